@@ -0,0 +1,178 @@
+// Package cli provides the command line interface to run the experiments registered within the experiments registry
+// (see experiment.Register). The third-party modules can build their own executor by importing the packages which
+// register their experiments and invoking Main:
+//
+//	import (
+//		"github.com/yaricom/goNEAT/v2/experiment/cli"
+//		_ "example.com/tasks/mytask" // registers the experiment in its init function
+//	)
+//
+//	func main() {
+//		if err := cli.Main(os.Args[1:]); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"io"
+	"os"
+	"strings"
+)
+
+// commands The subcommands of the executor
+var commands = []struct {
+	name        string
+	description string
+	execute     func(args []string) error
+}{
+	{"run", "Runs the experiment. The default command if no command name provided.", runCommand},
+	{"list", "Lists registered experiments.", func(_ []string) error { return listExperiments(os.Stdout) }},
+	{"describe", "Describes the registered experiment with its parameters.", describeCommand},
+	{"replay", "Loads the saved genome and re-evaluates it with the registered experiment.", replayCommand},
+	{"inspect", "Prints statistics of the genome or the population stored in plain or YAML file.", inspectCommand},
+	{"convert", "Translates the genome or the population between plain and YAML encodings.", convertCommand},
+	{"compare", "Prints the statistical comparison of the saved experiment results.", compareCommand},
+}
+
+// Main Parses provided command line arguments (without the program name) and executes the requested subcommand. If
+// the arguments start with the flag rather than the command name, the run command is executed.
+func Main(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runCommand(args)
+	}
+	name := args[0]
+	if name == "help" {
+		return usage(os.Stdout)
+	}
+	for _, c := range commands {
+		if c.name == name {
+			return c.execute(args[1:])
+		}
+	}
+	_ = usage(os.Stderr)
+	return errors.Errorf("unknown command: [%s]", name)
+}
+
+// usage Writes the list of supported commands
+func usage(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "Usage: executor <command> [flags] [arguments]\n\nCommands:"); err != nil {
+		return err
+	}
+	for _, c := range commands {
+		if _, err := fmt.Fprintf(w, "  %-10s %s\n", c.name, c.description); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "\nUse \"executor <command> -h\" for the command flags.")
+	return err
+}
+
+// runCommand Runs the experiment configured by the command line flags or by the experiment manifest
+func runCommand(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	var cfg runConfig
+	flags.StringVar(&cfg.outDirPath, "out", "./out", "The output directory to store results.")
+	flags.StringVar(&cfg.contextPath, "context", "./data/xor.neat", "The execution context configuration file.")
+	flags.StringVar(&cfg.genomePath, "genome", "./data/xorstartgenes", "The seed genome to start with.")
+	flags.StringVar(&cfg.experimentName, "experiment", "XOR", fmt.Sprintf("The name of experiment to run. [%s]",
+		strings.Join(registeredNames(), ", ")))
+	flags.IntVar(&cfg.trialsCount, "trials", 0, "The number of trials for experiment. Overrides the one set in configuration.")
+	flags.IntVar(&cfg.concurrentTrials, "concurrent_trials", 1, "The maximal number of trials to be executed concurrently.")
+	flags.StringVar(&cfg.logLevel, "log_level", "", "The logger level to be used. Overrides the one set in configuration.")
+	flags.BoolVar(&cfg.trialLogs, "trial_logs", false, "Write JSON logs of each trial into its output directory.")
+	flags.StringVar(&cfg.metricsAddr, "metrics_addr", "", "The address to serve live metrics at, e.g. :9090. Disabled if empty.")
+	flags.Var(&cfg.overrides, "set", "Overrides the NEAT option set in configuration, e.g. -set pop_size=150. Can be repeated.")
+	flags.StringVar(&cfg.manifestPath, "manifest", "", "The experiment manifest file. If set, the context, genome, experiment, out and trial_logs flags are ignored.")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	return run(&cfg)
+}
+
+// describeCommand Describes the registered experiment with name provided as the only argument
+func describeCommand(args []string) error {
+	if len(args) != 1 {
+		return errors.New("the experiment name expected")
+	}
+	return describeExperiment(os.Stdout, args[0])
+}
+
+// listExperiments Writes the names and descriptions of all registered experiments
+func listExperiments(w io.Writer) error {
+	for _, r := range experiment.Registered() {
+		if _, err := fmt.Fprintf(w, "%-24s %s\n", r.Name, r.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeExperiment Writes the description of the registered experiment with given name
+func describeExperiment(w io.Writer, name string) error {
+	r, ok := experiment.LookupRegistration(name)
+	if !ok {
+		return errors.Errorf("unsupported experiment: [%s], registered experiments: %s",
+			name, strings.Join(registeredNames(), ", "))
+	}
+	return r.Describe(w)
+}
+
+func registeredNames() []string {
+	registered := experiment.Registered()
+	names := make([]string, len(registered))
+	for i, r := range registered {
+		names[i] = r.Name
+	}
+	return names
+}
+
+// runConfig the configuration of the experiment run collected from the command line flags
+type runConfig struct {
+	outDirPath       string
+	contextPath      string
+	genomePath       string
+	experimentName   string
+	trialsCount      int
+	concurrentTrials int
+	logLevel         string
+	trialLogs        bool
+	metricsAddr      string
+	overrides        neat.OptionsOverrides
+	manifestPath     string
+}
+
+// manifest Returns the experiment manifest loaded from the file or built from the command line flags
+func (c *runConfig) manifest() (*experiment.Manifest, error) {
+	var manifest *experiment.Manifest
+	if len(c.manifestPath) > 0 {
+		var err error
+		if manifest, err = experiment.LoadManifestFile(c.manifestPath); err != nil {
+			return nil, err
+		}
+	} else {
+		manifest = &experiment.Manifest{
+			Name:      c.experimentName,
+			Evaluator: experiment.EvaluatorConfig{Name: c.experimentName},
+			Options:   experiment.OptionsConfig{Path: c.contextPath},
+			Genome:    experiment.GenomeConfig{Path: c.genomePath, Encoding: "plain"},
+			Output:    experiment.OutputConfig{Dir: c.outDirPath, TrialLogs: c.trialLogs},
+		}
+	}
+	if c.trialsCount > 0 {
+		manifest.Trials = c.trialsCount
+	}
+	if c.concurrentTrials > 1 {
+		manifest.ConcurrentTrials = c.concurrentTrials
+	}
+	return manifest, nil
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"testing"
+)
+
+func init() {
+	experiment.MustRegister(experiment.Registration{
+		Name:        "cli_test",
+		Description: "The CLI test experiment",
+		NewEvaluator: func(_ experiment.EvaluatorConfig, _ string) (experiment.GenerationEvaluator, error) {
+			return nil, nil
+		},
+	})
+}
+
+func TestListExperiments(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, listExperiments(&buf))
+	assert.Contains(t, buf.String(), "cli_test                 The CLI test experiment\n")
+}
+
+func TestDescribeExperiment(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, describeExperiment(&buf, "cli_test"))
+	assert.Contains(t, buf.String(), "The CLI test experiment")
+
+	err := describeExperiment(&buf, "unknown")
+	assert.EqualError(t, err, "unsupported experiment: [unknown], registered experiments: cli_test")
+}
+
+func TestRunConfig_manifest(t *testing.T) {
+	cfg := runConfig{experimentName: "cli_test", contextPath: "options.neat", genomePath: "genome",
+		outDirPath: "out", trialsCount: 3, concurrentTrials: 2}
+	manifest, err := cfg.manifest()
+	require.NoError(t, err)
+	assert.Equal(t, "cli_test", manifest.Evaluator.Name)
+	assert.Equal(t, "options.neat", manifest.Options.Path)
+	assert.Equal(t, "genome", manifest.Genome.Path)
+	assert.Equal(t, "out", manifest.OutputDir())
+	assert.Equal(t, 3, manifest.Trials)
+	assert.Equal(t, 2, manifest.ConcurrentTrials)
+
+	cfg = runConfig{manifestPath: "../../data/xor_experiment.yml"}
+	manifest, err = cfg.manifest()
+	require.NoError(t, err)
+	assert.Equal(t, "XOR", manifest.Name)
+	assert.Equal(t, 10, manifest.Trials)
+
+	cfg = runConfig{manifestPath: "missing.yml"}
+	_, err = cfg.manifest()
+	assert.Error(t, err)
+}
+
+func TestMain_invalidFlag(t *testing.T) {
+	assert.Error(t, Main([]string{"-unknown"}))
+}
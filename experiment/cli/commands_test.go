@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// nodesCountEvaluator The test evaluator setting the fitness of organism to the number of nodes in its genome
+type nodesCountEvaluator struct {
+	solvedNodes int
+}
+
+func (e nodesCountEvaluator) GenerationEvaluate(pop *genetics.Population, _ *experiment.Generation, _ *neat.Options) error {
+	for _, org := range pop.Organisms {
+		org.Fitness = float64(len(org.Genotype.Nodes))
+		org.Error = 1.0 / org.Fitness
+		org.IsWinner = len(org.Genotype.Nodes) >= e.solvedNodes
+	}
+	return nil
+}
+
+func TestMain_commands(t *testing.T) {
+	assert.NoError(t, Main([]string{"help"}))
+	assert.NoError(t, Main([]string{"list"}))
+	assert.NoError(t, Main([]string{"describe", "cli_test"}))
+	assert.Error(t, Main([]string{"describe"}))
+	assert.EqualError(t, Main([]string{"unknown"}), "unknown command: [unknown]")
+	assert.EqualError(t, Main([]string{"run", "extra"}), "unexpected arguments: extra")
+}
+
+func TestInspectGenome(t *testing.T) {
+	genomes, err := readGenomesFile(testPlainGenomePath, genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, inspectGenome(&buf, genomes[0]))
+	out := buf.String()
+	assert.Contains(t, out, "Genome #1\n")
+	assert.Contains(t, out, "Nodes:       4 (inputs: 2, bias: 1, hidden: 0, outputs: 1)")
+	assert.Contains(t, out, "Genes:       3 (enabled: 3, disabled: 0, recurrent: 0)")
+	assert.Contains(t, out, "Network:")
+}
+
+func TestInspectPopulation(t *testing.T) {
+	genomes, err := readGenomesFile(testPlainGenomePath, genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	yamlGenomes, err := readGenomesFile(testYAMLGenomePath, genetics.YAMLGenomeEncoding)
+	require.NoError(t, err)
+	genomes = append(genomes, yamlGenomes...)
+
+	opts := neat.DefaultOptions()
+	pop, err := genetics.NewPopulationFromGenomes(genomes, opts)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, inspectPopulation(&buf, pop, opts))
+	out := buf.String()
+	assert.Contains(t, out, "Organisms:   2\n")
+	assert.Contains(t, out, "Species:     2 (compat threshold: 3, method: linear)")
+	assert.Contains(t, out, "Nodes:       min: 4")
+	assert.Contains(t, out, "1 organisms\n")
+}
+
+func TestInspectCommand(t *testing.T) {
+	assert.NoError(t, inspectCommand([]string{testYAMLGenomePath}))
+	assert.Error(t, inspectCommand([]string{}))
+	assert.Error(t, inspectCommand([]string{"-encoding", "json", testYAMLGenomePath}))
+}
+
+func TestConvertCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	// plain -> yaml -> plain
+	yamlPath := filepath.Join(dir, "genome.yml")
+	require.NoError(t, convertCommand([]string{testPlainGenomePath, yamlPath}))
+	plainPath := filepath.Join(dir, "genome")
+	require.NoError(t, convertCommand([]string{"-from", "yaml", "-to", "plain", yamlPath, plainPath}))
+
+	expected, err := ioutil.ReadFile(testPlainGenomePath)
+	require.NoError(t, err)
+	expectedGenomes, err := readGenomes(bytes.NewReader(expected), genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	converted, err := readGenomesFile(plainPath, genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	require.Len(t, converted, 1)
+	equal, err := converted[0].IsEqual(expectedGenomes[0])
+	assert.True(t, equal, err)
+
+	// MIMO modules can not be stored in plain encoding
+	assert.Error(t, convertCommand([]string{testYAMLGenomePath, plainPath}))
+	assert.Error(t, convertCommand([]string{testPlainGenomePath}))
+}
+
+func TestCompareCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compare")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	genomes, err := readGenomesFile(testPlainGenomePath, genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	paths := make([]string, 0)
+	for i, fitness := range [][]float64{{10, 12, 11, 13, 12}, {14, 15, 16, 15, 14}} {
+		exp := experiment.Experiment{Name: "XOR", Trials: make(experiment.Trials, len(fitness))}
+		for j, f := range fitness {
+			org, err := genetics.NewOrganism(f, genomes[0], 1)
+			require.NoError(t, err)
+			gen := experiment.Generation{Id: 1, Best: org, Fitness: experiment.Floats{f}, Solved: j%2 == 0,
+				WinnerEvals: 100 * (j + 1)}
+			exp.Trials[j] = experiment.Trial{Id: j, Generations: experiment.Generations{gen}}
+		}
+		path := filepath.Join(dir, "xor"+string(rune('a'+i))+"."+experiment.ExportFormatNative)
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		require.NoError(t, exp.Write(file))
+		require.NoError(t, file.Close())
+		paths = append(paths, path)
+	}
+
+	assert.NoError(t, compareCommand(paths))
+	assert.Error(t, compareCommand(paths[:1]))
+	assert.Error(t, compareCommand([]string{paths[0], filepath.Join(dir, "missing.dat")}))
+}
+
+func TestReplay(t *testing.T) {
+	genomes, err := readGenomesFile(testPlainGenomePath, genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	yamlGenomes, err := readGenomesFile(testYAMLGenomePath, genetics.YAMLGenomeEncoding)
+	require.NoError(t, err)
+	genomes = append(genomes, yamlGenomes...)
+
+	ctx := neat.NewContext(context.Background(), neat.DefaultOptions())
+	results, err := replay(ctx, nodesCountEvaluator{solvedNodes: 5}, genomes, 3)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, experiment.Floats{4, 4, 4}, results[0].fitness)
+	assert.Equal(t, 0, results[0].winners)
+	assert.Equal(t, 3, results[1].winners)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeReplayResults(&buf, results, 3))
+	assert.Contains(t, buf.String(), "Genome #1\tfitness: 4 (min: 4, max: 4)\terror: 0.25\tsolved: 0 from 3\n")
+
+	_, err = replay(context.Background(), nodesCountEvaluator{}, genomes, 1)
+	assert.Equal(t, neat.ErrNEATOptionsNotFound, err)
+}
+
+func TestReplayCommand_errors(t *testing.T) {
+	assert.Error(t, replayCommand([]string{}))
+	assert.Error(t, replayCommand([]string{"-repeat", "0", testPlainGenomePath}))
+	assert.EqualError(t, replayCommand([]string{testPlainGenomePath}),
+		"the experiment to evaluate genome with must be set either by manifest or by flag")
+	assert.Error(t, replayCommand([]string{"-param", "invalid", testPlainGenomePath}))
+}
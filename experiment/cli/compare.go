@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// compareCommand Prints the statistical comparison of the saved experiment results
+func compareCommand(args []string) error {
+	opts := experiment.DefaultComparisonOptions()
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.Float64Var(&opts.Alpha, "alpha", opts.Alpha, "The significance level of the statistical tests.")
+	flags.Float64Var(&opts.ConfidenceLevel, "confidence", opts.ConfidenceLevel, "The confidence level of the bootstrap confidence intervals.")
+	flags.IntVar(&opts.BootstrapResamples, "resamples", opts.BootstrapResamples, "The number of bootstrap resamples.")
+	flags.Int64Var(&opts.Seed, "seed", opts.Seed, "The seed of the random number generator used for bootstrap resampling.")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(flags.Output(), "Usage: compare [flags] <results file> <results file> [results file...]")
+		_, _ = fmt.Fprintln(flags.Output(), "The results files are either in native (.dat) or JSON (.json) format.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return errors.New("at least two results files expected")
+	}
+
+	experiments := make([]*experiment.Experiment, flags.NArg())
+	for i, path := range flags.Args() {
+		var err error
+		if experiments[i], err = readExperiment(path); err != nil {
+			return err
+		}
+	}
+	// distinguish the results of the same experiment by the files names
+	names := make(map[string]int, len(experiments))
+	for _, e := range experiments {
+		names[e.Name]++
+	}
+	for i, e := range experiments {
+		if names[e.Name] > 1 {
+			e.Name = fmt.Sprintf("%s (%s)", e.Name, flags.Arg(i))
+		}
+	}
+
+	report, err := experiment.CompareExperiments(experiments, opts)
+	if err != nil {
+		return err
+	}
+	return report.Write(os.Stdout)
+}
+
+// readExperiment Reads the experiment results from the file in the native (.dat) or JSON (.json) format. The name of
+// experiment is set to the file name if not stored in results.
+func readExperiment(path string) (*experiment.Experiment, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	exp := &experiment.Experiment{}
+	if strings.ToLower(filepath.Ext(path)) == "."+experiment.ExportFormatJSON {
+		err = exp.ReadJSON(file)
+	} else {
+		err = exp.Read(file)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read experiment results: [%s]", path)
+	}
+	if len(exp.Name) == 0 {
+		exp.Name = filepath.Base(path)
+	}
+	return exp, nil
+}
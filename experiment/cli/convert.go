@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"os"
+)
+
+// convertCommand Translates the genome or the population between the plain text and YAML encodings
+func convertCommand(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fromName := flags.String("from", "", "The encoding of the input file [plain, yaml]. Detected by the file extension if empty.")
+	toName := flags.String("to", "", "The encoding of the output file [plain, yaml]. Detected by the file extension if empty.")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(flags.Output(), "Usage: convert [flags] <input file> <output file>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.New("the input and output files expected")
+	}
+	inPath, outPath := flags.Arg(0), flags.Arg(1)
+	from, err := experiment.ResolveGenomeEncoding(*fromName, inPath)
+	if err != nil {
+		return err
+	}
+	to, err := experiment.ResolveGenomeEncoding(*toName, outPath)
+	if err != nil {
+		return err
+	}
+	genomes, err := readGenomesFile(inPath, from)
+	if err != nil {
+		return err
+	}
+
+	outFile, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if err = writeGenomes(outFile, genomes, to); err != nil {
+		_ = outFile.Close()
+		return errors.Wrapf(err, "failed to write genomes into: [%s]", outPath)
+	}
+	if err = outFile.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Converted %d genome(s) from [%s] into [%s]\n", len(genomes), inPath, outPath)
+	return nil
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"strings"
+)
+
+// readGenomesFile Reads all genomes stored in the file with given path using provided encoding
+func readGenomesFile(path string, encoding genetics.GenomeEncoding) ([]*genetics.Genome, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	genomes, err := readGenomes(file, encoding)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read genomes from: [%s]", path)
+	}
+	return genomes, nil
+}
+
+// readGenomes Reads all genomes from provided reader. The plain text genomes are delimited by genomestart and genomeend
+// lines, as written by Population.Write. The YAML genomes are stored as separate documents of the YAML stream.
+func readGenomes(r io.Reader, encoding genetics.GenomeEncoding) ([]*genetics.Genome, error) {
+	var records [][]byte
+	switch encoding {
+	case genetics.PlainGenomeEncoding:
+		var record *bytes.Buffer
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if strings.HasPrefix(line, "genomestart") {
+				record = &bytes.Buffer{}
+			}
+			if record == nil {
+				// skip comments between genomes
+				continue
+			}
+			record.WriteString(line)
+			record.WriteByte('\n')
+			if strings.HasPrefix(line, "genomeend") {
+				records = append(records, record.Bytes())
+				record = nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		if record != nil {
+			return nil, errors.New("unexpected end of genome data, genomeend not found")
+		}
+	case genetics.YAMLGenomeEncoding:
+		dec := yaml.NewDecoder(r)
+		for {
+			var doc yaml.Node
+			if err := dec.Decode(&doc); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			data, err := yaml.Marshal(&doc)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, data)
+		}
+	default:
+		return nil, genetics.ErrUnsupportedGenomeEncoding
+	}
+
+	genomes := make([]*genetics.Genome, len(records))
+	for i, record := range records {
+		reader, err := genetics.NewGenomeReader(bytes.NewReader(record), encoding)
+		if err != nil {
+			return nil, err
+		}
+		if genomes[i], err = reader.Read(); err != nil {
+			return nil, errors.Wrapf(err, "failed to read genome #%d", i)
+		}
+	}
+	if len(genomes) == 0 {
+		return nil, errors.New("no genomes found")
+	}
+	return genomes, nil
+}
+
+// writeGenomes Writes all provided genomes into the writer using given encoding. The YAML genomes are written as
+// separate documents of the YAML stream. The genomes with MIMO modules can be written only in YAML encoding.
+func writeGenomes(w io.Writer, genomes []*genetics.Genome, encoding genetics.GenomeEncoding) error {
+	for i, genome := range genomes {
+		if encoding == genetics.PlainGenomeEncoding && len(genome.ControlGenes) > 0 {
+			return errors.Errorf("genome #%d has MIMO modules which can not be stored in plain encoding", genome.Id)
+		}
+		if encoding == genetics.YAMLGenomeEncoding && i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		writer, err := genetics.NewGenomeWriter(w, encoding)
+		if err != nil {
+			return err
+		}
+		if err = writer.WriteGenome(genome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"strings"
+	"testing"
+)
+
+const (
+	testPlainGenomePath = "../../data/xorstartgenes"
+	testYAMLGenomePath  = "../../data/test_seed_genome.yml"
+)
+
+func TestReadWriteGenomes_roundTrip(t *testing.T) {
+	for _, path := range []string{testPlainGenomePath, testYAMLGenomePath} {
+		encoding, err := experiment.ResolveGenomeEncoding("", path)
+		require.NoError(t, err)
+		genomes, err := readGenomesFile(path, encoding)
+		require.NoError(t, err, path)
+		require.Len(t, genomes, 1)
+
+		for _, to := range []genetics.GenomeEncoding{genetics.PlainGenomeEncoding, genetics.YAMLGenomeEncoding} {
+			var buf bytes.Buffer
+			err = writeGenomes(&buf, []*genetics.Genome{genomes[0], genomes[0]}, to)
+			if to == genetics.PlainGenomeEncoding && len(genomes[0].ControlGenes) > 0 {
+				assert.EqualError(t, err, "genome #26045 has MIMO modules which can not be stored in plain encoding")
+				continue
+			}
+			require.NoError(t, err)
+			decoded, err := readGenomes(&buf, to)
+			require.NoError(t, err, path)
+			require.Len(t, decoded, 2)
+			for _, g := range decoded {
+				equal, err := g.IsEqual(genomes[0])
+				assert.True(t, equal, "%s: %v", path, err)
+			}
+		}
+	}
+}
+
+func TestReadGenomes_errors(t *testing.T) {
+	_, err := readGenomes(strings.NewReader("/* no genomes */\n"), genetics.PlainGenomeEncoding)
+	assert.EqualError(t, err, "no genomes found")
+
+	_, err = readGenomes(strings.NewReader("genomestart 1\ntrait 1 0.1 0 0 0 0 0 0 0\n"), genetics.PlainGenomeEncoding)
+	assert.EqualError(t, err, "unexpected end of genome data, genomeend not found")
+
+	_, err = readGenomesFile("missing", genetics.PlainGenomeEncoding)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"io"
+	"os"
+	"sort"
+)
+
+// inspectCommand Prints statistics of the genome or the population stored in plain or YAML file
+func inspectCommand(args []string) error {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	encodingName := flags.String("encoding", "", "The encoding of the file [plain, yaml]. Detected by the file extension if empty.")
+	contextPath := flags.String("context", "", "The NEAT options file used to speciate population. The default options used if empty.")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(flags.Output(), "Usage: inspect [flags] <genome or population file>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("the genome or population file expected")
+	}
+	path := flags.Arg(0)
+	encoding, err := experiment.ResolveGenomeEncoding(*encodingName, path)
+	if err != nil {
+		return err
+	}
+	genomes, err := readGenomesFile(path, encoding)
+	if err != nil {
+		return err
+	}
+	if len(genomes) == 1 {
+		return inspectGenome(os.Stdout, genomes[0])
+	}
+
+	opts, err := loadOptions(*contextPath)
+	if err != nil {
+		return err
+	}
+	pop, err := genetics.NewPopulationFromGenomes(genomes, opts)
+	if err != nil {
+		return errors.Wrap(err, "failed to create population")
+	}
+	return inspectPopulation(os.Stdout, pop, opts)
+}
+
+// inspectGenome Writes the statistics of the genome and its phenotype network
+func inspectGenome(w io.Writer, genome *genetics.Genome) error {
+	neurons := make(map[network.NodeNeuronType]int)
+	for _, node := range genome.Nodes {
+		neurons[node.NeuronType]++
+	}
+	var enabled, recurrent int
+	for _, gene := range genome.Genes {
+		if gene.IsEnabled {
+			enabled++
+		}
+		if gene.Link.IsRecurrent {
+			recurrent++
+		}
+	}
+	if _, err := fmt.Fprintf(w, "Genome #%d\n\t%-12s %d\n\t%-12s %d (inputs: %d, bias: %d, hidden: %d, outputs: %d)\n"+
+		"\t%-12s %d (enabled: %d, disabled: %d, recurrent: %d)\n\t%-12s %d\n",
+		genome.Id, "Traits:", len(genome.Traits), "Nodes:", len(genome.Nodes), neurons[network.InputNeuron],
+		neurons[network.BiasNeuron], neurons[network.HiddenNeuron], neurons[network.OutputNeuron],
+		"Genes:", len(genome.Genes), enabled, len(genome.Genes)-enabled, recurrent, "Modules:", len(genome.ControlGenes)); err != nil {
+		return err
+	}
+	net, err := genome.Genesis(genome.Id)
+	if err != nil {
+		return errors.Wrap(err, "failed to build phenotype network")
+	}
+	_, err = fmt.Fprintf(w, "\t%-12s %s\n", "Network:", net.Analyze())
+	return err
+}
+
+// inspectPopulation Writes the statistics of the population of organisms and its species
+func inspectPopulation(w io.Writer, pop *genetics.Population, opts *neat.Options) error {
+	var nodes, genes, complexity experiment.Floats
+	for _, org := range pop.Organisms {
+		nodes = append(nodes, float64(len(org.Genotype.Nodes)))
+		genes = append(genes, float64(org.Genotype.Extrons()))
+		complexity = append(complexity, float64(org.Phenotype.Complexity()))
+	}
+	if _, err := fmt.Fprintf(w, "Population\n\t%-12s %d\n\t%-12s %d (compat threshold: %g, method: %s)\n",
+		"Organisms:", len(pop.Organisms), "Species:", len(pop.Species), opts.CompatThreshold, opts.GenCompatMethod); err != nil {
+		return err
+	}
+	stats := []struct {
+		name   string
+		values experiment.Floats
+	}{{"Nodes", nodes}, {"Genes", genes}, {"Complexity", complexity}}
+	for _, s := range stats {
+		if _, err := fmt.Fprintf(w, "\t%-12s min: %.0f, mean: %.2f, max: %.0f\n",
+			s.name+":", s.values.Min(), s.values.Mean(), s.values.Max()); err != nil {
+			return err
+		}
+	}
+	species := make([]*genetics.Species, len(pop.Species))
+	copy(species, pop.Species)
+	sort.Slice(species, func(i, j int) bool {
+		return len(species[i].Organisms) > len(species[j].Organisms)
+	})
+	for _, s := range species {
+		if _, err := fmt.Fprintf(w, "\t%-12s %d organisms\n", fmt.Sprintf("Species #%d:", s.Id), len(s.Organisms)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadOptions Loads the NEAT options from the file with given path or returns default options if path is empty
+func loadOptions(path string) (*neat.Options, error) {
+	if len(path) == 0 {
+		return neat.DefaultOptions(), nil
+	}
+	manifest := experiment.Manifest{Options: experiment.OptionsConfig{Path: path}}
+	return manifest.LoadOptions()
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io"
+	"os"
+	"strings"
+)
+
+// evaluatorParameters The list of the evaluator parameters in key=value format, which can be used as command line flag
+type evaluatorParameters map[string]interface{}
+
+func (p evaluatorParameters) String() string {
+	pairs := make([]string, 0, len(p))
+	for k, v := range p {
+		pairs = append(pairs, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Set Stores the parameter value. Implements flag.Value interface.
+func (p evaluatorParameters) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("parameter must be in key=value format: [%s]", value)
+	}
+	p[strings.TrimSpace(parts[0])] = parts[1]
+	return nil
+}
+
+// replayResult The results of repeated evaluation of one genome
+type replayResult struct {
+	genome  *genetics.Genome
+	fitness experiment.Floats
+	errors  experiment.Floats
+	winners int
+}
+
+// replayCommand Loads the saved genome and re-evaluates it with the evaluator of the registered experiment
+func replayCommand(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	experimentName := flags.String("experiment", "", "The name of the registered experiment to evaluate genome with. Overrides the one set in manifest.")
+	manifestPath := flags.String("manifest", "", "The experiment manifest file to take the evaluator and the NEAT options from.")
+	contextPath := flags.String("context", "", "The NEAT options file. Overrides the options set in manifest. The default options used if both empty.")
+	encodingName := flags.String("encoding", "", "The encoding of the genome file [plain, yaml]. Detected by the file extension if empty.")
+	repeat := flags.Int("repeat", 1, "The number of times to evaluate the genome.")
+	outDir := flags.String("out", "", "The output directory to store results of evaluation. Nothing is stored if empty.")
+	params := make(evaluatorParameters)
+	flags.Var(params, "param", "Sets the evaluator parameter, e.g. -param win_balance_steps=1000. Can be repeated.")
+	var overrides neat.OptionsOverrides
+	flags.Var(&overrides, "set", "Overrides the NEAT option, e.g. -set log_level=warn. Can be repeated.")
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(flags.Output(), "Usage: replay [flags] <genome file>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("the genome file expected")
+	}
+	if *repeat < 1 {
+		return errors.Errorf("the number of repeats must be positive: %d", *repeat)
+	}
+
+	// collect evaluator configuration and options
+	var config experiment.EvaluatorConfig
+	var opts *neat.Options
+	var err error
+	if len(*manifestPath) > 0 {
+		manifest, err := experiment.LoadManifestFile(*manifestPath)
+		if err != nil {
+			return err
+		}
+		config = manifest.Evaluator
+		if len(*contextPath) == 0 {
+			if opts, err = manifest.LoadOptions(); err != nil {
+				return err
+			}
+		}
+	}
+	if opts == nil {
+		if opts, err = loadOptions(*contextPath); err != nil {
+			return err
+		}
+	}
+	if err = opts.SetFromFlags(overrides); err != nil {
+		return err
+	}
+	if err = opts.Validate(); err != nil {
+		return err
+	}
+	if err = neat.InitLogger(opts.LogLevel); err != nil {
+		return err
+	}
+	if len(*experimentName) > 0 && *experimentName != config.Name {
+		config = experiment.EvaluatorConfig{Name: *experimentName}
+	}
+	if len(config.Name) == 0 {
+		return errors.New("the experiment to evaluate genome with must be set either by manifest or by flag")
+	}
+	if len(params) > 0 {
+		merged := make(map[string]interface{}, len(config.Parameters)+len(params))
+		for k, v := range config.Parameters {
+			merged[k] = v
+		}
+		for k, v := range params {
+			merged[k] = v
+		}
+		config.Parameters = merged
+	}
+
+	// load genomes and evaluate
+	path := flags.Arg(0)
+	encoding, err := experiment.ResolveGenomeEncoding(*encodingName, path)
+	if err != nil {
+		return err
+	}
+	genomes, err := readGenomesFile(path, encoding)
+	if err != nil {
+		return err
+	}
+	evaluator, _, err := experiment.NewRegisteredEvaluator(config, *outDir)
+	if err != nil {
+		return err
+	}
+	results, err := replay(neat.NewContext(context.Background(), opts), evaluator, genomes, *repeat)
+	if err != nil {
+		return err
+	}
+	return writeReplayResults(os.Stdout, results, *repeat)
+}
+
+// replay Evaluates provided genomes with the evaluator given number of times. Each time the new population is created
+// from genomes to start evaluation from scratch.
+func replay(ctx context.Context, evaluator experiment.GenerationEvaluator, genomes []*genetics.Genome, repeat int) ([]*replayResult, error) {
+	opts, found := neat.FromContext(ctx)
+	if !found {
+		return nil, neat.ErrNEATOptionsNotFound
+	}
+	results := make([]*replayResult, len(genomes))
+	for i, genome := range genomes {
+		results[i] = &replayResult{genome: genome}
+	}
+	contextEvaluator := experiment.NewContextGenerationEvaluator(evaluator)
+	for r := 0; r < repeat; r++ {
+		pop, err := genetics.NewPopulationFromGenomes(genomes, opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create population")
+		}
+		epoch := &experiment.Generation{Id: r}
+		if err = contextEvaluator.GenerationEvaluateContext(ctx, pop, epoch); err != nil {
+			return nil, errors.Wrap(err, "failed to evaluate genomes")
+		}
+		for i, org := range pop.Organisms {
+			results[i].fitness = append(results[i].fitness, org.Fitness)
+			results[i].errors = append(results[i].errors, org.Error)
+			if org.IsWinner {
+				results[i].winners++
+			}
+		}
+	}
+	return results, nil
+}
+
+// writeReplayResults Writes the results of genomes evaluation
+func writeReplayResults(w io.Writer, results []*replayResult, repeat int) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintf(w, "Genome #%d\tfitness: %g (min: %g, max: %g)\terror: %g\tsolved: %d from %d\n",
+			r.genome.Id, r.fitness.Mean(), r.fitness.Min(), r.fitness.Max(), r.errors.Mean(), r.winners, repeat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// run Runs the experiment described by provided configuration
+func run(cfg *runConfig) error {
+	manifest, err := cfg.manifest()
+	if err != nil {
+		return errors.Wrap(err, "failed to load experiment manifest")
+	}
+
+	// Seed the random-number generator with current time so that
+	// the numbers will be different every time we run.
+	seed := manifest.Seed
+	if seed == 0 {
+		seed = time.Now().Unix()
+	}
+	rand.Seed(seed)
+
+	// Load neatOptions configuration
+	neatOptions, err := manifest.LoadOptions()
+	if err != nil {
+		return errors.Wrap(err, "failed to load NEAT options")
+	}
+
+	// Load Genome
+	log.Printf("Loading start genome for %s experiment\n", manifest.Name)
+	startGenome, err := manifest.LoadStartGenome()
+	if err != nil {
+		return errors.Wrap(err, "failed to read start genome")
+	}
+	fmt.Println(startGenome)
+
+	// Check if output dir exists
+	outDir := manifest.OutputDir()
+	if _, err = os.Stat(outDir); err == nil && !manifest.Output.Overwrite {
+		// backup it
+		backUpDir := fmt.Sprintf("%s-%s", outDir, time.Now().Format("2006-01-02T15_04_05"))
+		// clear it
+		if err = os.Rename(outDir, backUpDir); err != nil {
+			return errors.Wrap(err, "failed to do previous results backup")
+		}
+	}
+	// create output dir
+	if err = os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "failed to create output directory")
+	}
+
+	// Override neatOptions configuration parameters with ones set from environment (NEAT_<OPTION>) and command line
+	if err = neatOptions.SetFromEnvironment("NEAT_"); err != nil {
+		return errors.Wrap(err, "failed to override NEAT options")
+	}
+	if err = neatOptions.SetFromFlags(cfg.overrides); err != nil {
+		return errors.Wrap(err, "failed to override NEAT options")
+	}
+	if err = neatOptions.Validate(); err != nil {
+		return err
+	}
+	if err = neat.InitLogger(neatOptions.LogLevel); err != nil {
+		return errors.Wrap(err, "failed to initialize logger")
+	}
+	if len(cfg.logLevel) > 0 {
+		neat.LogLevel = neat.LoggerLevel(cfg.logLevel)
+	}
+
+	// keep record of the experiment configuration next to its results
+	if err = manifest.WriteRecord(outDir, neatOptions, startGenome, seed); err != nil {
+		return errors.Wrap(err, "failed to write experiment configuration")
+	}
+
+	// create the generation evaluator of the registered experiment
+	generationEvaluator, maxFitnessScore, err := experiment.NewRegisteredEvaluator(manifest.Evaluator, outDir)
+	if err != nil {
+		return err
+	}
+	if manifest.MaxFitnessScore > 0 {
+		maxFitnessScore = manifest.MaxFitnessScore
+	}
+
+	// create experiment
+	expt := experiment.Experiment{
+		Id:                  0,
+		Trials:              make(experiment.Trials, neatOptions.NumRuns),
+		Name:                manifest.Name,
+		RandSeed:            seed,
+		MaxFitnessScore:     maxFitnessScore,
+		MaxConcurrentTrials: manifest.ConcurrentTrials,
+		AnalyzeChampions:    manifest.AnalyzeChampions,
+	}
+
+	// log experiment's events
+	eventsObserver, err := experiment.NewJSONLEventObserver(outDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to create events log")
+	}
+	defer func() {
+		_ = eventsObserver.Close()
+	}()
+	expt.Events = experiment.NewEventBus(eventsObserver)
+
+	// write logs of each trial into separate file if requested
+	if manifest.Output.TrialLogs {
+		trialHandler := neat.NewTrialFileHandler(outDir, neat.LogLevel.Level(), neat.NewJSONHandler)
+		defer func() {
+			_ = trialHandler.Close()
+		}()
+		neat.SetDefaultLogger(neat.NewLogger(neat.NewMultiHandler(neat.DefaultLogger().Handler(), trialHandler)))
+	}
+
+	// serve live metrics if requested
+	if len(cfg.metricsAddr) > 0 {
+		collector := experiment.NewMetricsCollector()
+		expt.Events.Subscribe(collector)
+		metricsServer := experiment.NewMetricsServer(cfg.metricsAddr, collector)
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Metrics server failed: %s", err)
+			}
+		}()
+		defer func() {
+			_ = metricsServer.Close()
+		}()
+		fmt.Printf(">>> Serving metrics at: %s%s\n", cfg.metricsAddr, experiment.MetricsPath)
+	}
+
+	// run experiment until completion or termination signal
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		fmt.Println("\nPress Ctrl+C to stop")
+
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+		defer signal.Stop(signals)
+		select {
+		case <-signals:
+			// signal to stop test fixture
+			cancel()
+		case <-ctx.Done():
+			// stop waiting
+		}
+	}()
+	if err = expt.Execute(neat.NewContext(ctx, neatOptions), startGenome, generationEvaluator, nil); err != nil {
+		return errors.Wrap(err, "experiment execution failed")
+	}
+
+	// Print experiment results statistics
+	//
+	expt.PrintStatistics()
+
+	fmt.Printf(">>> Experiment configuration: %s\n", filepath.Join(outDir, experiment.ManifestFileName))
+
+	// Save experiment data in requested formats
+	//
+	return expt.WriteFiles(outDir, manifest.Name, manifest.OutputFormats())
+}
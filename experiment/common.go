@@ -2,6 +2,7 @@
 package experiment
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/yaricom/goNEAT/v2/neat"
@@ -17,6 +18,42 @@ type GenerationEvaluator interface {
 	GenerationEvaluate(pop *genetics.Population, epoch *Generation, context *neat.Options) (err error)
 }
 
+// ContextGenerationEvaluator the context-aware variant of GenerationEvaluator. The provided context holds the NEAT
+// options (see neat.FromContext) and can be used to interrupt evaluation of the generation, e.g. on user request.
+type ContextGenerationEvaluator interface {
+	// GenerationEvaluateContext Invoked to evaluate one generation of population of organisms within given
+	// execution context. Returns the context error if evaluation was interrupted.
+	GenerationEvaluateContext(ctx context.Context, pop *genetics.Population, epoch *Generation) error
+}
+
+// NewContextGenerationEvaluator Adapts provided GenerationEvaluator to the ContextGenerationEvaluator interface. If
+// evaluator already implements ContextGenerationEvaluator it is returned as is. Otherwise, the context is checked for
+// cancellation before and after the generation evaluation, because ordinary evaluator can not be interrupted.
+func NewContextGenerationEvaluator(evaluator GenerationEvaluator) ContextGenerationEvaluator {
+	if ce, ok := evaluator.(ContextGenerationEvaluator); ok {
+		return ce
+	}
+	return &contextGenerationEvaluatorAdapter{evaluator: evaluator}
+}
+
+type contextGenerationEvaluatorAdapter struct {
+	evaluator GenerationEvaluator
+}
+
+func (a *contextGenerationEvaluatorAdapter) GenerationEvaluateContext(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	opts, found := neat.FromContext(ctx)
+	if !found {
+		return neat.ErrNEATOptionsNotFound
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := a.evaluator.GenerationEvaluate(pop, epoch, opts); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
 // TrialRunObserver defines observer to be notified about experiment's trial lifecycle methods
 type TrialRunObserver interface {
 	// TrialRunStarted invoked to notify that new trial run just started. Invoked before any epoch evaluation in that trial run
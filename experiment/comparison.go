@@ -0,0 +1,441 @@
+package experiment
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// StatisticalTest the result of the two-sided statistical hypothesis test
+type StatisticalTest struct {
+	// The value of the test statistic
+	Statistic float64
+	// The degrees of freedom of the test statistic distribution, if applicable
+	DegreesOfFreedom float64
+	// The two-sided p-value of the test. It is NaN when samples are too small to perform the test.
+	PValue float64
+}
+
+// ConfidenceInterval the confidence interval of the sample statistic
+type ConfidenceInterval struct {
+	// The value of the statistic estimated on the original sample
+	Estimate float64
+	// The lower bound of the interval
+	Lower float64
+	// The upper bound of the interval
+	Upper float64
+	// The confidence level of the interval, e.g. 0.95
+	Level float64
+}
+
+// MannWhitneyU Performs the Mann-Whitney U test (Wilcoxon rank-sum test) of the null hypothesis that samples x and y are
+// drawn from the same distribution. The returned statistic is the U value of the sample x, and the p-value is estimated
+// using the normal approximation with the tie and continuity corrections.
+func MannWhitneyU(x, y Floats) StatisticalTest {
+	n1, n2 := float64(len(x)), float64(len(y))
+	if n1 == 0 || n2 == 0 {
+		return StatisticalTest{Statistic: math.NaN(), PValue: math.NaN()}
+	}
+	ranks, ties := rankSamples(x, y)
+	rankSum := 0.0
+	for i := range x {
+		rankSum += ranks[i]
+	}
+	u := rankSum - n1*(n1+1)/2
+
+	n := n1 + n2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+	if variance <= 0 {
+		// all values are equal
+		return StatisticalTest{Statistic: u, PValue: 1}
+	}
+	diff := u - mean
+	if diff > 0 {
+		diff = math.Max(diff-0.5, 0)
+	} else {
+		diff = math.Min(diff+0.5, 0)
+	}
+	z := diff / math.Sqrt(variance)
+	return StatisticalTest{Statistic: u, PValue: math.Min(1, math.Erfc(math.Abs(z)/math.Sqrt2))}
+}
+
+// rankSamples Returns the ranks of values of both samples in the order of concatenation, with tied values getting the
+// average rank, and the sum of t^3-t over all groups of t tied values.
+func rankSamples(x, y Floats) ([]float64, float64) {
+	values := make([]float64, 0, len(x)+len(y))
+	values = append(values, x...)
+	values = append(values, y...)
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return values[indices[i]] < values[indices[j]]
+	})
+	ranks := make([]float64, len(values))
+	ties := 0.0
+	for i := 0; i < len(indices); {
+		j := i + 1
+		for j < len(indices) && values[indices[j]] == values[indices[i]] {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			ranks[indices[k]] = rank
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	return ranks, ties
+}
+
+// WelchTTest Performs the Welch's t-test of the null hypothesis that samples x and y have equal means without assuming
+// equal variances. The returned statistic is the t value of the difference mean(x) - mean(y).
+func WelchTTest(x, y Floats) StatisticalTest {
+	if len(x) < 2 || len(y) < 2 {
+		return StatisticalTest{Statistic: math.NaN(), DegreesOfFreedom: math.NaN(), PValue: math.NaN()}
+	}
+	n1, n2 := float64(len(x)), float64(len(y))
+	m1, v1 := stat.MeanVariance(x, nil)
+	m2, v2 := stat.MeanVariance(y, nil)
+	s1, s2 := v1/n1, v2/n2
+	se := s1 + s2
+	if se == 0 {
+		// both samples are constant
+		if m1 == m2 {
+			return StatisticalTest{Statistic: 0, DegreesOfFreedom: n1 + n2 - 2, PValue: 1}
+		}
+		return StatisticalTest{Statistic: math.Copysign(math.Inf(1), m1-m2), DegreesOfFreedom: n1 + n2 - 2, PValue: 0}
+	}
+	t := (m1 - m2) / math.Sqrt(se)
+	df := se * se / (s1*s1/(n1-1) + s2*s2/(n2-1))
+	p := mathext.RegIncBeta(df/2, 0.5, df/(df+t*t))
+	return StatisticalTest{Statistic: t, DegreesOfFreedom: df, PValue: p}
+}
+
+// VarghaDelaneyA12 Calculates the Vargha-Delaney A12 effect size, i.e. the probability that a value randomly drawn
+// from the sample x is greater than a value randomly drawn from the sample y, with ties counted as half.
+func VarghaDelaneyA12(x, y Floats) float64 {
+	if len(x) == 0 || len(y) == 0 {
+		return math.NaN()
+	}
+	greater := 0.0
+	for _, xv := range x {
+		for _, yv := range y {
+			if xv > yv {
+				greater++
+			} else if xv == yv {
+				greater += 0.5
+			}
+		}
+	}
+	return greater / float64(len(x)*len(y))
+}
+
+// A12Magnitude Returns the magnitude of the Vargha-Delaney A12 effect size: negligible, small, medium or large,
+// following the thresholds proposed by Vargha and Delaney.
+func A12Magnitude(a12 float64) string {
+	d := math.Abs(a12 - 0.5)
+	switch {
+	case math.IsNaN(d):
+		return "n/a"
+	case d < 0.06:
+		return "negligible"
+	case d < 0.14:
+		return "small"
+	case d < 0.21:
+		return "medium"
+	default:
+		return "large"
+	}
+}
+
+// FisherExactTest Performs the two-sided Fisher's exact test of the null hypothesis that success rates of two
+// experiments are equal. The successesX out of totalX trials are compared with successesY out of totalY trials.
+func FisherExactTest(successesX, totalX, successesY, totalY int) float64 {
+	if totalX <= 0 || totalY <= 0 || successesX < 0 || successesY < 0 || successesX > totalX || successesY > totalY {
+		return math.NaN()
+	}
+	successes := successesX + successesY
+	total := totalX + totalY
+	logP := func(k int) float64 {
+		return logChoose(totalX, k) + logChoose(totalY, successes-k) - logChoose(total, successes)
+	}
+	observed := logP(successesX)
+	minK, maxK := successes-totalY, successes
+	if minK < 0 {
+		minK = 0
+	}
+	if maxK > totalX {
+		maxK = totalX
+	}
+	p := 0.0
+	for k := minK; k <= maxK; k++ {
+		// the relative tolerance protects against the floating point errors in the probabilities of the same tables
+		if lp := logP(k); lp <= observed+1e-7 {
+			p += math.Exp(lp)
+		}
+	}
+	return math.Min(1, p)
+}
+
+func logChoose(n, k int) float64 {
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
+
+// BootstrapCI Estimates the confidence interval of the statistic of the sample at the given confidence level using
+// the percentile bootstrap with the provided number of resamples.
+func BootstrapCI(x Floats, statistic func(Floats) float64, resamples int, level float64, rng *rand.Rand) ConfidenceInterval {
+	ci := ConfidenceInterval{Estimate: math.NaN(), Lower: math.NaN(), Upper: math.NaN(), Level: level}
+	if len(x) == 0 {
+		return ci
+	}
+	ci.Estimate = statistic(x)
+	if resamples <= 0 {
+		return ci
+	}
+	estimates := make([]float64, resamples)
+	sample := make(Floats, len(x))
+	for i := range estimates {
+		for j := range sample {
+			sample[j] = x[rng.Intn(len(x))]
+		}
+		estimates[i] = statistic(sample)
+	}
+	sort.Float64s(estimates)
+	alpha := (1 - level) / 2
+	ci.Lower = stat.Quantile(alpha, stat.Empirical, estimates, nil)
+	ci.Upper = stat.Quantile(1-alpha, stat.Empirical, estimates, nil)
+	return ci
+}
+
+// ComparisonOptions the options of the statistical comparison of experiments
+type ComparisonOptions struct {
+	// The significance level of the statistical tests
+	Alpha float64
+	// The confidence level of the bootstrap confidence intervals
+	ConfidenceLevel float64
+	// The number of bootstrap resamples
+	BootstrapResamples int
+	// The seed of the random number generator used for bootstrap resampling
+	Seed int64
+}
+
+// DefaultComparisonOptions Returns the comparison options with the significance level 0.05, the confidence level 0.95
+// and 10000 bootstrap resamples.
+func DefaultComparisonOptions() ComparisonOptions {
+	return ComparisonOptions{Alpha: 0.05, ConfidenceLevel: 0.95, BootstrapResamples: 10000, Seed: 42}
+}
+
+// SampleSummary the descriptive statistics of the sample of per trial values
+type SampleSummary struct {
+	// The sample size
+	N int
+	// The sample median
+	Median float64
+	// The standard deviation of the sample
+	StdDev float64
+	// The bootstrap confidence interval of the sample mean
+	Mean ConfidenceInterval
+}
+
+// ExperimentSummary the summary of the compared experiment
+type ExperimentSummary struct {
+	// The name of the experiment
+	Name string
+	// The number of trials
+	Trials int
+	// The number of solved trials
+	Solved int
+	// The final best fitness scores of all trials
+	FinalFitness SampleSummary
+	// The numbers of evaluations to solve of the solved trials
+	EvaluationsToSolve SampleSummary
+}
+
+// MetricComparison the result of comparison of the metric sampled per trial between two experiments
+type MetricComparison struct {
+	// The result of the Mann-Whitney U test
+	MannWhitney StatisticalTest
+	// The result of the Welch's t-test
+	Welch StatisticalTest
+	// The Vargha-Delaney A12 effect size, i.e. the probability that the metric of the first experiment is greater
+	A12 float64
+	// The magnitude of the A12 effect size
+	Magnitude string
+	// Whether the difference is significant according to the Mann-Whitney U test
+	Significant bool
+}
+
+// PairComparison the result of comparison of two experiments
+type PairComparison struct {
+	// The names of the compared experiments
+	A, B string
+	// The comparison of final best fitness scores, the greater is better
+	FinalFitness MetricComparison
+	// The comparison of the numbers of evaluations to solve, the lower is better
+	EvaluationsToSolve MetricComparison
+	// The p-value of the Fisher's exact test of success rates
+	SuccessRatePValue float64
+	// Whether the difference of success rates is significant
+	SuccessRateSignificant bool
+}
+
+// ComparisonReport the report of the statistical comparison of two or more experiments
+type ComparisonReport struct {
+	// The options used for comparison
+	Options ComparisonOptions
+	// The summaries of compared experiments
+	Experiments []ExperimentSummary
+	// The results of comparison of each pair of experiments
+	Pairs []PairComparison
+}
+
+// EvaluationsToSolve Returns the number of organism evaluations until the winner was found for each solved trial
+func (e *Experiment) EvaluationsToSolve() Floats {
+	x := make(Floats, 0, len(e.Trials))
+	for i := range e.Trials {
+		if e.Trials[i].Solved() {
+			_, _, evals, _ := e.Trials[i].Winner()
+			x = append(x, float64(evals))
+		}
+	}
+	return x
+}
+
+// CompareExperiments Performs the statistical comparison of the final best fitness scores, the numbers of evaluations
+// to solve and the success rates of each pair of provided experiments.
+func CompareExperiments(experiments []*Experiment, opts ComparisonOptions) (*ComparisonReport, error) {
+	if len(experiments) < 2 {
+		return nil, errors.New("at least two experiments must be provided for comparison")
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	fitness := make([]Floats, len(experiments))
+	evaluations := make([]Floats, len(experiments))
+	report := &ComparisonReport{Options: opts, Experiments: make([]ExperimentSummary, len(experiments))}
+	for i, e := range experiments {
+		if len(e.Trials) == 0 {
+			return nil, errors.Errorf("experiment %q has no trials", experimentName(e, i))
+		}
+		fitness[i] = e.BestFitness()
+		evaluations[i] = e.EvaluationsToSolve()
+		report.Experiments[i] = ExperimentSummary{
+			Name:               experimentName(e, i),
+			Trials:             len(e.Trials),
+			Solved:             e.TrialsSolved(),
+			FinalFitness:       summarizeSample(fitness[i], opts, rng),
+			EvaluationsToSolve: summarizeSample(evaluations[i], opts, rng),
+		}
+	}
+	for i := 0; i < len(experiments); i++ {
+		for j := i + 1; j < len(experiments); j++ {
+			a, b := report.Experiments[i], report.Experiments[j]
+			pair := PairComparison{
+				A:                  a.Name,
+				B:                  b.Name,
+				FinalFitness:       compareMetric(fitness[i], fitness[j], opts.Alpha),
+				EvaluationsToSolve: compareMetric(evaluations[i], evaluations[j], opts.Alpha),
+				SuccessRatePValue:  FisherExactTest(a.Solved, a.Trials, b.Solved, b.Trials),
+			}
+			pair.SuccessRateSignificant = pair.SuccessRatePValue < opts.Alpha
+			report.Pairs = append(report.Pairs, pair)
+		}
+	}
+	return report, nil
+}
+
+func experimentName(e *Experiment, index int) string {
+	if len(e.Name) > 0 {
+		return e.Name
+	}
+	return fmt.Sprintf("experiment %d", index)
+}
+
+func summarizeSample(x Floats, opts ComparisonOptions, rng *rand.Rand) SampleSummary {
+	// the quantiles are defined only for sorted data
+	sorted := make(Floats, len(x))
+	copy(sorted, x)
+	sort.Float64s(sorted)
+	return SampleSummary{
+		N:      len(x),
+		Median: sorted.Median(),
+		StdDev: x.StdDev(),
+		Mean:   BootstrapCI(x, Floats.Mean, opts.BootstrapResamples, opts.ConfidenceLevel, rng),
+	}
+}
+
+func compareMetric(x, y Floats, alpha float64) MetricComparison {
+	c := MetricComparison{
+		MannWhitney: MannWhitneyU(x, y),
+		Welch:       WelchTTest(x, y),
+		A12:         VarghaDelaneyA12(x, y),
+	}
+	c.Magnitude = A12Magnitude(c.A12)
+	c.Significant = c.MannWhitney.PValue < alpha
+	return c
+}
+
+// Write Writes the human readable comparison report into provided writer
+func (r *ComparisonReport) Write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Comparison of %d experiments, alpha: %g, confidence level: %g, bootstrap resamples: %d\n",
+		len(r.Experiments), r.Options.Alpha, r.Options.ConfidenceLevel, r.Options.BootstrapResamples); err != nil {
+		return err
+	}
+	for _, s := range r.Experiments {
+		if _, err := fmt.Fprintf(w, "\n%s\n\tSolved:\t\t\t%d from %d trials\n", s.Name, s.Solved, s.Trials); err != nil {
+			return err
+		}
+		if err := writeSampleSummary(w, "Final fitness", s.FinalFitness); err != nil {
+			return err
+		}
+		if err := writeSampleSummary(w, "Evaluations to solve", s.EvaluationsToSolve); err != nil {
+			return err
+		}
+	}
+	for _, p := range r.Pairs {
+		if _, err := fmt.Fprintf(w, "\n%s vs %s\n", p.A, p.B); err != nil {
+			return err
+		}
+		if err := writeMetricComparison(w, "Final fitness", p.FinalFitness); err != nil {
+			return err
+		}
+		if err := writeMetricComparison(w, "Evaluations to solve", p.EvaluationsToSolve); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "\tSuccess rate:\t\tFisher p=%.4g%s\n",
+			p.SuccessRatePValue, significanceMark(p.SuccessRateSignificant)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSampleSummary(w io.Writer, name string, s SampleSummary) error {
+	_, err := fmt.Fprintf(w, "\t%s:\tn=%d, mean=%g [%g, %g], median=%g, sd=%g\n",
+		name, s.N, s.Mean.Estimate, s.Mean.Lower, s.Mean.Upper, s.Median, s.StdDev)
+	return err
+}
+
+func writeMetricComparison(w io.Writer, name string, c MetricComparison) error {
+	_, err := fmt.Fprintf(w, "\t%s:\tMann-Whitney U=%g p=%.4g%s, Welch t=%.4g p=%.4g, A12=%.3f (%s)\n",
+		name, c.MannWhitney.Statistic, c.MannWhitney.PValue, significanceMark(c.Significant),
+		c.Welch.Statistic, c.Welch.PValue, c.A12, c.Magnitude)
+	return err
+}
+
+func significanceMark(significant bool) string {
+	if significant {
+		return " *"
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package experiment
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	res := MannWhitneyU(Floats{1, 2, 3, 4, 5}, Floats{6, 7, 8, 9, 10})
+	assert.Equal(t, 0.0, res.Statistic)
+	assert.InDelta(t, 0.012186, res.PValue, 1e-6)
+
+	// with ties
+	res = MannWhitneyU(Floats{1, 2, 2, 3}, Floats{2, 3, 3, 4})
+	assert.Equal(t, 3.0, res.Statistic)
+	assert.True(t, res.PValue > 0.05 && res.PValue <= 1, "p-value: %f", res.PValue)
+
+	// identical values
+	res = MannWhitneyU(Floats{1, 1}, Floats{1, 1})
+	assert.Equal(t, 1.0, res.PValue)
+
+	res = MannWhitneyU(Floats{}, Floats{1, 2})
+	assert.True(t, math.IsNaN(res.PValue))
+}
+
+func TestWelchTTest(t *testing.T) {
+	res := WelchTTest(Floats{1, 2, 3, 4, 5}, Floats{2, 4, 6, 8, 10})
+	assert.InDelta(t, -1.897367, res.Statistic, 1e-6)
+	assert.InDelta(t, 5.882353, res.DegreesOfFreedom, 1e-6)
+	assert.InDelta(t, 0.1073, res.PValue, 1e-3)
+
+	res = WelchTTest(Floats{2, 2}, Floats{2, 2})
+	assert.Equal(t, 1.0, res.PValue)
+	res = WelchTTest(Floats{1, 1}, Floats{2, 2})
+	assert.Equal(t, 0.0, res.PValue)
+
+	res = WelchTTest(Floats{1}, Floats{2, 3})
+	assert.True(t, math.IsNaN(res.PValue))
+}
+
+func TestVarghaDelaneyA12(t *testing.T) {
+	assert.Equal(t, 0.5, VarghaDelaneyA12(Floats{1, 2, 3}, Floats{1, 2, 3}))
+	assert.Equal(t, 1.0, VarghaDelaneyA12(Floats{4, 5}, Floats{1, 2, 3}))
+	assert.Equal(t, 0.0, VarghaDelaneyA12(Floats{1, 2, 3}, Floats{4, 5}))
+	assert.True(t, math.IsNaN(VarghaDelaneyA12(Floats{}, Floats{1})))
+
+	assert.Equal(t, "negligible", A12Magnitude(0.52))
+	assert.Equal(t, "small", A12Magnitude(0.4))
+	assert.Equal(t, "medium", A12Magnitude(0.68))
+	assert.Equal(t, "large", A12Magnitude(0.0))
+}
+
+func TestFisherExactTest(t *testing.T) {
+	assert.InDelta(t, 0.485714, FisherExactTest(3, 4, 1, 4), 1e-6)
+	assert.InDelta(t, 0.034965, FisherExactTest(8, 10, 1, 6), 1e-6)
+	assert.InDelta(t, 1.0, FisherExactTest(5, 10, 5, 10), 1e-9)
+	assert.True(t, math.IsNaN(FisherExactTest(3, 2, 1, 4)))
+}
+
+func TestBootstrapCI(t *testing.T) {
+	x := Floats{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	ci := BootstrapCI(x, Floats.Mean, 2000, 0.95, rand.New(rand.NewSource(42)))
+	assert.Equal(t, 5.5, ci.Estimate)
+	assert.Equal(t, 0.95, ci.Level)
+	assert.True(t, ci.Lower < ci.Estimate && ci.Estimate < ci.Upper, "interval: %v", ci)
+	assert.True(t, ci.Lower >= 1 && ci.Upper <= 10, "interval: %v", ci)
+
+	ci = BootstrapCI(Floats{}, Floats.Mean, 100, 0.95, rand.New(rand.NewSource(42)))
+	assert.True(t, math.IsNaN(ci.Estimate))
+}
+
+func TestCompareExperiments(t *testing.T) {
+	expA := buildComparisonTestExperiment("A", []float64{14, 10, 15, 12, 11, 13}, 6)
+	expB := buildComparisonTestExperiment("B", []float64{1, 2, 3, 4, 5, 6}, 1)
+	expC := buildComparisonTestExperiment("", []float64{1, 2, 3, 4, 5, 6}, 0)
+
+	opts := DefaultComparisonOptions()
+	opts.BootstrapResamples = 500
+	report, err := CompareExperiments([]*Experiment{expA, expB, expC}, opts)
+	require.NoError(t, err, "failed to compare experiments")
+	require.Len(t, report.Experiments, 3)
+	require.Len(t, report.Pairs, 3)
+
+	assert.Equal(t, "experiment 2", report.Experiments[2].Name)
+	assert.Equal(t, 6, report.Experiments[0].Solved)
+	assert.Equal(t, 12.0, report.Experiments[0].FinalFitness.Median)
+	assert.Equal(t, 6, report.Experiments[0].EvaluationsToSolve.N)
+	assert.Equal(t, 0, report.Experiments[2].EvaluationsToSolve.N)
+
+	ab := report.Pairs[0]
+	assert.Equal(t, "A", ab.A)
+	assert.Equal(t, "B", ab.B)
+	assert.True(t, ab.FinalFitness.Significant, "fitness difference expected to be significant")
+	assert.Equal(t, 1.0, ab.FinalFitness.A12)
+	assert.Equal(t, "large", ab.FinalFitness.Magnitude)
+	assert.True(t, ab.SuccessRateSignificant, "success rate difference expected to be significant")
+
+	bc := report.Pairs[2]
+	assert.False(t, bc.FinalFitness.Significant)
+	assert.Equal(t, 0.5, bc.FinalFitness.A12)
+	assert.True(t, math.IsNaN(bc.EvaluationsToSolve.MannWhitney.PValue))
+	assert.False(t, bc.SuccessRateSignificant)
+
+	var buf bytes.Buffer
+	err = report.Write(&buf)
+	require.NoError(t, err, "failed to write report")
+	assert.Contains(t, buf.String(), "A vs B")
+	assert.Contains(t, buf.String(), "Mann-Whitney")
+
+	_, err = CompareExperiments([]*Experiment{expA}, opts)
+	assert.Error(t, err)
+	_, err = CompareExperiments([]*Experiment{expA, {Name: "empty"}}, opts)
+	assert.Error(t, err)
+}
+
+func buildComparisonTestExperiment(name string, fitness []float64, solved int) *Experiment {
+	exp := Experiment{Name: name, Trials: make(Trials, len(fitness))}
+	for i, f := range fitness {
+		gen := buildTestGeneration(1, f)
+		gen.Solved = i < solved
+		gen.WinnerEvals = 100 * (i + 1)
+		exp.Trials[i] = Trial{Id: i, Generations: Generations{*gen}}
+	}
+	return &exp
+}
@@ -0,0 +1,356 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EvaluatePath The HTTP path of the worker's endpoint accepting organisms for evaluation
+const EvaluatePath = "/evaluate"
+
+// HealthPath The HTTP path of the worker's endpoint used to probe whether lost worker is back
+const HealthPath = "/health"
+
+// The default settings of the RemoteOrganismEvaluator
+const (
+	// DefaultRemoteTimeout The default timeout of single organism evaluation by remote worker
+	DefaultRemoteTimeout = time.Minute
+	// DefaultRemoteMaxRetries The default number of retries of organism evaluation after transport failures
+	DefaultRemoteMaxRetries = 3
+	// DefaultRemoteMaxFailures The default number of consecutive failures after which remote worker considered lost
+	DefaultRemoteMaxFailures = 3
+	// DefaultRemoteProbeInterval The default interval between probes of the lost remote worker
+	DefaultRemoteProbeInterval = 10 * time.Second
+)
+
+// ErrNoLiveWorkers The error returned when all remote workers are lost
+var ErrNoLiveWorkers = errors.New("no live remote workers left")
+
+// EvaluationRequest The request sent to the remote worker to evaluate organism
+type EvaluationRequest struct {
+	// The name of the task which organism evaluator should be used by the worker
+	Task string `json:"task"`
+	// The ID of the organism's genome
+	GenomeId int `json:"genome_id"`
+	// The generation of the organism
+	Generation int `json:"generation"`
+	// The genome of the organism encoded in YAML
+	Genome string `json:"genome"`
+}
+
+// EvaluationResponse The response of the remote worker with results of organism evaluation
+type EvaluationResponse struct {
+	// The ID of the evaluated organism's genome
+	GenomeId int `json:"genome_id"`
+	// The fitness score of the organism
+	Fitness float64 `json:"fitness"`
+	// The error value of the organism
+	ErrorValue float64 `json:"error_value"`
+	// The flag to indicate whether organism is a winner
+	Winner bool `json:"winner"`
+	// The error message if evaluation failed
+	Error string `json:"error,omitempty"`
+}
+
+// remoteWorker holds the state of the remote worker
+type remoteWorker struct {
+	// The base URL of the worker
+	url string
+	// The number of consecutive failures
+	failures int
+	// The flag to indicate whether worker was lost
+	lost bool
+	// The time when lost worker was probed last time
+	probed time.Time
+}
+
+// RemoteOrganismEvaluator the OrganismEvaluator which sends organisms to the remote workers over HTTP/JSON protocol.
+// The workers are used in round-robin order. The evaluation which failed due to transport error, timeout, or
+// unexpected response is retried on the next worker. The worker which fails MaxFailures times in a row is considered
+// lost and is not used until it responds to the health probe, see ProbeLostWorkers.
+type RemoteOrganismEvaluator struct {
+	// The name of the task to be requested from the workers
+	Task string
+	// The timeout of single organism evaluation
+	Timeout time.Duration
+	// The maximal number of retries of single organism evaluation
+	MaxRetries int
+	// The number of consecutive failures after which the worker considered lost
+	MaxFailures int
+	// The minimal interval between health probes of the lost worker. If zero or negative, lost workers are never probed.
+	ProbeInterval time.Duration
+
+	workers []*remoteWorker
+	next    int
+	mutex   sync.Mutex
+	client  *http.Client
+}
+
+// NewRemoteOrganismEvaluator Creates new remote organism evaluator for the given task which uses workers with provided
+// base URLs, e.g. "http://localhost:8080".
+func NewRemoteOrganismEvaluator(task string, workerURLs []string) *RemoteOrganismEvaluator {
+	workers := make([]*remoteWorker, len(workerURLs))
+	for i, url := range workerURLs {
+		workers[i] = &remoteWorker{url: strings.TrimSuffix(url, "/")}
+	}
+	return &RemoteOrganismEvaluator{
+		Task:          task,
+		Timeout:       DefaultRemoteTimeout,
+		MaxRetries:    DefaultRemoteMaxRetries,
+		MaxFailures:   DefaultRemoteMaxFailures,
+		ProbeInterval: DefaultRemoteProbeInterval,
+		workers:       workers,
+		client:        &http.Client{},
+	}
+}
+
+// LiveWorkers Returns the number of remote workers which are not lost
+func (e *RemoteOrganismEvaluator) LiveWorkers() int {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	count := 0
+	for _, w := range e.workers {
+		if !w.lost {
+			count++
+		}
+	}
+	return count
+}
+
+// OrganismEvaluate Sends provided organism to the remote worker for evaluation and returns results.
+func (e *RemoteOrganismEvaluator) OrganismEvaluate(organism *genetics.Organism, _ *neat.Options) (fitness, errValue float64, winner bool, err error) {
+	return e.OrganismEvaluateContext(context.Background(), organism)
+}
+
+// OrganismEvaluateContext Sends provided organism to the remote worker for evaluation within given context and
+// returns results. The pending request is canceled when context is done.
+func (e *RemoteOrganismEvaluator) OrganismEvaluateContext(ctx context.Context, organism *genetics.Organism) (fitness, errValue float64, winner bool, err error) {
+	var buf bytes.Buffer
+	if wr, err := genetics.NewGenomeWriter(&buf, genetics.YAMLGenomeEncoding); err != nil {
+		return 0, 0, false, err
+	} else if err = wr.WriteGenome(organism.Genotype); err != nil {
+		return 0, 0, false, err
+	}
+	body, err := json.Marshal(EvaluationRequest{
+		Task:       e.Task,
+		GenomeId:   organism.Genotype.Id,
+		Generation: organism.Generation,
+		Genome:     buf.String(),
+	})
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= e.MaxRetries; attempt++ {
+		if err = ctx.Err(); err != nil {
+			return 0, 0, false, err
+		}
+		worker := e.nextWorker()
+		if worker == nil && e.ProbeLostWorkers(ctx) > 0 {
+			worker = e.nextWorker()
+		}
+		if worker == nil {
+			if lastErr != nil {
+				return 0, 0, false, fmt.Errorf("%s, last failure: %s", ErrNoLiveWorkers, lastErr)
+			}
+			return 0, 0, false, ErrNoLiveWorkers
+		}
+		resp, err := e.post(ctx, worker, body)
+		if err != nil {
+			if ctx.Err() != nil {
+				// the request was canceled, the worker is not to blame
+				return 0, 0, false, ctx.Err()
+			}
+			lastErr = err
+			e.reportFailure(worker, err)
+			continue
+		}
+		e.reportSuccess(worker)
+		if resp.GenomeId != organism.Genotype.Id {
+			// the response is for the other organism - should not happen, but retry to be safe
+			lastErr = fmt.Errorf("unexpected response for genome: %d, expected: %d", resp.GenomeId, organism.Genotype.Id)
+			neat.WarnLog(lastErr.Error())
+			continue
+		}
+		if len(resp.Error) > 0 {
+			// the evaluation itself failed, retrying makes no sense
+			return 0, 0, false, errors.New(resp.Error)
+		}
+		return resp.Fitness, resp.ErrorValue, resp.Winner, nil
+	}
+	return 0, 0, false, fmt.Errorf("failed to evaluate organism after %d attempts, last failure: %s", e.MaxRetries+1, lastErr)
+}
+
+// post Sends the evaluation request to the given worker and decodes its response
+func (e *RemoteOrganismEvaluator) post(ctx context.Context, worker *remoteWorker, body []byte) (*EvaluationResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, worker.url+EvaluatePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := *e.client
+	client.Timeout = e.Timeout
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("worker %s responded with status: %s, body: %s",
+			worker.url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	res := &EvaluationResponse{}
+	if err = json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ProbeLostWorkers Sends health probe to the lost workers which were not probed during ProbeInterval and returns
+// them into rotation if they respond. Returns the number of recovered workers.
+func (e *RemoteOrganismEvaluator) ProbeLostWorkers(ctx context.Context) int {
+	if e.ProbeInterval <= 0 {
+		return 0
+	}
+	e.mutex.Lock()
+	now := time.Now()
+	due := make([]*remoteWorker, 0)
+	for _, w := range e.workers {
+		if w.lost && now.Sub(w.probed) >= e.ProbeInterval {
+			w.probed = now
+			due = append(due, w)
+		}
+	}
+	e.mutex.Unlock()
+
+	recovered := 0
+	for _, w := range due {
+		if err := e.probe(ctx, w); err != nil {
+			neat.DebugLog(fmt.Sprintf("Remote worker %s is still lost, reason: %s", w.url, err))
+			continue
+		}
+		e.mutex.Lock()
+		w.lost, w.failures = false, 0
+		e.mutex.Unlock()
+		recovered++
+		neat.InfoLog(fmt.Sprintf("Remote worker %s is back", w.url))
+	}
+	return recovered
+}
+
+// probe Sends the health probe to the given worker
+func (e *RemoteOrganismEvaluator) probe(ctx context.Context, worker *remoteWorker) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, worker.url+HealthPath, nil)
+	if err != nil {
+		return err
+	}
+	client := *e.client
+	client.Timeout = e.Timeout
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("worker %s responded with status: %s", worker.url, resp.Status)
+	}
+	return nil
+}
+
+// nextWorker Returns the next live worker in round-robin order or nil if all workers are lost
+func (e *RemoteOrganismEvaluator) nextWorker() *remoteWorker {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for i := 0; i < len(e.workers); i++ {
+		w := e.workers[e.next]
+		e.next = (e.next + 1) % len(e.workers)
+		if !w.lost {
+			return w
+		}
+	}
+	return nil
+}
+
+func (e *RemoteOrganismEvaluator) reportFailure(worker *remoteWorker, err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	worker.failures++
+	neat.WarnLog(fmt.Sprintf("Remote worker %s failed to evaluate organism, reason: %s", worker.url, err))
+	if e.MaxFailures > 0 && worker.failures >= e.MaxFailures && !worker.lost {
+		worker.lost = true
+		worker.probed = time.Now()
+		neat.WarnLog(fmt.Sprintf("Remote worker %s is lost after %d failures", worker.url, worker.failures))
+	}
+}
+
+func (e *RemoteOrganismEvaluator) reportSuccess(worker *remoteWorker) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	worker.failures = 0
+}
+
+// DistributedGenerationEvaluator the GenerationEvaluator which evaluates organisms of the population using remote
+// workers. It is the coordinator side of the distributed evaluation, see EvaluationWorker for the worker side.
+type DistributedGenerationEvaluator struct {
+	*ParallelGenerationEvaluator
+	// The evaluator sending organisms to the remote workers
+	Remote *RemoteOrganismEvaluator
+	// The number of organisms sent to each worker concurrently
+	ConcurrencyPerWorker int
+	// The optional local evaluator invoked after all organisms of the population were evaluated by remote workers,
+	// e.g. to run extra tests of the generation champion. The remote evaluator never implements it.
+	PostEvaluator GenerationPostEvaluator
+}
+
+// NewDistributedGenerationEvaluator Creates new coordinator of distributed evaluation for the given task using workers
+// with provided base URLs. The files with dumped genomes are stored in the outputPath with namePrefix prepended. The
+// additional processing of the evaluated generation can be done locally by setting PostEvaluator.
+func NewDistributedGenerationEvaluator(task string, workerURLs []string, outputPath, namePrefix string) *DistributedGenerationEvaluator {
+	remote := NewRemoteOrganismEvaluator(task, workerURLs)
+	return &DistributedGenerationEvaluator{
+		ParallelGenerationEvaluator: NewParallelGenerationEvaluator(remote, outputPath, namePrefix, len(workerURLs)),
+		Remote:                      remote,
+		ConcurrencyPerWorker:        1,
+	}
+}
+
+// GenerationEvaluate This method evaluates one epoch for given population using remote workers and prints results into
+// output directory if any.
+func (e *DistributedGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, opts *neat.Options) error {
+	return e.GenerationEvaluateContext(neat.NewContext(context.Background(), opts), pop, epoch)
+}
+
+// GenerationEvaluateContext This method evaluates one epoch for given population using remote workers within given
+// context and prints results into output directory if any. The lost workers are probed before evaluation and used
+// again if they are back.
+func (e *DistributedGenerationEvaluator) GenerationEvaluateContext(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	e.Remote.ProbeLostWorkers(ctx)
+	live := e.Remote.LiveWorkers()
+	if live == 0 {
+		return ErrNoLiveWorkers
+	}
+	concurrency := e.ConcurrencyPerWorker
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return e.generationEvaluate(ctx, pop, epoch, live*concurrency, e.PostEvaluator)
+}
@@ -0,0 +1,299 @@
+package experiment
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	testTask = "test"
+	// the environment variable which makes the test binary to run as the worker process
+	testWorkerProcessEnv = "GONEAT_TEST_WORKER_PROCESS"
+)
+
+func startTestWorker(evaluator *testOrganismEvaluator) *httptest.Server {
+	worker := NewEvaluationWorker(&neat.Options{})
+	worker.RegisterEvaluator(testTask, evaluator)
+	return httptest.NewServer(worker)
+}
+
+func TestDistributedGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	orgEvaluator := &testOrganismEvaluator{winnerId: 3, evaluated: make(map[int]int)}
+	w1, w2 := startTestWorker(orgEvaluator), startTestWorker(orgEvaluator)
+	defer w1.Close()
+	defer w2.Close()
+
+	popSize := 12
+	pop, err := buildTestPopulation(popSize)
+	require.NoError(t, err, "failed to create population")
+
+	postEvaluator := &testOrganismEvaluator{evaluated: make(map[int]int)}
+	evaluator := NewDistributedGenerationEvaluator(testTask, []string{w1.URL, w2.URL}, "", "test")
+	evaluator.ConcurrencyPerWorker = 2
+	evaluator.PostEvaluator = postEvaluator
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(pop, &epoch, &neat.Options{PopSize: popSize, PrintEvery: 1})
+	require.NoError(t, err, "failed to evaluate generation")
+
+	assert.Len(t, orgEvaluator.evaluated, popSize)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+		assert.Equal(t, 1.0/float64(org.Genotype.Id), org.Error)
+		assert.Equal(t, org.Genotype.Id == 3, org.IsWinner)
+	}
+	assert.True(t, epoch.Solved)
+	require.NotNil(t, epoch.Best)
+	assert.Equal(t, 3, epoch.Best.Genotype.Id)
+	assert.Equal(t, 2, evaluator.Remote.LiveWorkers())
+	assert.Equal(t, 1, postEvaluator.postCalls, "local post evaluator must be invoked once")
+	assert.Zero(t, orgEvaluator.postCalls, "remote evaluator must not be post evaluated")
+}
+
+func TestEvaluationWorker_RequestContext(t *testing.T) {
+	orgEvaluator := &testContextOrganismEvaluator{
+		testOrganismEvaluator: &testOrganismEvaluator{slowId: 1, evaluated: make(map[int]int)},
+	}
+	worker := NewEvaluationWorker(&neat.Options{})
+	worker.RegisterEvaluator(testTask, orgEvaluator)
+	server := httptest.NewServer(worker)
+	defer server.Close()
+
+	pop, err := buildTestPopulation(2)
+	require.NoError(t, err, "failed to create population")
+
+	remote := NewRemoteOrganismEvaluator(testTask, []string{server.URL})
+	remote.Timeout = 50 * time.Millisecond
+	remote.MaxRetries = 0
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[0], nil)
+	assert.Error(t, err, "timeout expected")
+	// the slow evaluation returns when the request is canceled by coordinator
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&orgEvaluator.slowReturned) == 1
+	}, time.Second, 10*time.Millisecond, "evaluation must be interrupted with request context")
+
+	fitness, _, _, err := remote.OrganismEvaluate(pop.Organisms[1], nil)
+	require.NoError(t, err, "failed to evaluate organism")
+	assert.Equal(t, 2.0, fitness)
+}
+
+func TestRemoteOrganismEvaluator_WorkerLoss(t *testing.T) {
+	orgEvaluator := &testOrganismEvaluator{evaluated: make(map[int]int)}
+	alive := startTestWorker(orgEvaluator)
+	defer alive.Close()
+	lost := startTestWorker(orgEvaluator)
+	lost.Close()
+
+	pop, err := buildTestPopulation(6)
+	require.NoError(t, err, "failed to create population")
+
+	remote := NewRemoteOrganismEvaluator(testTask, []string{lost.URL, alive.URL})
+	remote.MaxFailures = 1
+	for _, org := range pop.Organisms {
+		fitness, _, _, err := remote.OrganismEvaluate(org, nil)
+		require.NoError(t, err, "failed to evaluate organism: %d", org.Genotype.Id)
+		assert.Equal(t, float64(org.Genotype.Id), fitness)
+	}
+	assert.Equal(t, 1, remote.LiveWorkers())
+
+	// all workers lost
+	alive.Close()
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[0], nil)
+	assert.Error(t, err)
+	assert.Zero(t, remote.LiveWorkers())
+}
+
+func TestRemoteOrganismEvaluator_TimeoutAndReordering(t *testing.T) {
+	orgEvaluator := &testOrganismEvaluator{evaluated: make(map[int]int)}
+	good := startTestWorker(orgEvaluator)
+	defer good.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	defer slow.Close()
+	confused := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(EvaluationResponse{GenomeId: -1, Fitness: 100})
+	}))
+	defer confused.Close()
+
+	pop, err := buildTestPopulation(3)
+	require.NoError(t, err, "failed to create population")
+
+	remote := NewRemoteOrganismEvaluator(testTask, []string{slow.URL, confused.URL, good.URL})
+	remote.Timeout = 50 * time.Millisecond
+	remote.MaxRetries = 2
+	for _, org := range pop.Organisms {
+		fitness, _, _, err := remote.OrganismEvaluate(org, nil)
+		require.NoError(t, err, "failed to evaluate organism: %d", org.Genotype.Id)
+		assert.Equal(t, float64(org.Genotype.Id), fitness)
+	}
+
+	// not enough retries to reach the good worker
+	remote.MaxRetries = 0
+	remote.next = 0
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[0], nil)
+	assert.Error(t, err)
+}
+
+func TestRemoteOrganismEvaluator_EvaluationError(t *testing.T) {
+	orgEvaluator := &testOrganismEvaluator{failingId: 2, evaluated: make(map[int]int)}
+	worker := startTestWorker(orgEvaluator)
+	defer worker.Close()
+
+	pop, err := buildTestPopulation(2)
+	require.NoError(t, err, "failed to create population")
+
+	remote := NewRemoteOrganismEvaluator(testTask, []string{worker.URL})
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[1], nil)
+	assert.EqualError(t, err, "evaluation failed")
+	assert.Equal(t, 1, remote.LiveWorkers(), "worker should not be blamed for evaluation failure")
+
+	// unknown task
+	remote = NewRemoteOrganismEvaluator("unknown", []string{worker.URL})
+	remote.MaxRetries = 0
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[0], nil)
+	assert.Error(t, err)
+}
+
+func TestRemoteOrganismEvaluator_ProbeLostWorkers(t *testing.T) {
+	orgEvaluator := &testOrganismEvaluator{evaluated: make(map[int]int)}
+	worker := NewEvaluationWorker(&neat.Options{})
+	worker.RegisterEvaluator(testTask, orgEvaluator)
+	var down int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			http.Error(w, "worker is down", http.StatusServiceUnavailable)
+			return
+		}
+		worker.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	pop, err := buildTestPopulation(2)
+	require.NoError(t, err, "failed to create population")
+
+	remote := NewRemoteOrganismEvaluator(testTask, []string{server.URL})
+	remote.MaxFailures = 1
+	remote.MaxRetries = 0
+	remote.ProbeInterval = time.Hour
+
+	// the worker is lost
+	atomic.StoreInt32(&down, 1)
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[0], nil)
+	assert.Error(t, err)
+	assert.Zero(t, remote.LiveWorkers())
+
+	// the worker is back, but the probe is not due yet
+	atomic.StoreInt32(&down, 0)
+	assert.Zero(t, remote.ProbeLostWorkers(context.Background()))
+	_, _, _, err = remote.OrganismEvaluate(pop.Organisms[0], nil)
+	assert.EqualError(t, err, ErrNoLiveWorkers.Error())
+
+	// the worker is recovered by the probe
+	remote.ProbeInterval = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	fitness, _, _, err := remote.OrganismEvaluate(pop.Organisms[1], nil)
+	require.NoError(t, err, "failed to evaluate organism after worker recovery")
+	assert.Equal(t, float64(pop.Organisms[1].Genotype.Id), fitness)
+	assert.Equal(t, 1, remote.LiveWorkers())
+}
+
+// TestWorkerProcess is not a real test, it runs the evaluation worker when the test binary started as the worker
+// process by startWorkerProcess.
+func TestWorkerProcess(t *testing.T) {
+	if os.Getenv(testWorkerProcessEnv) != "1" {
+		return
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// report the worker address to the parent process
+	fmt.Printf("http://%s\n", listener.Addr())
+
+	worker := NewEvaluationWorker(&neat.Options{})
+	worker.RegisterEvaluator(testTask, &testOrganismEvaluator{winnerId: 3, evaluated: make(map[int]int)})
+	err = http.Serve(listener, worker)
+	_, _ = fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
+// startWorkerProcess Starts the test binary as the separate worker process and returns its command and base URL
+func startWorkerProcess(t *testing.T) (*exec.Cmd, string) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestWorkerProcess$")
+	cmd.Env = append(os.Environ(), testWorkerProcessEnv+"=1")
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start(), "failed to start worker process")
+
+	url, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		require.NoError(t, err, "failed to read worker address")
+	}
+	return cmd, strings.TrimSpace(url)
+}
+
+func TestDistributedGenerationEvaluator_WorkerProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping worker processes test in short mode")
+	}
+	cmd1, url1 := startWorkerProcess(t)
+	defer func() {
+		_ = cmd1.Process.Kill()
+		_ = cmd1.Wait()
+	}()
+	cmd2, url2 := startWorkerProcess(t)
+	defer func() {
+		_ = cmd2.Process.Kill()
+		_ = cmd2.Wait()
+	}()
+
+	popSize := 12
+	opts := &neat.Options{PopSize: popSize, PrintEvery: 1}
+	evaluator := NewDistributedGenerationEvaluator(testTask, []string{url1, url2}, "", "test")
+	evaluator.Remote.MaxFailures = 1
+	evaluator.ConcurrencyPerWorker = 2
+
+	pop, err := buildTestPopulation(popSize)
+	require.NoError(t, err, "failed to create population")
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(pop, &epoch, opts)
+	require.NoError(t, err, "failed to evaluate generation")
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+		assert.Equal(t, org.Genotype.Id == 3, org.IsWinner)
+	}
+	require.NotNil(t, epoch.Best)
+	assert.Equal(t, 3, epoch.Best.Genotype.Id)
+
+	// kill one worker process, the evaluation continues on the remaining one
+	require.NoError(t, cmd1.Process.Kill())
+	_ = cmd1.Wait()
+
+	pop, err = buildTestPopulation(popSize)
+	require.NoError(t, err, "failed to create population")
+	epoch = Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(pop, &epoch, opts)
+	require.NoError(t, err, "failed to evaluate generation after worker loss")
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+	}
+	assert.Equal(t, 1, evaluator.Remote.LiveWorkers())
+}
@@ -0,0 +1,121 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// EvaluationWorker the worker side of the distributed evaluation. It accepts organisms from the coordinator
+// (see DistributedGenerationEvaluator) over HTTP/JSON protocol and evaluates them with the OrganismEvaluator registered
+// for the requested task. The worker implements http.Handler and can be served by any HTTP server.
+type EvaluationWorker struct {
+	// The NEAT options passed to the organism evaluators
+	Options *neat.Options
+
+	evaluators map[string]OrganismEvaluator
+	mutex      sync.RWMutex
+	mux        *http.ServeMux
+}
+
+// NewEvaluationWorker Creates new worker which passes provided NEAT options to the registered organism evaluators.
+func NewEvaluationWorker(options *neat.Options) *EvaluationWorker {
+	w := &EvaluationWorker{
+		Options:    options,
+		evaluators: make(map[string]OrganismEvaluator),
+		mux:        http.NewServeMux(),
+	}
+	w.mux.HandleFunc(EvaluatePath, w.handleEvaluate)
+	w.mux.HandleFunc(HealthPath, w.handleHealth)
+	return w
+}
+
+// RegisterEvaluator Registers the organism evaluator to be used for the given task
+func (w *EvaluationWorker) RegisterEvaluator(task string, evaluator OrganismEvaluator) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.evaluators[task] = evaluator
+}
+
+// ListenAndServe Starts serving evaluation requests at the given TCP network address. This method blocks until
+// server fails.
+func (w *EvaluationWorker) ListenAndServe(address string) error {
+	neat.InfoLog(fmt.Sprintf("Evaluation worker is listening at: %s", address))
+	return http.ListenAndServe(address, w)
+}
+
+// ServeHTTP Implements http.Handler
+func (w *EvaluationWorker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mux.ServeHTTP(rw, r)
+}
+
+func (w *EvaluationWorker) handleHealth(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(rw, "only GET method supported", http.StatusMethodNotAllowed)
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+func (w *EvaluationWorker) handleEvaluate(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "only POST method supported", http.StatusMethodNotAllowed)
+		return
+	}
+	req := EvaluationRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	w.mutex.RLock()
+	evaluator, ok := w.evaluators[req.Task]
+	w.mutex.RUnlock()
+	if !ok {
+		http.Error(rw, fmt.Sprintf("unknown task: %s", req.Task), http.StatusNotFound)
+		return
+	}
+
+	res := w.evaluate(neat.NewContext(r.Context(), w.Options), evaluator, &req)
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(res); err != nil {
+		neat.ErrorLog(fmt.Sprintf("Failed to write evaluation response, reason: %s", err))
+	}
+}
+
+// evaluate Decodes organism from the request and evaluates it with provided evaluator. The context of the request is
+// passed to the ContextOrganismEvaluator, thus evaluation can be interrupted when coordinator cancels the request.
+func (w *EvaluationWorker) evaluate(ctx context.Context, evaluator OrganismEvaluator, req *EvaluationRequest) *EvaluationResponse {
+	res := &EvaluationResponse{GenomeId: req.GenomeId}
+	reader, err := genetics.NewGenomeReader(strings.NewReader(req.Genome), genetics.YAMLGenomeEncoding)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	genome, err := reader.Read()
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to decode genome: %s", err)
+		return res
+	}
+	genome.Id = req.GenomeId
+	organism, err := genetics.NewOrganism(0, genome, req.Generation)
+	if err != nil {
+		res.Error = fmt.Sprintf("failed to create organism: %s", err)
+		return res
+	}
+
+	if ce, ok := evaluator.(ContextOrganismEvaluator); ok {
+		res.Fitness, res.ErrorValue, res.Winner, err = ce.OrganismEvaluateContext(ctx, organism)
+	} else {
+		res.Fitness, res.ErrorValue, res.Winner, err = evaluator.OrganismEvaluate(organism, w.Options)
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,185 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EventsLogFileName The name of the file in the output directory where JSONLEventObserver appends events
+const EventsLogFileName = "events.jsonl"
+
+// EventType the type of the experiment's event
+type EventType string
+
+// The types of the experiment's events
+const (
+	// EventTrialStarted emitted when new trial started, before any generation evaluated
+	EventTrialStarted EventType = "trial_started"
+	// EventTrialFinished emitted when trial finished, the Reason holds the trial's stop reason
+	EventTrialFinished EventType = "trial_finished"
+	// EventGenerationEvaluated emitted when generation evaluated, before population turned over to the next epoch
+	EventGenerationEvaluated EventType = "generation_evaluated"
+	// EventSpeciesCreated emitted when new species created in the population
+	EventSpeciesCreated = EventType(genetics.SpeciesCreatedEvent)
+	// EventSpeciesExtinct emitted when species removed from the population
+	EventSpeciesExtinct = EventType(genetics.SpeciesExtinctEvent)
+	// EventNewChampion emitted when the best organism of the generation outperforms all previous in the trial
+	EventNewChampion EventType = "new_champion"
+	// EventDeltaCoding emitted when delta coding applied to fix the population's stagnation
+	EventDeltaCoding = EventType(genetics.DeltaCodingEvent)
+	// EventBabiesStolen emitted when offspring taken away from the worse species and given to the best ones
+	EventBabiesStolen = EventType(genetics.BabiesStolenEvent)
+	// EventCheckpointWritten emitted when population or genome was written to the file, the Path holds file path
+	EventCheckpointWritten EventType = "checkpoint_written"
+)
+
+// Event the event emitted during experiment execution. Only fields relevant to the event's type are set.
+type Event struct {
+	// The type of the event
+	Type EventType `json:"type"`
+	// The time when event was emitted
+	Time time.Time `json:"time"`
+	// The ID of the trial
+	TrialId int `json:"trial_id"`
+	// The ID of the generation
+	Generation int `json:"generation"`
+	// The ID of the species related to the event
+	SpeciesId int `json:"species_id,omitempty"`
+	// The ID of the champion's genome
+	GenomeId int `json:"genome_id,omitempty"`
+	// The event specific count, e.g. the number of stolen babies or the number of species in the generation
+	Count int `json:"count,omitempty"`
+	// The fitness score of the generation's champion
+	Fitness float64 `json:"fitness,omitempty"`
+	// The mean fitness score of the population's organisms
+	MeanFitness float64 `json:"mean_fitness,omitempty"`
+	// The standard deviation of fitness scores of the population's organisms
+	StandardDev float64 `json:"standard_dev,omitempty"`
+	// The number of organisms evaluated in the generation
+	Evaluations int `json:"evaluations,omitempty"`
+	// The complexity of the champion's phenotype
+	Complexity int `json:"complexity,omitempty"`
+	// The flag to indicate whether solution was found
+	Solved bool `json:"solved,omitempty"`
+	// The reason of the trial's termination
+	Reason string `json:"reason,omitempty"`
+	// The path to the written checkpoint file
+	Path string `json:"path,omitempty"`
+}
+
+// EventObserver defines observer to be notified about events of the experiment's execution
+type EventObserver interface {
+	// OnEvent invoked to notify about event. The notifications are serialized by the EventBus, thus observer
+	// is not required to be thread-safe.
+	OnEvent(event Event)
+}
+
+// EventObserverFunc the function adapter to the EventObserver interface
+type EventObserverFunc func(event Event)
+
+// OnEvent Implements EventObserver
+func (f EventObserverFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// EventBus delivers experiment's events to all subscribed observers. It is safe for concurrent use and
+// the nil bus silently discards all events.
+type EventBus struct {
+	observers []EventObserver
+	mutex     sync.Mutex
+}
+
+// NewEventBus Creates new event bus with provided observers subscribed
+func NewEventBus(observers ...EventObserver) *EventBus {
+	return &EventBus{observers: observers}
+}
+
+// Subscribe Adds provided observer to be notified about all subsequent events
+func (b *EventBus) Subscribe(observer EventObserver) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.observers = append(b.observers, observer)
+}
+
+// Publish Delivers provided event to all subscribed observers in order of subscription. The event's Time
+// is set to the current time if missed.
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, o := range b.observers {
+		o.OnEvent(event)
+	}
+}
+
+// eventBusKey is the key for EventBus values in Contexts
+type eventBusKey struct{}
+
+// NewEventBusContext returns a new Context that carries provided event bus. The evaluators can use it to publish
+// their own events, e.g. EventCheckpointWritten.
+func NewEventBusContext(ctx context.Context, bus *EventBus) context.Context {
+	return context.WithValue(ctx, eventBusKey{}, bus)
+}
+
+// EventBusFromContext returns the EventBus stored in ctx, if any.
+func EventBusFromContext(ctx context.Context) (*EventBus, bool) {
+	bus, ok := ctx.Value(eventBusKey{}).(*EventBus)
+	return bus, ok && bus != nil
+}
+
+// JSONLEventObserver the EventObserver which appends every event as JSON object on a separate line to the file.
+type JSONLEventObserver struct {
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewJSONLEventObserver Creates new observer which appends events to the EventsLogFileName file in the given output
+// directory. The file is created if not exists.
+func NewJSONLEventObserver(outDir string) (*JSONLEventObserver, error) {
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(outDir, EventsLogFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &JSONLEventObserver{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// OnEvent Implements EventObserver
+func (o *JSONLEventObserver) OnEvent(event Event) {
+	if err := o.encoder.Encode(event); err != nil {
+		neat.ErrorLog(fmt.Sprintf("Failed to write event: %s, reason: %s", event.Type, err))
+	}
+}
+
+// Close Closes the underlying file
+func (o *JSONLEventObserver) Close() error {
+	return o.file.Close()
+}
+
+// populationListener Creates the population listener which publishes population's events of the given trial into
+// the bus. The generation ID of events is read from the provided pointer.
+func populationListener(bus *EventBus, trialId int, generationId *int) genetics.PopulationListener {
+	return func(pe genetics.PopulationEvent) {
+		bus.Publish(Event{
+			Type:       EventType(pe.Type),
+			TrialId:    trialId,
+			Generation: *generationId,
+			SpeciesId:  pe.SpeciesId,
+			Count:      pe.Count,
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package experiment
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recordingEventObserver stores all received events
+type recordingEventObserver struct {
+	events []Event
+}
+
+func (o *recordingEventObserver) OnEvent(event Event) {
+	o.events = append(o.events, event)
+}
+
+func (o *recordingEventObserver) count(eventType EventType) int {
+	count := 0
+	for _, e := range o.events {
+		if e.Type == eventType {
+			count++
+		}
+	}
+	return count
+}
+
+func TestEventBus_Publish(t *testing.T) {
+	first := &recordingEventObserver{}
+	calls := 0
+	bus := NewEventBus(first)
+	bus.Subscribe(EventObserverFunc(func(event Event) {
+		calls++
+	}))
+
+	bus.Publish(Event{Type: EventTrialStarted, TrialId: 2})
+	require.Len(t, first.events, 1)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, EventTrialStarted, first.events[0].Type)
+	assert.Equal(t, 2, first.events[0].TrialId)
+	assert.False(t, first.events[0].Time.IsZero(), "event time expected to be set")
+
+	// nil bus discards events
+	var nilBus *EventBus
+	assert.NotPanics(t, func() {
+		nilBus.Publish(Event{Type: EventTrialStarted})
+	})
+}
+
+func TestEventBusFromContext(t *testing.T) {
+	bus := NewEventBus()
+	found, ok := EventBusFromContext(NewEventBusContext(context.Background(), bus))
+	assert.True(t, ok)
+	assert.Equal(t, bus, found)
+
+	_, ok = EventBusFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestJSONLEventObserver(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "jsonl_events")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	observer, err := NewJSONLEventObserver(outDir)
+	require.NoError(t, err, "failed to create observer")
+	events := []Event{
+		{Type: EventTrialStarted, TrialId: 1},
+		{Type: EventBabiesStolen, TrialId: 1, Generation: 3, SpeciesId: 2, Count: 10},
+		{Type: EventCheckpointWritten, TrialId: 1, Generation: 3, Path: "out/1/gen_3"},
+	}
+	bus := NewEventBus(observer)
+	for _, e := range events {
+		bus.Publish(e)
+	}
+	require.NoError(t, observer.Close())
+
+	file, err := os.Open(filepath.Join(outDir, EventsLogFileName))
+	require.NoError(t, err, "failed to open events log")
+	defer func() {
+		_ = file.Close()
+	}()
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event), "failed to decode line: %d", lines)
+		event.Time = events[lines].Time
+		assert.Equal(t, events[lines], event)
+		lines++
+	}
+	assert.Equal(t, len(events), lines)
+}
+
+func TestExperiment_Execute_Events(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "execute_events")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 2
+	opts.NumGenerations = 3
+	opts.PrintEvery = 1
+
+	observer := &recordingEventObserver{}
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), outDir, "test", 2)
+	exp := Experiment{
+		Id:     1,
+		Events: NewEventBus(observer),
+	}
+	err = exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	assert.Equal(t, opts.NumRuns, observer.count(EventTrialStarted))
+	assert.Equal(t, opts.NumRuns, observer.count(EventTrialFinished))
+	assert.Equal(t, opts.NumRuns*opts.NumGenerations, observer.count(EventGenerationEvaluated))
+	assert.Equal(t, opts.NumRuns*opts.NumGenerations, observer.count(EventCheckpointWritten))
+	assert.True(t, observer.count(EventSpeciesCreated) >= opts.NumRuns, "species creation events expected")
+	assert.True(t, observer.count(EventNewChampion) >= opts.NumRuns, "new champion events expected")
+
+	// check events order of the first trial
+	require.True(t, len(observer.events) > 0)
+	assert.Equal(t, EventTrialStarted, observer.events[0].Type)
+	last := observer.events[len(observer.events)-1]
+	assert.Equal(t, EventTrialFinished, last.Type)
+	assert.Equal(t, TrialStopReasonMaxGenerations, last.Reason)
+	assert.Equal(t, opts.NumGenerations, last.Count)
+}
@@ -3,6 +3,7 @@ package experiment
 import (
 	"encoding/gob"
 	"fmt"
+	"github.com/pkg/errors"
 	"github.com/sbinet/npyio/npz"
 	"github.com/yaricom/goNEAT/v2/neat/genetics"
 	"gonum.org/v1/gonum/mat"
@@ -23,6 +24,15 @@ type Experiment struct {
 	// It is used to normalize fitness score value used in efficiency score calculation. If this value
 	// is not set, than fitness score will not be normalized during efficiency score estimation.
 	MaxFitnessScore float64
+	// The optional criterion to stop trial before the maximal number of generations evaluated. The trial is always
+	// stopped when solution found.
+	TerminationCriterion TerminationCriterion
+	// The maximal number of trials executed concurrently. If less than two, the trials are executed sequentially.
+	MaxConcurrentTrials int
+	// The optional bus to publish events of the experiment's execution to the subscribed observers
+	Events *EventBus
+	// The flag to run structural analysis of each generation champion, see Generation.AnalyzeBest
+	AnalyzeChampions bool
 }
 
 // AvgTrialDuration Calculates average duration of experiment's trial
@@ -318,8 +328,27 @@ func (e *Experiment) Write(w io.Writer) error {
 	return e.Encode(enc)
 }
 
+// The GOB encoding format versions. The legacy format has no version marker and starts with the experiment Id.
+const (
+	formatVersionLegacy = 0
+	// formatVersion1 adds the trial stop reason and seed, the generation evaluation counters, timings,
+	// raw champion fitness and complexity, and the champion structural analysis.
+	formatVersion1 = 1
+
+	// formatVersion is the version of the format written by Encode
+	formatVersion = formatVersion1
+	// formatMarker is encoded before the format version to distinguish it from the legacy experiment Id
+	formatMarker = math.MinInt32
+)
+
 // Encode Encodes experiment with GOB encoding
 func (e *Experiment) Encode(enc *gob.Encoder) error {
+	if err := enc.Encode(formatMarker); err != nil {
+		return err
+	}
+	if err := enc.Encode(formatVersion); err != nil {
+		return err
+	}
 	if err := enc.Encode(e.Id); err != nil {
 		return err
 	}
@@ -345,11 +374,23 @@ func (e *Experiment) Read(r io.Reader) error {
 	return e.Decode(dec)
 }
 
-// Decode Decodes experiment data
+// Decode Decodes experiment data. The data encoded in the legacy format without version marker is supported.
 func (e *Experiment) Decode(dec *gob.Decoder) error {
 	if err := dec.Decode(&e.Id); err != nil {
 		return err
 	}
+	version := formatVersionLegacy
+	if e.Id == formatMarker {
+		if err := dec.Decode(&version); err != nil {
+			return err
+		}
+		if version > formatVersion {
+			return errors.Errorf("unsupported experiment data format version: %d", version)
+		}
+		if err := dec.Decode(&e.Id); err != nil {
+			return err
+		}
+	}
 	if err := dec.Decode(&e.Name); err != nil {
 		return err
 	}
@@ -363,7 +404,7 @@ func (e *Experiment) Decode(dec *gob.Decoder) error {
 	e.Trials = make([]Trial, tNum)
 	for i := 0; i < tNum; i++ {
 		trial := Trial{}
-		if err := trial.Decode(dec); err != nil {
+		if err := trial.decode(dec, version); err != nil {
 			return err
 		}
 		e.Trials[i] = trial
@@ -380,6 +421,10 @@ func (e *Experiment) Decode(dec *gob.Decoder) error {
 // - trial_[0...n]_epoch_best_fitnesses - the best fitness scores per epoch per trial
 // the same for AGE and COMPLEXITY per epoch per trial
 // - trial_[0...n]_epoch_diversity - the number of species per epoch per trial
+// - trial_[0...n]_epoch_timings - the durations in seconds of evaluation, fitness adjustment, reproduction, speciation,
+// total and maximal reproduction of species per epoch per trial
+// - trial_[0...n]_epoch_reproduction_counts - the numbers of add node, add link, connect sensors, link weights,
+// non-structural mutations, and multipoint, multipoint average, single point, interspecies matings per epoch per trial
 func (e *Experiment) WriteNPZ(w io.Writer) error {
 	// write general statistics
 	trialsFitness := mat.NewDense(len(e.Trials), 2, nil)    // mean, var
@@ -426,10 +471,56 @@ func (e *Experiment) WriteNPZ(w io.Writer) error {
 		if err := out.Write(fmt.Sprintf("trial_%d_epoch_diversity", i), t.Diversity()); err != nil {
 			return err
 		}
+		if len(t.Generations) == 0 {
+			continue
+		}
+		if err := out.Write(fmt.Sprintf("trial_%d_epoch_timings", i), epochTimings(&t)); err != nil {
+			return err
+		}
+		if err := out.Write(fmt.Sprintf("trial_%d_epoch_reproduction_counts", i), epochReproductionCounts(&t)); err != nil {
+			return err
+		}
 	}
 	return out.Close()
 }
 
+// epochTimings Returns matrix with durations of the generation phases in seconds per epoch of the trial
+func epochTimings(t *Trial) *mat.Dense {
+	timings := mat.NewDense(len(t.Generations), 6, nil)
+	for i, g := range t.Generations {
+		speciesTotal, speciesMax := time.Duration(0), time.Duration(0)
+		for _, d := range g.EpochStats.SpeciesReproduction {
+			speciesTotal += d
+			if d > speciesMax {
+				speciesMax = d
+			}
+		}
+		timings.SetRow(i, []float64{
+			g.EvaluationDuration.Seconds(),
+			g.EpochStats.FitnessAdjustment.Seconds(),
+			g.EpochStats.Reproduction.Seconds(),
+			g.EpochStats.Speciation.Seconds(),
+			speciesTotal.Seconds(),
+			speciesMax.Seconds(),
+		})
+	}
+	return timings
+}
+
+// epochReproductionCounts Returns matrix with numbers of mutations and matings by type per epoch of the trial
+func epochReproductionCounts(t *Trial) *mat.Dense {
+	counts := mat.NewDense(len(t.Generations), 9, nil)
+	for i, g := range t.Generations {
+		m, mt := g.EpochStats.Mutations, g.EpochStats.Matings
+		counts.SetRow(i, []float64{
+			float64(m.AddNode), float64(m.AddLink), float64(m.ConnectSensors), float64(m.LinkWeights),
+			float64(m.NonStructural), float64(mt.Multipoint), float64(mt.MultipointAvg), float64(mt.SinglePoint),
+			float64(mt.Interspecies),
+		})
+	}
+	return counts
+}
+
 // Experiments is a sortable list of experiments by execution time and Id
 type Experiments []Experiment
 
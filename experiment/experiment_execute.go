@@ -5,113 +5,315 @@ import (
 	"fmt"
 	"github.com/yaricom/goNEAT/v2/neat"
 	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"math"
+	"math/rand"
+	"sync"
 	"time"
 )
 
-// Execute is to run specific experiment using provided startGenome and specific evaluator for each epoch of the experiment
+// Execute is to run specific experiment using provided startGenome and specific evaluator for each epoch of the experiment.
+// If MaxConcurrentTrials is greater than one, the trials are executed concurrently and provided evaluator and trial
+// observer must be safe for concurrent use. If RandSeed is set, each trial has its own generator of random numbers
+// seeded with TrialSeed, thus trials are reproducible even when executed concurrently. The generator is available to
+// the evaluator through the context, see neat.RandFromContext. The trial observer notifications are serialized, thus
+// observer itself is not required to be thread-safe. If Events bus is set, the events of execution are published to it
+// and the bus is also available to the evaluator through the context, see EventBusFromContext.
 func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return neat.ErrNEATOptionsNotFound
 	}
+	if e.Events != nil {
+		ctx = NewEventBusContext(ctx, e.Events)
+	}
+
+	contextEvaluator := NewContextGenerationEvaluator(evaluator)
 
 	if e.Trials == nil {
 		e.Trials = make(Trials, opts.NumRuns)
 	}
 
-	for run := 0; run < opts.NumRuns; run++ {
-		trialStartTime := time.Now()
-
-		neat.InfoLog("\n>>>>> Spawning new population ")
-		pop, err := genetics.NewPopulation(startGenome, opts)
-		if err != nil {
-			neat.InfoLog("Failed to spawn new population from start genome")
-			return err
-		} else {
-			neat.InfoLog("OK <<<<<")
-		}
-		neat.InfoLog(">>>>> Verifying spawned population ")
-		_, err = pop.Verify()
-		if err != nil {
-			neat.ErrorLog("\n!!!!! Population verification failed !!!!!")
-			return err
-		} else {
-			neat.InfoLog("OK <<<<<")
-		}
+	if e.MaxConcurrentTrials > 1 && opts.NumRuns > 1 {
+		return e.executeConcurrently(ctx, opts, startGenome, contextEvaluator, trialObserver)
+	}
 
-		// create appropriate population's epoch executor
-		epochExecutor, err := epochExecutorForContext(opts)
+	for run := 0; run < opts.NumRuns; run++ {
+		trial, err := e.executeTrial(ctx, run, e.TrialSeed(run), opts, startGenome, contextEvaluator, trialObserver)
 		if err != nil {
 			return err
 		}
 
-		// start new trial
-		trial := Trial{
-			Id: run,
-		}
+		// store trial into experiment
+		e.Trials[run] = *trial
 
+		// notify trial observer
 		if trialObserver != nil {
-			trialObserver.TrialRunStarted(&trial) // optional
+			trialObserver.TrialRunFinished(trial)
 		}
+	}
 
-		for generationId := 0; generationId < opts.NumGenerations; generationId++ {
-			// check if context was canceled
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			default:
-			}
+	return nil
+}
 
-			neat.InfoLog(fmt.Sprintf(">>>>> Generation:%3d\tRun: %d\n", generationId, run))
-			generation := Generation{
-				Id:      generationId,
-				TrialId: run,
-			}
-			genStartTime := time.Now()
-			err = evaluator.GenerationEvaluate(pop, &generation, opts)
-			if err != nil {
-				neat.InfoLog(fmt.Sprintf("!!!!! Generation [%d] evaluation failed !!!!!\n", generationId))
-				return err
-			}
-			generation.Executed = time.Now()
+// TrialSeed Returns the deterministic seed of the random numbers generator for the trial with given ID, which is
+// derived from the RandSeed of this experiment. Returns zero if RandSeed is not set.
+func (e *Experiment) TrialSeed(run int) int64 {
+	if e.RandSeed == 0 {
+		return 0
+	}
+	return e.RandSeed + int64(run)
+}
+
+// executeConcurrently Executes trials of the experiment concurrently using at most MaxConcurrentTrials workers.
+func (e *Experiment) executeConcurrently(ctx context.Context, opts *neat.Options, startGenome *genetics.Genome,
+	evaluator ContextGenerationEvaluator, trialObserver TrialRunObserver) error {
+	if trialObserver != nil {
+		trialObserver = &synchronizedTrialObserver{observer: trialObserver}
+	}
+
+	// the first failed trial cancels all others
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-			// Turnover population of organisms to the next epoch if appropriate
-			if !generation.Solved {
-				neat.DebugLog(">>>>> start next generation")
-				err = epochExecutor.NextEpoch(ctx, generationId, pop)
+	runs := make(chan int)
+	errs := make(chan error, opts.NumRuns)
+	var wg sync.WaitGroup
+	workers := e.MaxConcurrentTrials
+	if workers > opts.NumRuns {
+		workers = opts.NumRuns
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for run := range runs {
+				if ctx.Err() != nil {
+					continue
+				}
+				trial, err := e.executeTrial(ctx, run, e.TrialSeed(run), opts, startGenome, evaluator, trialObserver)
 				if err != nil {
-					neat.InfoLog(fmt.Sprintf("!!!!! Epoch execution failed in generation [%d] !!!!!\n", generationId))
-					return err
+					errs <- err
+					cancel()
+					continue
+				}
+				// each trial has its own slot, thus no synchronization required
+				e.Trials[run] = *trial
+
+				if trialObserver != nil {
+					trialObserver.TrialRunFinished(trial)
 				}
 			}
+		}()
+	}
+
+	for run := 0; run < opts.NumRuns; run++ {
+		runs <- run
+	}
+	close(runs)
+	wg.Wait()
+	close(errs)
 
-			// Set generation duration, which also includes preparation for the next epoch
-			generation.Duration = generation.Executed.Sub(genStartTime)
-			trial.Generations = append(trial.Generations, generation)
+	// return the first error if any
+	if err := <-errs; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
 
-			// notify trial observer
-			if trialObserver != nil {
-				trialObserver.EpochEvaluated(&trial, &generation)
-			}
+// executeTrial Executes one trial of the experiment with given ID and the seed of random numbers generator. If seed
+// is zero, the global random numbers generator is used. The trial observer is notified about trial start and each
+// epoch evaluated, but not about trial finish.
+func (e *Experiment) executeTrial(ctx context.Context, run int, seed int64, opts *neat.Options, startGenome *genetics.Genome,
+	evaluator ContextGenerationEvaluator, trialObserver TrialRunObserver) (*Trial, error) {
+	trialStartTime := time.Now()
 
-			if generation.Solved {
-				// stop further evaluation if already solved
-				neat.InfoLog(fmt.Sprintf(">>>>> The winner organism found in [%d] generation, fitness: %f <<<<<\n",
-					generationId, generation.Best.Fitness))
-				break
+	// the logger of the trial is passed down to the evaluator
+	logger := neat.LoggerFromContext(ctx).With(neat.LogKeyTrial, run)
+	ctx = neat.NewLoggerContext(ctx, logger)
+	if seed != 0 {
+		// the trial has its own generator of random numbers to be reproducible regardless of other trials
+		ctx = neat.NewRandContext(ctx, rand.New(rand.NewSource(seed)))
+	}
+
+	logger.Info(">>>>> Spawning new population ")
+	pop, err := genetics.NewPopulationContext(ctx, startGenome)
+	if err != nil {
+		logger.Info("Failed to spawn new population from start genome")
+		return nil, err
+	} else {
+		logger.Info("OK <<<<<")
+	}
+	logger.Info(">>>>> Verifying spawned population ")
+	_, err = pop.Verify()
+	if err != nil {
+		logger.Error("!!!!! Population verification failed !!!!!")
+		return nil, err
+	} else {
+		logger.Info("OK <<<<<")
+	}
+
+	// create appropriate population's epoch executor
+	epochExecutor, err := epochExecutorForContext(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// start new trial
+	trial := Trial{
+		Id:         run,
+		StopReason: TrialStopReasonMaxGenerations,
+		Seed:       seed,
+	}
+
+	if trialObserver != nil {
+		trialObserver.TrialRunStarted(&trial) // optional
+	}
+
+	// publish events of the population, including species of the initial population
+	generationId := 0
+	if e.Events != nil {
+		e.Events.Publish(Event{Type: EventTrialStarted, TrialId: run})
+		for _, sp := range pop.Species {
+			e.Events.Publish(Event{Type: EventSpeciesCreated, TrialId: run, SpeciesId: sp.Id, Count: len(sp.Organisms)})
+		}
+		pop.SetListener(populationListener(e.Events, run, &generationId))
+	}
+	championFitness := math.Inf(-1)
+
+	for ; generationId < opts.NumGenerations; generationId++ {
+		// check if context was canceled
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		genLogger := logger.With(neat.LogKeyGeneration, generationId)
+		genLogger.Info(fmt.Sprintf(">>>>> Generation:%3d\tRun: %d", generationId, run))
+		generation := Generation{
+			Id:      generationId,
+			TrialId: run,
+		}
+		genStartTime := time.Now()
+		err = evaluator.GenerationEvaluateContext(neat.NewLoggerContext(ctx, genLogger), pop, &generation)
+		if err != nil {
+			genLogger.Info("!!!!! Generation evaluation failed !!!!!", "error", err)
+			return nil, err
+		}
+		generation.Executed = time.Now()
+		generation.EvaluationDuration = generation.Executed.Sub(genStartTime)
+		generation.Evaluations = len(pop.Organisms)
+		// the evaluator may set the best organism without filling population statistics
+		generation.recordBest()
+		pop.UpdateFitnessStatistics()
+		if e.AnalyzeChampions {
+			generation.AnalyzeBest()
+		}
+		if e.Events != nil {
+			// publish before the next epoch which renumbers organisms of the population
+			championFitness = e.publishGenerationEvents(&generation, pop, championFitness)
+		}
+
+		// Turnover population of organisms to the next epoch if appropriate
+		if !generation.Solved {
+			genLogger.Debug(">>>>> start next generation")
+			err = epochExecutor.NextEpoch(ctx, generationId, pop)
+			if err != nil {
+				genLogger.Info("!!!!! Epoch execution failed !!!!!", "error", err)
+				return nil, err
 			}
+			generation.EpochStats = pop.EpochStats
 		}
-		// holds trial duration
-		trial.Duration = time.Since(trialStartTime)
 
-		// store trial into experiment
-		e.Trials[run] = trial
+		// Set generation duration, which also includes preparation for the next epoch
+		generation.Duration = time.Since(genStartTime)
+		trial.Generations = append(trial.Generations, generation)
+		// the elapsed time of running trial including population spawning and all epochs
+		trial.Duration = time.Since(trialStartTime)
 
 		// notify trial observer
 		if trialObserver != nil {
-			trialObserver.TrialRunFinished(&trial)
+			trialObserver.EpochEvaluated(&trial, &generation)
+		}
+
+		if generation.Solved {
+			// stop further evaluation if already solved
+			genLogger.Info(fmt.Sprintf(">>>>> The winner organism found in [%d] generation, fitness: %f <<<<<",
+				generationId, generation.Best.Fitness), neat.LogKeyOrganism, generation.Best.Genotype.Id)
+			trial.StopReason = TrialStopReasonSolved
+			break
+		}
+		if e.TerminationCriterion != nil {
+			if stop, reason := e.TerminationCriterion.ShouldTerminate(&trial, &generation); stop {
+				genLogger.Info(fmt.Sprintf(">>>>> The trial [%d] stopped in [%d] generation: %s <<<<<",
+					run, generationId, reason))
+				trial.StopReason = reason
+				break
+			}
 		}
 	}
+	// holds trial duration
+	trial.Duration = time.Since(trialStartTime)
 
-	return nil
+	if e.Events != nil {
+		e.Events.Publish(Event{Type: EventTrialFinished, TrialId: run, Generation: len(trial.Generations) - 1,
+			Count: len(trial.Generations), Solved: trial.Solved(), Reason: trial.StopReason})
+	}
+
+	return &trial, nil
+}
+
+// publishGenerationEvents Publishes events about evaluated generation and its champion if it outperforms champions
+// of previous generations with given best fitness. Returns the best fitness found so far in the trial.
+func (e *Experiment) publishGenerationEvents(generation *Generation, pop *genetics.Population, championFitness float64) float64 {
+	event := Event{
+		Type:        EventGenerationEvaluated,
+		TrialId:     generation.TrialId,
+		Generation:  generation.Id,
+		Count:       len(pop.Species),
+		MeanFitness: pop.MeanFitness,
+		StandardDev: pop.StandardDev,
+		Evaluations: generation.Evaluations,
+		Solved:      generation.Solved,
+	}
+	if best := generation.Best; best != nil {
+		event.GenomeId = best.Genotype.Id
+		event.Fitness = best.Fitness
+		if best.Phenotype != nil {
+			event.Complexity = best.Phenotype.Complexity()
+		}
+	}
+	e.Events.Publish(event)
+
+	if generation.Best != nil && generation.Best.Fitness > championFitness {
+		event.Type = EventNewChampion
+		event.Count, event.MeanFitness, event.StandardDev, event.Evaluations = 0, 0, 0, 0
+		e.Events.Publish(event)
+		return generation.Best.Fitness
+	}
+	return championFitness
+}
+
+// synchronizedTrialObserver the TrialRunObserver which serializes notifications of the wrapped observer
+type synchronizedTrialObserver struct {
+	observer TrialRunObserver
+	mutex    sync.Mutex
+}
+
+func (o *synchronizedTrialObserver) TrialRunStarted(trial *Trial) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.observer.TrialRunStarted(trial)
+}
+
+func (o *synchronizedTrialObserver) TrialRunFinished(trial *Trial) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.observer.TrialRunFinished(trial)
+}
+
+func (o *synchronizedTrialObserver) EpochEvaluated(trial *Trial, epoch *Generation) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.observer.EpochEvaluated(trial, epoch)
 }
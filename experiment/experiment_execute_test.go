@@ -0,0 +1,194 @@
+package experiment
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"os"
+	"testing"
+	"time"
+)
+
+// countingTrialObserver counts notifications without any synchronization
+type countingTrialObserver struct {
+	started, finished, epochs int
+}
+
+func (o *countingTrialObserver) TrialRunStarted(_ *Trial) {
+	o.started++
+}
+
+func (o *countingTrialObserver) TrialRunFinished(_ *Trial) {
+	o.finished++
+}
+
+func (o *countingTrialObserver) EpochEvaluated(_ *Trial, _ *Generation) {
+	o.epochs++
+}
+
+func loadTestOptionsAndGenome(t *testing.T) (*neat.Options, *genetics.Genome) {
+	optFile, err := os.Open("../data/xor_test.neat")
+	require.NoError(t, err, "failed to open options file")
+	opts, err := neat.LoadNeatOptions(optFile)
+	require.NoError(t, err, "failed to load options")
+
+	genomeFile, err := os.Open("../data/xorstartgenes")
+	require.NoError(t, err, "failed to open genome file")
+	startGenome, err := genetics.ReadGenome(genomeFile, 1)
+	require.NoError(t, err, "failed to read start genome")
+	return opts, startGenome
+}
+
+func newNeverSolvingEvaluator() *testOrganismEvaluator {
+	return &testOrganismEvaluator{winnerId: -1, failingId: -1, panicId: -1, slowId: -1, evaluated: make(map[int]int)}
+}
+
+func TestExperiment_Execute_ConcurrentTrials(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 6
+
+	observer := &countingTrialObserver{}
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	exp := Experiment{
+		Id:                   1,
+		TerminationCriterion: EvaluationsBudget(opts.PopSize * 2),
+		MaxConcurrentTrials:  3,
+	}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, observer)
+	require.NoError(t, err, "failed to execute experiment")
+
+	require.Len(t, exp.Trials, opts.NumRuns)
+	for i, trial := range exp.Trials {
+		assert.Equal(t, i, trial.Id, "trials must be stored in order")
+		assert.Zero(t, trial.Seed, "no seed expected without RandSeed")
+		assert.Len(t, trial.Generations, 2)
+		for _, g := range trial.Generations {
+			assert.Equal(t, i, g.TrialId)
+		}
+	}
+	assert.Equal(t, opts.NumRuns, observer.started)
+	assert.Equal(t, opts.NumRuns, observer.finished)
+	assert.Equal(t, opts.NumRuns*2, observer.epochs)
+}
+
+func TestExperiment_Execute_ConcurrentTrials_Seeded(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 3
+
+	execute := func(concurrentTrials int) Trials {
+		exp := Experiment{
+			RandSeed:             42,
+			TerminationCriterion: EvaluationsBudget(opts.PopSize * 3),
+			MaxConcurrentTrials:  concurrentTrials,
+		}
+		evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+		err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+		require.NoError(t, err, "failed to execute experiment")
+		require.Len(t, exp.Trials, opts.NumRuns)
+		return exp.Trials
+	}
+	sequential := execute(1)
+	concurrent := execute(3)
+	for i := range concurrent {
+		assert.Equal(t, int64(42+i), concurrent[i].Seed, "wrong seed of trial: %d", i)
+		require.Len(t, concurrent[i].Generations, len(sequential[i].Generations))
+		for j, g := range concurrent[i].Generations {
+			expected := sequential[i].Generations[j]
+			assert.Equal(t, expected.Complexity, g.Complexity, "trial: %d, generation: %d", i, j)
+			assert.Equal(t, expected.Diversity, g.Diversity, "trial: %d, generation: %d", i, j)
+			assert.Equal(t, expected.Best.Genotype.String(), g.Best.Genotype.String(), "trial: %d, generation: %d", i, j)
+		}
+	}
+}
+
+func TestExperiment_Execute_ConcurrentTrials_Canceled(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 4
+
+	ctx, cancel := context.WithCancel(neat.NewContext(context.Background(), opts))
+	cancel()
+	exp := Experiment{MaxConcurrentTrials: 2}
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	err := exp.Execute(ctx, startGenome, evaluator, nil)
+	assert.EqualError(t, err, context.Canceled.Error())
+}
+
+func TestExperiment_Execute_TrialSeeds(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 2
+
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 1)
+	execute := func() *Experiment {
+		exp := Experiment{RandSeed: 42, TerminationCriterion: EvaluationsBudget(opts.PopSize * 2)}
+		err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+		require.NoError(t, err, "failed to execute experiment")
+		return &exp
+	}
+	first, second := execute(), execute()
+	for i := range first.Trials {
+		assert.Equal(t, int64(42+i), first.Trials[i].Seed)
+		assert.Equal(t, first.Trials[i].BestFitness(), second.Trials[i].BestFitness(),
+			"sequential trials must be reproducible")
+		assert.Equal(t, first.Trials[i].Diversity(), second.Trials[i].Diversity())
+	}
+	assert.Zero(t, (&Experiment{}).TrialSeed(1), "no seed expected without RandSeed")
+}
+
+func TestExperiment_Execute_EpochStats(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 1
+	opts.NumGenerations = 3
+
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	exp := Experiment{Id: 1}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	require.Len(t, exp.Trials[0].Generations, opts.NumGenerations)
+	for _, g := range exp.Trials[0].Generations {
+		assert.Nil(t, g.BestStructure, "structural analysis is not requested")
+		assert.True(t, g.EvaluationDuration > 0, "evaluation duration expected")
+		assert.True(t, g.EpochStats.Reproduction > 0, "reproduction duration expected")
+		assert.True(t, g.EpochStats.Speciation > 0, "speciation duration expected")
+		assert.NotEmpty(t, g.EpochStats.SpeciesReproduction, "species reproduction durations expected")
+		assert.True(t, g.EpochStats.Mutations.Total()+g.EpochStats.Matings.Total() > 0, "reproduction counts expected")
+	}
+}
+
+func TestExperiment_Execute_WallClockBudget(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 1
+	opts.NumGenerations = 3
+
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	exp := Experiment{Id: 1, TerminationCriterion: WallClockBudget(time.Nanosecond)}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	trial := exp.Trials[0]
+	require.Len(t, trial.Generations, 1, "trial must be stopped after the first generation")
+	assert.Equal(t, "wall-clock budget 1ns exhausted", trial.StopReason)
+	assert.True(t, trial.Duration >= trial.GenerationsDuration(), "trial duration must include all generations")
+	g := trial.Generations[0]
+	epochDuration := g.EpochStats.FitnessAdjustment + g.EpochStats.Reproduction + g.EpochStats.Speciation
+	assert.True(t, g.Duration >= g.EvaluationDuration+epochDuration, "generation duration must include the epoch")
+}
+
+func TestExperiment_Execute_AnalyzeChampions(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 1
+	opts.NumGenerations = 2
+
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	exp := Experiment{Id: 1, AnalyzeChampions: true}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	require.Len(t, exp.Trials[0].Generations, opts.NumGenerations)
+	for _, g := range exp.Trials[0].Generations {
+		require.NotNil(t, g.BestStructure, "structural analysis of champion expected")
+		assert.True(t, g.BestStructure.Nodes > 0)
+	}
+}
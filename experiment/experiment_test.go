@@ -2,8 +2,13 @@ package experiment
 
 import (
 	"bytes"
+	"encoding/gob"
+	"fmt"
+	"github.com/sbinet/npyio/npz"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"gonum.org/v1/gonum/mat"
 	"testing"
 )
 
@@ -33,3 +38,116 @@ func TestExperiment_Write_Read(t *testing.T) {
 		assert.EqualValues(t, ex.Trials[i], newEx.Trials[i])
 	}
 }
+
+func TestExperiment_Read_legacyFormat(t *testing.T) {
+	ex := Experiment{Id: 1, Name: "Test Legacy Decode", Trials: make(Trials, 2)}
+	for i := 0; i < len(ex.Trials); i++ {
+		ex.Trials[i] = *buildTestTrial(i+1, 3)
+	}
+
+	// Write experiment in the layout used before the format version was introduced
+	var buff bytes.Buffer
+	err := encodeLegacyExperiment(gob.NewEncoder(&buff), &ex)
+	require.NoError(t, err, "Failed to write legacy experiment")
+
+	// Read experiment
+	newEx := Experiment{}
+	err = newEx.Read(&buff)
+	require.NoError(t, err, "failed to read legacy experiment")
+
+	assert.Equal(t, ex.Id, newEx.Id)
+	assert.Equal(t, ex.Name, newEx.Name)
+	require.Len(t, newEx.Trials, len(ex.Trials))
+	for i, trial := range newEx.Trials {
+		assert.Equal(t, ex.Trials[i].Id, trial.Id)
+		assert.Empty(t, trial.StopReason)
+		assert.Zero(t, trial.Seed)
+		require.Len(t, trial.Generations, len(ex.Trials[i].Generations))
+		for j, gen := range trial.Generations {
+			expected := ex.Trials[i].Generations[j]
+			assert.Equal(t, expected.Id, gen.Id)
+			assert.Equal(t, expected.Fitness, gen.Fitness)
+			assert.Equal(t, expected.WinnerGenes, gen.WinnerGenes)
+			assert.Zero(t, gen.Evaluations)
+			assert.Nil(t, gen.BestStructure)
+			require.NotNil(t, gen.Best)
+			assert.Equal(t, expected.Best.Fitness, gen.Best.Fitness)
+			assert.Equal(t, expected.Best.Fitness, gen.BestFitness)
+		}
+	}
+}
+
+func TestExperiment_Read_unsupportedVersion(t *testing.T) {
+	var buff bytes.Buffer
+	enc := gob.NewEncoder(&buff)
+	require.NoError(t, enc.Encode(formatMarker))
+	require.NoError(t, enc.Encode(formatVersion+1))
+
+	ex := Experiment{}
+	err := ex.Read(&buff)
+	assert.EqualError(t, err, fmt.Sprintf("unsupported experiment data format version: %d", formatVersion+1))
+}
+
+// encodeLegacyExperiment Encodes experiment using the legacy format without version marker
+func encodeLegacyExperiment(enc *gob.Encoder, ex *Experiment) error {
+	for _, v := range []interface{}{ex.Id, ex.Name, len(ex.Trials)} {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	for _, trial := range ex.Trials {
+		if err := enc.Encode(trial.Id); err != nil {
+			return err
+		}
+		if err := enc.Encode(len(trial.Generations)); err != nil {
+			return err
+		}
+		for _, g := range trial.Generations {
+			for _, v := range []interface{}{g.Id, g.Executed, g.Solved, g.Fitness, g.Age, g.Complexity,
+				g.Diversity, g.WinnerEvals, g.WinnerNodes, g.WinnerGenes} {
+				if err := enc.Encode(v); err != nil {
+					return err
+				}
+			}
+			if err := encodeOrganism(enc, g.Best); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestExperiment_WriteNPZ(t *testing.T) {
+	ex := Experiment{Id: 1, Name: "Test NPZ", Trials: make(Trials, 2)}
+	for i := 0; i < len(ex.Trials); i++ {
+		trial := buildTestTrial(i+1, 3)
+		for j := range trial.Generations {
+			g := &trial.Generations[j]
+			org, err := genetics.NewOrganism(g.Best.Fitness, g.Best.Genotype, g.Id)
+			require.NoError(t, err, "failed to create organism")
+			org.Species = genetics.NewSpecies(1)
+			g.Best = org
+		}
+		ex.Trials[i] = *trial
+	}
+
+	var buff bytes.Buffer
+	err := ex.WriteNPZ(&buff)
+	require.NoError(t, err, "failed to write NPZ")
+
+	reader, err := npz.NewReader(bytes.NewReader(buff.Bytes()), int64(buff.Len()))
+	require.NoError(t, err, "failed to read NPZ")
+
+	timings := mat.Dense{}
+	err = reader.Read("trial_1_epoch_timings", &timings)
+	require.NoError(t, err, "failed to read timings")
+	rows, cols := timings.Dims()
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 6, cols)
+	assert.Equal(t, []float64{3.0, 0.001, 0.02, 0.005, 0.019, 0.012}, timings.RawRowView(0))
+
+	counts := mat.Dense{}
+	err = reader.Read("trial_1_epoch_reproduction_counts", &counts)
+	require.NoError(t, err, "failed to read reproduction counts")
+	assert.Equal(t, []float64{3, 5, 1, 0, 80, 40, 20, 2, 1}, counts.RawRowView(2))
+}
@@ -0,0 +1,336 @@
+package experiment
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// The supported formats of the experiment results files, also used as the files extensions
+const (
+	// ExportFormatNative the native binary format written by Experiment.Write
+	ExportFormatNative = "dat"
+	// ExportFormatNPZ the Numpy NPZ format written by Experiment.WriteNPZ
+	ExportFormatNPZ = "npz"
+	// ExportFormatCSV the tidy CSV format written by Experiment.WriteCSV
+	ExportFormatCSV = "csv"
+	// ExportFormatJSON the structured JSON format written by Experiment.WriteJSON
+	ExportFormatJSON = "json"
+)
+
+// ExportFormats the list of all supported formats of the experiment results files
+var ExportFormats = []string{ExportFormatNative, ExportFormatNPZ, ExportFormatCSV, ExportFormatJSON}
+
+// ValidateExportFormat Checks that provided experiment results format is supported
+func ValidateExportFormat(format string) error {
+	for _, f := range ExportFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported experiment results format: [%s]", format)
+}
+
+// WriteFiles Writes the experiment results into the output directory using each of the provided formats. The results
+// are stored into files named by the given name with extension of the format, e.g. XOR.csv.
+func (e *Experiment) WriteFiles(outDir, name string, formats []string) error {
+	for _, format := range formats {
+		var write func(io.Writer) error
+		switch format {
+		case ExportFormatNative:
+			write = e.Write
+		case ExportFormatNPZ:
+			write = e.WriteNPZ
+		case ExportFormatCSV:
+			write = e.WriteCSV
+		case ExportFormatJSON:
+			write = e.WriteJSON
+		default:
+			return ValidateExportFormat(format)
+		}
+		path := filepath.Join(outDir, fmt.Sprintf("%s.%s", name, format))
+		file, err := os.Create(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create file for experiment results: [%s]", path)
+		}
+		err = write(file)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to save experiment results as %s", format)
+		}
+	}
+	return nil
+}
+
+// csvHeader The header of the tidy CSV produced by Experiment.WriteCSV
+var csvHeader = []string{
+	"trial", "generation", "executed", "solved",
+	"best_fitness", "best_age", "best_complexity",
+	"mean_fitness", "mean_age", "mean_complexity",
+	"diversity", "duration_seconds", "evaluation_seconds", "evaluations", "failed_evaluations",
+	"winner_nodes", "winner_genes", "winner_evals",
+}
+
+// WriteCSV Writes the tidy CSV with statistics of the experiment into provided writer. The CSV has a header row followed
+// by one row per trial and generation with the fitness, age and complexity of the best organism, the mean values of
+// these among species, the species diversity, the durations of the generation and the winner statistics. The cells
+// of the best organism statistics are left empty if not available.
+func (e *Experiment) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range e.Trials {
+		for _, g := range t.Generations {
+			if err := out.Write(generationCSVRecord(t.Id, &g)); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func generationCSVRecord(trialId int, g *Generation) []string {
+	var bestFitness, bestAge, bestComplexity string
+	if g.Best != nil {
+		bestFitness = formatFloat(g.BestFitness)
+		if g.Best.Species != nil {
+			bestAge = strconv.Itoa(g.Best.Species.Age)
+		}
+		if g.Best.Phenotype != nil {
+			bestComplexity = strconv.Itoa(g.Best.Phenotype.Complexity())
+		}
+	}
+	meanFitness, meanAge, meanComplexity := g.Average()
+	return []string{
+		strconv.Itoa(trialId), strconv.Itoa(g.Id), g.Executed.Format(time.RFC3339Nano), strconv.FormatBool(g.Solved),
+		bestFitness, bestAge, bestComplexity,
+		formatFloat(meanFitness), formatFloat(meanAge), formatFloat(meanComplexity),
+		strconv.Itoa(g.Diversity), formatFloat(g.Duration.Seconds()), formatFloat(g.EvaluationDuration.Seconds()),
+		strconv.Itoa(g.Evaluations), strconv.Itoa(g.FailedEvaluations),
+		strconv.Itoa(g.WinnerNodes), strconv.Itoa(g.WinnerGenes), strconv.Itoa(g.WinnerEvals),
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// The JSON representations of the experiment's data. The organisms are stored with their genomes serialized in the
+// plain text format to avoid cyclic references between organisms, species and genomes.
+type experimentJSON struct {
+	Id              int         `json:"id"`
+	Name            string      `json:"name"`
+	RandSeed        int64       `json:"rand_seed"`
+	MaxFitnessScore float64     `json:"max_fitness_score"`
+	Trials          []trialJSON `json:"trials"`
+}
+
+type trialJSON struct {
+	Id          int              `json:"id"`
+	StopReason  string           `json:"stop_reason,omitempty"`
+	Seed        int64            `json:"seed"`
+	Duration    time.Duration    `json:"duration_ns"`
+	Generations []generationJSON `json:"generations"`
+}
+
+type generationJSON struct {
+	Id                 int                         `json:"id"`
+	Executed           time.Time                   `json:"executed"`
+	Duration           time.Duration               `json:"duration_ns"`
+	Solved             bool                        `json:"solved"`
+	Fitness            Floats                      `json:"species_fitness"`
+	Age                Floats                      `json:"species_age"`
+	Complexity         Floats                      `json:"species_complexity"`
+	Diversity          int                         `json:"diversity"`
+	WinnerEvals        int                         `json:"winner_evals"`
+	WinnerNodes        int                         `json:"winner_nodes"`
+	WinnerGenes        int                         `json:"winner_genes"`
+	Evaluations        int                         `json:"evaluations"`
+	FailedEvaluations  int                         `json:"failed_evaluations"`
+	EvaluationDuration time.Duration               `json:"evaluation_duration_ns"`
+	EpochStats         genetics.EpochStatistics    `json:"epoch_stats"`
+	BestFitness        float64                     `json:"best_fitness"`
+	BestComplexity     int                         `json:"best_complexity"`
+	BestStructure      *network.StructuralAnalysis `json:"best_structure,omitempty"`
+	Best               *organismJSON               `json:"best,omitempty"`
+}
+
+type organismJSON struct {
+	Fitness    float64 `json:"fitness"`
+	Error      float64 `json:"error"`
+	IsWinner   bool    `json:"is_winner"`
+	Generation int     `json:"generation"`
+	SpeciesId  int     `json:"species_id,omitempty"`
+	SpeciesAge int     `json:"species_age,omitempty"`
+	GenomeId   int     `json:"genome_id"`
+	Genome     string  `json:"genome"`
+}
+
+// WriteJSON Writes the structured JSON document with all data of the experiment, including per-species statistics of
+// each generation and the genomes of the best organisms, into provided writer. The document can be loaded back with
+// ReadJSON.
+func (e *Experiment) WriteJSON(w io.Writer) error {
+	doc := experimentJSON{
+		Id:              e.Id,
+		Name:            e.Name,
+		RandSeed:        e.RandSeed,
+		MaxFitnessScore: e.MaxFitnessScore,
+		Trials:          make([]trialJSON, len(e.Trials)),
+	}
+	for i, t := range e.Trials {
+		tj := trialJSON{
+			Id:          t.Id,
+			StopReason:  t.StopReason,
+			Seed:        t.Seed,
+			Duration:    t.Duration,
+			Generations: make([]generationJSON, len(t.Generations)),
+		}
+		for j, g := range t.Generations {
+			gj := generationJSON{
+				Id:                 g.Id,
+				Executed:           g.Executed,
+				Duration:           g.Duration,
+				Solved:             g.Solved,
+				Fitness:            g.Fitness,
+				Age:                g.Age,
+				Complexity:         g.Complexity,
+				Diversity:          g.Diversity,
+				WinnerEvals:        g.WinnerEvals,
+				WinnerNodes:        g.WinnerNodes,
+				WinnerGenes:        g.WinnerGenes,
+				Evaluations:        g.Evaluations,
+				FailedEvaluations:  g.FailedEvaluations,
+				EvaluationDuration: g.EvaluationDuration,
+				EpochStats:         g.EpochStats,
+				BestFitness:        g.BestFitness,
+				BestComplexity:     g.BestComplexity,
+				BestStructure:      g.BestStructure,
+			}
+			if g.Best != nil {
+				best, err := organismToJSON(g.Best)
+				if err != nil {
+					return errors.Wrapf(err, "failed to encode best organism of generation %d in trial %d", g.Id, t.Id)
+				}
+				gj.Best = best
+			}
+			tj.Generations[j] = gj
+		}
+		doc.Trials[i] = tj
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ReadJSON Reads the experiment data from the JSON document produced by WriteJSON. The best organisms are restored
+// with their phenotypes and the species holding only ID and age.
+func (e *Experiment) ReadJSON(r io.Reader) error {
+	var doc experimentJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return errors.Wrap(err, "failed to decode experiment JSON")
+	}
+	e.Id = doc.Id
+	e.Name = doc.Name
+	e.RandSeed = doc.RandSeed
+	e.MaxFitnessScore = doc.MaxFitnessScore
+	e.Trials = make(Trials, len(doc.Trials))
+	for i, tj := range doc.Trials {
+		trial := Trial{
+			Id:          tj.Id,
+			StopReason:  tj.StopReason,
+			Seed:        tj.Seed,
+			Duration:    tj.Duration,
+			Generations: make(Generations, len(tj.Generations)),
+		}
+		for j, gj := range tj.Generations {
+			g := Generation{
+				Id:                 gj.Id,
+				Executed:           gj.Executed,
+				Duration:           gj.Duration,
+				Solved:             gj.Solved,
+				Fitness:            gj.Fitness,
+				Age:                gj.Age,
+				Complexity:         gj.Complexity,
+				Diversity:          gj.Diversity,
+				WinnerEvals:        gj.WinnerEvals,
+				WinnerNodes:        gj.WinnerNodes,
+				WinnerGenes:        gj.WinnerGenes,
+				Evaluations:        gj.Evaluations,
+				FailedEvaluations:  gj.FailedEvaluations,
+				EvaluationDuration: gj.EvaluationDuration,
+				EpochStats:         gj.EpochStats,
+				BestFitness:        gj.BestFitness,
+				BestComplexity:     gj.BestComplexity,
+				BestStructure:      gj.BestStructure,
+				TrialId:            tj.Id,
+			}
+			if gj.Best != nil {
+				best, err := organismFromJSON(gj.Best)
+				if err != nil {
+					return errors.Wrapf(err, "failed to decode best organism of generation %d in trial %d", gj.Id, tj.Id)
+				}
+				g.Best = best
+			}
+			trial.Generations[j] = g
+		}
+		e.Trials[i] = trial
+	}
+	return nil
+}
+
+func organismToJSON(org *genetics.Organism) (*organismJSON, error) {
+	oj := &organismJSON{
+		Fitness:    org.Fitness,
+		Error:      org.Error,
+		IsWinner:   org.IsWinner,
+		Generation: org.Generation,
+	}
+	if org.Species != nil {
+		oj.SpeciesId = org.Species.Id
+		oj.SpeciesAge = org.Species.Age
+	}
+	if org.Genotype != nil {
+		oj.GenomeId = org.Genotype.Id
+		outBuf := bytes.NewBufferString("")
+		if err := org.Genotype.Write(outBuf); err != nil {
+			return nil, err
+		}
+		oj.Genome = outBuf.String()
+	}
+	return oj, nil
+}
+
+func organismFromJSON(oj *organismJSON) (*genetics.Organism, error) {
+	var org *genetics.Organism
+	if len(oj.Genome) > 0 {
+		genome, err := genetics.ReadGenome(bytes.NewBufferString(oj.Genome), oj.GenomeId)
+		if err != nil {
+			return nil, err
+		}
+		if org, err = genetics.NewOrganism(oj.Fitness, genome, oj.Generation); err != nil {
+			return nil, err
+		}
+	} else {
+		org = &genetics.Organism{Fitness: oj.Fitness, Generation: oj.Generation}
+	}
+	org.Error = oj.Error
+	org.IsWinner = oj.IsWinner
+	if oj.SpeciesId != 0 {
+		org.Species = genetics.NewSpecies(oj.SpeciesId)
+		org.Species.Age = oj.SpeciesAge
+	}
+	return org, nil
+}
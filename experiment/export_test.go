@@ -0,0 +1,110 @@
+package experiment
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExperiment_WriteCSV(t *testing.T) {
+	exp := Experiment{Id: 1, Name: "Test CSV", Trials: Trials{*buildTestTrial(1, 3), *buildTestTrial(2, 2)}}
+	gen := &exp.Trials[0].Generations[0]
+	gen.Duration = 1500 * time.Millisecond
+	best, err := genetics.NewOrganism(gen.Best.Fitness, gen.Best.Genotype, gen.Id)
+	require.NoError(t, err, "failed to create organism")
+	best.Species = genetics.NewSpecies(1)
+	best.Species.Age = 5
+	gen.Best = best
+
+	var buf bytes.Buffer
+	err = exp.WriteCSV(&buf)
+	require.NoError(t, err, "failed to write CSV")
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err, "failed to read CSV")
+	require.Len(t, records, 6, "header and one row per generation expected")
+	assert.Equal(t, csvHeader, records[0])
+
+	row := records[1]
+	assert.Equal(t, "1", row[0])
+	assert.Equal(t, "1", row[1])
+	assert.Equal(t, "true", row[3])
+	assert.Equal(t, strconv.FormatFloat(best.Fitness, 'g', -1, 64), row[4])
+	assert.Equal(t, "5", row[5])
+	assert.Equal(t, strconv.Itoa(best.Phenotype.Complexity()), row[6])
+	assert.Equal(t, "32", row[10])
+	assert.Equal(t, "1.5", row[11])
+	assert.Equal(t, "3", row[12])
+	assert.Equal(t, "12423", row[17])
+
+	// the best organism without species and phenotype
+	row = records[4]
+	assert.Equal(t, "2", row[0])
+	assert.Equal(t, "1", row[1])
+	assert.Empty(t, row[5])
+	assert.Empty(t, row[6])
+}
+
+func TestExperiment_WriteJSON_ReadJSON(t *testing.T) {
+	exp := Experiment{Id: 1, Name: "Test JSON", RandSeed: 42, MaxFitnessScore: 16.0,
+		Trials: Trials{*buildTestTrial(1, 3), *buildTestTrial(2, 2)}}
+	exp.Trials[0].Duration = time.Minute
+	exp.Trials[0].Generations[1].Best.Species = genetics.NewSpecies(3)
+
+	var buf bytes.Buffer
+	err := exp.WriteJSON(&buf)
+	require.NoError(t, err, "failed to write JSON")
+	assert.True(t, strings.Contains(buf.String(), `"species_fitness"`), "per-species statistics expected")
+
+	readExp := Experiment{}
+	err = readExp.ReadJSON(&buf)
+	require.NoError(t, err, "failed to read JSON")
+	assert.Equal(t, exp.Id, readExp.Id)
+	assert.Equal(t, exp.Name, readExp.Name)
+	assert.Equal(t, exp.RandSeed, readExp.RandSeed)
+	assert.Equal(t, exp.MaxFitnessScore, readExp.MaxFitnessScore)
+	require.Len(t, readExp.Trials, len(exp.Trials))
+	for i, trial := range exp.Trials {
+		readTrial := readExp.Trials[i]
+		assert.Equal(t, trial.Id, readTrial.Id)
+		assert.Equal(t, trial.StopReason, readTrial.StopReason)
+		assert.Equal(t, trial.Seed, readTrial.Seed)
+		assert.Equal(t, trial.Duration, readTrial.Duration)
+		require.Len(t, readTrial.Generations, len(trial.Generations))
+		for j, gen := range trial.Generations {
+			readGen := readTrial.Generations[j]
+			assert.True(t, gen.Executed.Equal(readGen.Executed), "executed time mismatch")
+			assert.Equal(t, trial.Id, readGen.TrialId)
+
+			require.NotNil(t, readGen.Best)
+			assert.Equal(t, gen.Best.Fitness, readGen.Best.Fitness)
+			assert.Equal(t, gen.Best.Generation, readGen.Best.Generation)
+			assert.Equal(t, gen.Best.Genotype.Id, readGen.Best.Genotype.Id)
+			assert.NotNil(t, readGen.Best.Phenotype, "phenotype expected")
+			var expected, actual bytes.Buffer
+			require.NoError(t, gen.Best.Genotype.Write(&expected))
+			require.NoError(t, readGen.Best.Genotype.Write(&actual))
+			assert.Equal(t, expected.String(), actual.String())
+
+			// compare the rest of the fields
+			gen.Best, readGen.Best = nil, nil
+			gen.Executed, readGen.Executed = time.Time{}, time.Time{}
+			gen.TrialId = trial.Id
+			assert.EqualValues(t, gen, readGen)
+		}
+	}
+	assert.Equal(t, 3, readExp.Trials[0].Generations[1].Best.Species.Id)
+	assert.Nil(t, readExp.Trials[0].Generations[0].Best.Species)
+}
+
+func TestExperiment_ReadJSON_malformed(t *testing.T) {
+	exp := Experiment{}
+	err := exp.ReadJSON(strings.NewReader(`{"id": "one"}`))
+	assert.Error(t, err)
+}
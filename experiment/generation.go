@@ -5,6 +5,7 @@ import (
 	"encoding/gob"
 	"github.com/pkg/errors"
 	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"github.com/yaricom/goNEAT/v2/neat/network"
 	"math"
 	"reflect"
 	"sort"
@@ -17,10 +18,15 @@ type Generation struct {
 	Id int
 	// The time when epoch was evaluated
 	Executed time.Time
-	// The elapsed time between generation execution start and finish
+	// The elapsed time between generation execution start and finish, including the epoch of the population
 	Duration time.Duration
 	// The best organism of best species
 	Best *genetics.Organism
+	// The fitness of the best organism as evaluated. It differs from the fitness of Best after the next epoch of
+	// population, which adjusts fitness of organisms in place.
+	BestFitness float64
+	// The complexity of the best organism's phenotype
+	BestComplexity int
 	// The flag to indicate whether experiment was solved in this epoch
 	Solved bool
 
@@ -41,6 +47,20 @@ type Generation struct {
 	// The numbers of genes (links) in winner genome or zero if not solved
 	WinnerGenes int
 
+	// The number of organisms evaluated in this generation
+	Evaluations int
+	// The number of organisms which evaluation failed (e.g. due to panic or timeout) and was penalized
+	FailedEvaluations int
+
+	// The duration of organisms evaluation in this generation
+	EvaluationDuration time.Duration
+	// The timings of the population's epoch phases and the counters of mutations and matings applied during
+	// reproduction of the next generation
+	EpochStats genetics.EpochStatistics
+
+	// The results of structural analysis of the best organism's phenotype, if requested (see AnalyzeBest)
+	BestStructure *network.StructuralAnalysis
+
 	// The ID of Trial this Generation was evaluated in
 	TrialId int
 }
@@ -67,6 +87,27 @@ func (g *Generation) FillPopulationStatistics(pop *genetics.Population) {
 			}
 		}
 	}
+	g.recordBest()
+}
+
+// recordBest Records the fitness and complexity of the best organism before they are changed by the next epoch
+func (g *Generation) recordBest() {
+	if g.Best == nil {
+		return
+	}
+	g.BestFitness = g.Best.Fitness
+	if g.Best.Phenotype != nil {
+		g.BestComplexity = g.Best.Phenotype.Complexity()
+	}
+}
+
+// AnalyzeBest Runs structural analysis of the best organism's phenotype and stores results into BestStructure. The
+// analysis enumerates cycles of the network, which may be expensive for the large recurrent networks, thus it is not
+// done by FillPopulationStatistics and should be requested explicitly, e.g., with Experiment.AnalyzeChampions.
+func (g *Generation) AnalyzeBest() {
+	if g.Best != nil && g.Best.Phenotype != nil {
+		g.BestStructure = g.Best.Phenotype.Analyze()
+	}
 }
 
 // Average Returns average fitness, age, and complexity among all organisms from population at the end of this epoch
@@ -109,6 +150,29 @@ func (g *Generation) Encode(enc *gob.Encoder) error {
 	if err := enc.EncodeValue(reflect.ValueOf(g.WinnerGenes)); err != nil {
 		return err
 	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.Evaluations)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.FailedEvaluations)); err != nil {
+		return err
+	}
+	if err := g.encodeTimings(enc); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.BestFitness)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.BestComplexity)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.BestStructure != nil)); err != nil {
+		return err
+	}
+	if g.BestStructure != nil {
+		if err := enc.Encode(g.BestStructure); err != nil {
+			return err
+		}
+	}
 
 	// encode best organism
 	if g.Best != nil {
@@ -119,6 +183,50 @@ func (g *Generation) Encode(enc *gob.Encoder) error {
 	return nil
 }
 
+// encodeTimings Encodes timings of the generation phases and the reproduction counters
+func (g *Generation) encodeTimings(enc *gob.Encoder) error {
+	stats := g.EpochStats
+	if stats.SpeciesReproduction == nil {
+		stats.SpeciesReproduction = make(map[int]time.Duration)
+	}
+	for _, d := range []time.Duration{g.EvaluationDuration, stats.FitnessAdjustment, stats.Reproduction, stats.Speciation} {
+		if err := enc.EncodeValue(reflect.ValueOf(d)); err != nil {
+			return err
+		}
+	}
+	if err := enc.Encode(stats.SpeciesReproduction); err != nil {
+		return err
+	}
+	if err := enc.Encode(stats.Mutations); err != nil {
+		return err
+	}
+	return enc.Encode(stats.Matings)
+}
+
+// decodeTimings Decodes timings of the generation phases and the reproduction counters
+func (g *Generation) decodeTimings(dec *gob.Decoder) error {
+	for _, d := range []*time.Duration{&g.EvaluationDuration, &g.EpochStats.FitnessAdjustment,
+		&g.EpochStats.Reproduction, &g.EpochStats.Speciation} {
+		if err := dec.Decode(d); err != nil {
+			return errors.Wrap(err, "failed to decode phase duration")
+		}
+	}
+	if err := dec.Decode(&g.EpochStats.SpeciesReproduction); err != nil {
+		return errors.Wrap(err, "failed to decode SpeciesReproduction")
+	}
+	if len(g.EpochStats.SpeciesReproduction) == 0 {
+		// the nil map is encoded as empty
+		g.EpochStats.SpeciesReproduction = nil
+	}
+	if err := dec.Decode(&g.EpochStats.Mutations); err != nil {
+		return errors.Wrap(err, "failed to decode Mutations")
+	}
+	if err := dec.Decode(&g.EpochStats.Matings); err != nil {
+		return errors.Wrap(err, "failed to decode Matings")
+	}
+	return nil
+}
+
 func encodeOrganism(enc *gob.Encoder, org *genetics.Organism) error {
 	if err := enc.Encode(org.Fitness); err != nil {
 		return err
@@ -153,7 +261,13 @@ func encodeOrganism(enc *gob.Encoder, org *genetics.Organism) error {
 	return nil
 }
 
+// Decode Decodes generation data
 func (g *Generation) Decode(dec *gob.Decoder) error {
+	return g.decode(dec, formatVersion)
+}
+
+// decode Decodes generation data encoded with specified format version
+func (g *Generation) decode(dec *gob.Decoder, version int) error {
 	if err := dec.Decode(&g.Id); err != nil {
 		return errors.Wrap(err, "failed to decode Id")
 	}
@@ -182,7 +296,12 @@ func (g *Generation) Decode(dec *gob.Decoder) error {
 		return errors.Wrap(err, "failed to decode WinnerNodes")
 	}
 	if err := dec.Decode(&g.WinnerGenes); err != nil {
-		return errors.Wrap(err, "failed to decode WinnerNodes")
+		return errors.Wrap(err, "failed to decode WinnerGenes")
+	}
+	if version >= formatVersion1 {
+		if err := g.decodeStatistics(dec); err != nil {
+			return err
+		}
 	}
 
 	// decode organism
@@ -191,6 +310,40 @@ func (g *Generation) Decode(dec *gob.Decoder) error {
 	} else {
 		g.Best = org
 	}
+	if version == formatVersionLegacy {
+		// the legacy format has no raw fitness snapshot, the stored champion fitness is the best available
+		g.BestFitness = g.Best.Fitness
+	}
+	return nil
+}
+
+// decodeStatistics Decodes evaluation counters, timings and champion statistics
+func (g *Generation) decodeStatistics(dec *gob.Decoder) error {
+	if err := dec.Decode(&g.Evaluations); err != nil {
+		return errors.Wrap(err, "failed to decode Evaluations")
+	}
+	if err := dec.Decode(&g.FailedEvaluations); err != nil {
+		return errors.Wrap(err, "failed to decode FailedEvaluations")
+	}
+	if err := g.decodeTimings(dec); err != nil {
+		return err
+	}
+	if err := dec.Decode(&g.BestFitness); err != nil {
+		return errors.Wrap(err, "failed to decode BestFitness")
+	}
+	if err := dec.Decode(&g.BestComplexity); err != nil {
+		return errors.Wrap(err, "failed to decode BestComplexity")
+	}
+	var hasStructure bool
+	if err := dec.Decode(&hasStructure); err != nil {
+		return errors.Wrap(err, "failed to decode BestStructure flag")
+	}
+	if hasStructure {
+		g.BestStructure = &network.StructuralAnalysis{}
+		if err := dec.Decode(g.BestStructure); err != nil {
+			return errors.Wrap(err, "failed to decode BestStructure")
+		}
+	}
 	return nil
 }
 
@@ -34,6 +34,43 @@ func TestGeneration_Encode_Decode(t *testing.T) {
 
 	//  and test fields
 	assert.EqualValues(t, gen, dgen)
+
+	// encode/decode without structural analysis
+	gen.BestStructure = nil
+	buff.Reset()
+	enc = gob.NewEncoder(&buff)
+	err = gen.Encode(enc)
+	require.NoError(t, err, "failed to encode generation")
+	dec = gob.NewDecoder(bytes.NewBuffer(buff.Bytes()))
+	dgen = &Generation{}
+	err = dgen.Decode(dec)
+	require.NoError(t, err, "failed to decode generation")
+	assert.Nil(t, dgen.BestStructure)
+}
+
+func TestGeneration_FillPopulationStatistics(t *testing.T) {
+	gen := buildTestGeneration(1, 10.0)
+	gen.Solved = false
+	gen.Best = nil
+	gen.BestStructure = nil
+
+	org, err := genetics.NewOrganism(10.0, buildTestGenome(1), 1)
+	require.NoError(t, err, "failed to create organism")
+	species := genetics.NewSpecies(1)
+	species.Organisms = genetics.Organisms{org}
+	pop := &genetics.Population{Species: []*genetics.Species{species}}
+
+	gen.FillPopulationStatistics(pop)
+	assert.Equal(t, 1, gen.Diversity)
+	assert.Equal(t, org, gen.Best)
+	assert.Nil(t, gen.BestStructure, "structural analysis must be requested explicitly")
+
+	gen.AnalyzeBest()
+	require.NotNil(t, gen.BestStructure, "structural analysis of the best organism expected")
+	assert.Equal(t, 4, gen.BestStructure.Nodes)
+	assert.Equal(t, 3, gen.BestStructure.Links)
+	assert.Equal(t, 2, gen.BestStructure.Layers)
+	assert.Equal(t, 3, gen.BestStructure.InputOutputPaths)
 }
 
 func buildTestGeneration(genId int, fitness float64) *Generation {
@@ -48,10 +85,26 @@ func buildTestGeneration(genId int, fitness float64) *Generation {
 	epoch.WinnerEvals = 12423
 	epoch.WinnerNodes = 7
 	epoch.WinnerGenes = 5
+	epoch.Evaluations = 150
+	epoch.FailedEvaluations = 2
+	epoch.EvaluationDuration = 3 * time.Second
+	epoch.EpochStats = genetics.EpochStatistics{
+		FitnessAdjustment:   time.Millisecond,
+		Reproduction:        20 * time.Millisecond,
+		Speciation:          5 * time.Millisecond,
+		SpeciesReproduction: map[int]time.Duration{1: 12 * time.Millisecond, 2: 7 * time.Millisecond},
+		Mutations:           genetics.MutationCounts{AddNode: 3, AddLink: 5, ConnectSensors: 1, NonStructural: 80},
+		Matings:             genetics.MatingCounts{Multipoint: 40, MultipointAvg: 20, SinglePoint: 2, Interspecies: 1},
+	}
 
 	genome := buildTestGenome(genId)
 	org := genetics.Organism{Fitness: fitness, Genotype: genome, Generation: genId}
 	epoch.Best = &org
+	epoch.BestFitness = fitness
+	epoch.BestStructure = &network.StructuralAnalysis{
+		Nodes: 4, Links: 3, EffectiveNodes: 4, EffectiveLinks: 3, Layers: 2, InputOutputPaths: 3,
+		MaxFanIn: 3, MaxFanOut: 1, Modularity: -0.33, Communities: 4,
+	}
 
 	return &epoch
 }
@@ -0,0 +1,221 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io"
+	"sync"
+)
+
+// Candidate the organism of the population presented to the user for selection
+type Candidate struct {
+	// The index of the organism in the population, which is used to refer the candidate in Selection
+	Index int `json:"index"`
+	// The ID of the organism's genome
+	GenomeId int `json:"genome_id"`
+	// The ID of the species of the organism
+	SpeciesId int `json:"species_id"`
+	// The MIME type of the rendered organism's output
+	ContentType string `json:"content_type"`
+	// The rendered organism's output, e.g., the image generated by CPPN or the SVG of the network
+	Content []byte `json:"-"`
+}
+
+// SelectionRequest the generation of organisms awaiting the user's selection
+type SelectionRequest struct {
+	// The ID of the trial
+	TrialId int `json:"trial_id"`
+	// The ID of the generation
+	Generation int `json:"generation"`
+	// The organisms to select from
+	Candidates []Candidate `json:"candidates"`
+}
+
+// Selection the choice made by the user among candidates of the SelectionRequest
+type Selection struct {
+	// The ID of the trial the selection was made for
+	TrialId int `json:"trial_id"`
+	// The ID of the generation the selection was made for
+	Generation int `json:"generation"`
+	// The indexes of the favourite candidates, which become the parents of the next generation
+	Favourites []int `json:"favourites"`
+	// If true, the first favourite is accepted as the solution, and the trial is finished
+	Accept bool `json:"accept"`
+}
+
+// Validate Checks that selection refers the candidates of provided request
+func (s *Selection) Validate(request *SelectionRequest) error {
+	if s.TrialId != request.TrialId || s.Generation != request.Generation {
+		return errors.Errorf("selection made for generation %d of trial %d, but generation %d of trial %d expected",
+			s.Generation, s.TrialId, request.Generation, request.TrialId)
+	}
+	seen := make(map[int]bool, len(s.Favourites))
+	for _, index := range s.Favourites {
+		if index < 0 || index >= len(request.Candidates) {
+			return errors.Errorf("favourite candidate index out of range: %d", index)
+		}
+		if seen[index] {
+			return errors.Errorf("duplicate favourite candidate index: %d", index)
+		}
+		seen[index] = true
+	}
+	if s.Accept && len(s.Favourites) == 0 {
+		return errors.New("at least one favourite candidate expected to accept the solution")
+	}
+	return nil
+}
+
+// Selector the source of the user's choices. The implementation blocks until selection is made or context is done.
+type Selector interface {
+	// Select Presents the candidates of the request to the user and returns the selection made
+	Select(ctx context.Context, request *SelectionRequest) (*Selection, error)
+}
+
+// SelectorFunc the adapter to allow the use of ordinary function as Selector
+type SelectorFunc func(ctx context.Context, request *SelectionRequest) (*Selection, error)
+
+// Select Implements Selector
+func (f SelectorFunc) Select(ctx context.Context, request *SelectionRequest) (*Selection, error) {
+	return f(ctx, request)
+}
+
+// ScriptedSelector the Selector which returns selections read from the stream of JSON objects, one per generation.
+// It allows running the interactive evolution headlessly, e.g., in tests or to replay the choices made by the user.
+// The trial and generation IDs of the scripted selections are ignored.
+type ScriptedSelector struct {
+	decoder *json.Decoder
+	mutex   sync.Mutex
+}
+
+// NewScriptedSelector Creates new scripted selector which reads selections from provided reader
+func NewScriptedSelector(r io.Reader) *ScriptedSelector {
+	return &ScriptedSelector{decoder: json.NewDecoder(r)}
+}
+
+// Select Implements Selector
+func (s *ScriptedSelector) Select(ctx context.Context, request *SelectionRequest) (*Selection, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var selection Selection
+	if err := s.decoder.Decode(&selection); err == io.EOF {
+		return nil, errors.Errorf("no scripted selection for generation %d of trial %d",
+			request.Generation, request.TrialId)
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to decode scripted selection")
+	}
+	selection.TrialId, selection.Generation = request.TrialId, request.Generation
+	return &selection, nil
+}
+
+// InteractiveGenerationEvaluator the generation evaluator which lets the user to assign fitness of organisms by
+// selecting favourites among them. The output of each organism is rendered and presented to the user by Selector,
+// e.g., the web page served by InteractiveServer. The evaluation blocks until the user makes selection. The favourite
+// organisms get the FavouriteFitness, and all others get the BaseFitness, thus the favourites and their species
+// produce the most offspring during reproduction of the population.
+type InteractiveGenerationEvaluator struct {
+	// Renderer renders the output of organisms to be presented to the user
+	Renderer OrganismRenderer
+	// Selector provides the user's choices
+	Selector Selector
+	// The fitness to be assigned to the favourite organisms
+	FavouriteFitness float64
+	// The fitness to be assigned to the organisms not selected by the user. It should be positive to let the species
+	// without favourites produce some offspring.
+	BaseFitness float64
+}
+
+// NewInteractiveGenerationEvaluator Creates new interactive generation evaluator which renders organisms with provided
+// renderer and assigns their fitness according to the choices provided by selector.
+func NewInteractiveGenerationEvaluator(renderer OrganismRenderer, selector Selector) *InteractiveGenerationEvaluator {
+	return &InteractiveGenerationEvaluator{
+		Renderer:         renderer,
+		Selector:         selector,
+		FavouriteFitness: 1.0,
+		BaseFitness:      0.01,
+	}
+}
+
+// GenerationEvaluate Implements GenerationEvaluator. Blocks until the user makes selection.
+func (e *InteractiveGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, opts *neat.Options) error {
+	return e.GenerationEvaluateContext(neat.NewContext(context.Background(), opts), pop, epoch)
+}
+
+// GenerationEvaluateContext Implements ContextGenerationEvaluator. Blocks until the user makes selection or context
+// is done.
+func (e *InteractiveGenerationEvaluator) GenerationEvaluateContext(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	opts, found := neat.FromContext(ctx)
+	if !found {
+		return neat.ErrNEATOptionsNotFound
+	}
+	request, err := e.selectionRequest(pop, epoch)
+	if err != nil {
+		return err
+	}
+	selection, err := e.Selector.Select(ctx, request)
+	if err != nil {
+		return err
+	}
+	if err = selection.Validate(request); err != nil {
+		return err
+	}
+	neat.LoggerFromContext(ctx).Debug(fmt.Sprintf("User selected %d favourites in generation %d",
+		len(selection.Favourites), epoch.Id), "accept", selection.Accept)
+
+	// assign fitness according to the selection
+	for _, org := range pop.Organisms {
+		org.Fitness, org.Error, org.IsWinner = e.BaseFitness, 1.0, false
+	}
+	for _, index := range selection.Favourites {
+		org := pop.Organisms[index]
+		org.Fitness, org.Error = e.FavouriteFitness, 0.0
+	}
+	if selection.Accept {
+		org := pop.Organisms[selection.Favourites[0]]
+		org.IsWinner = true
+		epoch.Solved = true
+		epoch.WinnerNodes = len(org.Genotype.Nodes)
+		epoch.WinnerGenes = org.Genotype.Extrons()
+		epoch.WinnerEvals = opts.PopSize*epoch.Id + org.Genotype.Id
+		epoch.Best = org
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+// selectionRequest Renders organisms of the population into the request for selection
+func (e *InteractiveGenerationEvaluator) selectionRequest(pop *genetics.Population, epoch *Generation) (*SelectionRequest, error) {
+	request := &SelectionRequest{
+		TrialId:    epoch.TrialId,
+		Generation: epoch.Id,
+		Candidates: make([]Candidate, len(pop.Organisms)),
+	}
+	for i, org := range pop.Organisms {
+		var buf bytes.Buffer
+		contentType, err := e.Renderer.Render(&buf, org)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render organism with genome: %d", org.Genotype.Id)
+		}
+		candidate := Candidate{
+			Index:       i,
+			GenomeId:    org.Genotype.Id,
+			ContentType: contentType,
+			Content:     buf.Bytes(),
+		}
+		if org.Species != nil {
+			candidate.SpeciesId = org.Species.Id
+		}
+		request.Candidates[i] = candidate
+	}
+	return request, nil
+}
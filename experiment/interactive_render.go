@@ -0,0 +1,244 @@
+package experiment
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// OrganismRenderer renders the output of organism to be presented to the user during interactive evolution
+type OrganismRenderer interface {
+	// Render Writes the rendered output of organism and returns its MIME type
+	Render(w io.Writer, org *genetics.Organism) (contentType string, err error)
+}
+
+// OrganismRendererFunc the adapter to allow the use of ordinary function as OrganismRenderer
+type OrganismRendererFunc func(w io.Writer, org *genetics.Organism) (string, error)
+
+// Render Implements OrganismRenderer
+func (f OrganismRendererFunc) Render(w io.Writer, org *genetics.Organism) (string, error) {
+	return f(w, org)
+}
+
+// NetworkSVGRenderer renders the topology of the organism's genome as SVG image. The sensors are placed at the bottom,
+// the outputs at the top, and the hidden nodes in between according to their distance from sensors. The positive
+// links are drawn in green, the negative in red, with width proportional to the weight, and the disabled links are
+// dashed.
+type NetworkSVGRenderer struct {
+	// The width of the image
+	Width int
+	// The height of the image
+	Height int
+}
+
+// NewNetworkSVGRenderer Creates new SVG renderer of networks topology with default image size
+func NewNetworkSVGRenderer() *NetworkSVGRenderer {
+	return &NetworkSVGRenderer{Width: 240, Height: 240}
+}
+
+// Render Implements OrganismRenderer
+func (r *NetworkSVGRenderer) Render(w io.Writer, org *genetics.Organism) (string, error) {
+	genome := org.Genotype
+	layers := genomeLayers(genome)
+	maxLayer := 0
+	for _, layer := range layers {
+		if layer > maxLayer {
+			maxLayer = layer
+		}
+	}
+	// arrange nodes within the layers
+	rows := make([][]*network.NNode, maxLayer+1)
+	for _, node := range genome.Nodes {
+		rows[layers[node.Id]] = append(rows[layers[node.Id]], node)
+	}
+	const margin, radius = 16.0, 7.0
+	positions := make(map[int][2]float64, len(genome.Nodes))
+	for l, row := range rows {
+		y := float64(r.Height) - margin
+		if maxLayer > 0 {
+			y -= float64(l) * (float64(r.Height) - 2*margin) / float64(maxLayer)
+		}
+		for i, node := range row {
+			x := float64(r.Width) * float64(i+1) / float64(len(row)+1)
+			positions[node.Id] = [2]float64{x, y}
+		}
+	}
+
+	buf := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		r.Width, r.Height, r.Width, r.Height)
+	for _, gene := range genome.Genes {
+		from, to := positions[gene.Link.InNode.Id], positions[gene.Link.OutNode.Id]
+		stroke, dash := "#2e7d32", ""
+		if gene.Link.Weight < 0 {
+			stroke = "#c62828"
+		}
+		if !gene.IsEnabled {
+			stroke, dash = "#9e9e9e", ` stroke-dasharray="3,3"`
+		}
+		width := math.Min(0.5+math.Abs(gene.Link.Weight), 4.0)
+		if gene.Link.InNode.Id == gene.Link.OutNode.Id {
+			_, _ = fmt.Fprintf(buf, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="%s" stroke-width="%.2f"%s/>`+"\n",
+				from[0]+radius, from[1]-radius, radius, stroke, width, dash)
+		} else {
+			_, _ = fmt.Fprintf(buf, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.2f"%s/>`+"\n",
+				from[0], from[1], to[0], to[1], stroke, width, dash)
+		}
+	}
+	for _, node := range genome.Nodes {
+		pos := positions[node.Id]
+		_, _ = fmt.Fprintf(buf, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="#212121"><title>%s #%d</title></circle>`+"\n",
+			pos[0], pos[1], radius, neuronColor(node.NeuronType), network.NeuronTypeName(node.NeuronType), node.Id)
+	}
+	if _, err := fmt.Fprintln(buf, "</svg>"); err != nil {
+		return "", err
+	}
+	return "image/svg+xml", buf.Flush()
+}
+
+// genomeLayers Returns the layer of each node of the genome keyed by node ID. The sensors are in the first layer, the
+// hidden nodes are placed into the layers according to the length of the longest non-recurrent path from sensors, and
+// the outputs are in the last layer.
+func genomeLayers(genome *genetics.Genome) map[int]int {
+	layers := make(map[int]int, len(genome.Nodes))
+	for _, node := range genome.Nodes {
+		if node.NeuronType == network.HiddenNeuron {
+			layers[node.Id] = 1
+		}
+	}
+	// relax the longest paths, the number of iterations is limited by the number of nodes to break cycles
+	for i := 0; i < len(genome.Nodes); i++ {
+		changed := false
+		for _, gene := range genome.Genes {
+			in, out := gene.Link.InNode, gene.Link.OutNode
+			if gene.Link.IsRecurrent || out.NeuronType != network.HiddenNeuron || in.NeuronType != network.HiddenNeuron {
+				continue
+			}
+			if layers[in.Id]+1 > layers[out.Id] {
+				layers[out.Id] = layers[in.Id] + 1
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	maxHidden := 0
+	for _, layer := range layers {
+		if layer > maxHidden {
+			maxHidden = layer
+		}
+	}
+	for _, node := range genome.Nodes {
+		if node.NeuronType == network.OutputNeuron {
+			layers[node.Id] = maxHidden + 1
+		}
+	}
+	return layers
+}
+
+func neuronColor(neuronType network.NodeNeuronType) string {
+	switch neuronType {
+	case network.InputNeuron:
+		return "#64b5f6"
+	case network.BiasNeuron:
+		return "#fff176"
+	case network.OutputNeuron:
+		return "#ffb74d"
+	default:
+		return "#e0e0e0"
+	}
+}
+
+// CPPNImageRenderer renders the image generated by organism's phenotype treated as Compositional Pattern Producing
+// Network (CPPN). The network is queried for each pixel with its coordinates x and y scaled to [-1, 1] and the distance
+// from the center d, in that order, as many of them as the network has input neurons (up to three). The single output
+// network produces grayscale image, and the network with three or more outputs produces RGB image. The outputs are
+// expected to be in [0, 1] range and clamped otherwise.
+type CPPNImageRenderer struct {
+	// The width of the image
+	Width int
+	// The height of the image
+	Height int
+	// The number of activation steps, which should be sufficient to propagate signals through the modular and
+	// recurrent networks
+	ActivationSteps int
+}
+
+// NewCPPNImageRenderer Creates new CPPN image renderer with default image size
+func NewCPPNImageRenderer() *CPPNImageRenderer {
+	return &CPPNImageRenderer{Width: 128, Height: 128, ActivationSteps: 10}
+}
+
+// Render Implements OrganismRenderer
+func (r *CPPNImageRenderer) Render(w io.Writer, org *genetics.Organism) (string, error) {
+	if r.Width <= 0 || r.Height <= 0 {
+		return "", errors.Errorf("invalid image size: %dx%d", r.Width, r.Height)
+	}
+	inputs := 0
+	for _, node := range org.Genotype.Nodes {
+		if node.NeuronType == network.InputNeuron {
+			inputs++
+		}
+	}
+	if inputs == 0 || inputs > 3 {
+		return "", errors.Errorf("CPPN with 1 to 3 inputs expected, but found: %d", inputs)
+	}
+	solver, err := org.Phenotype.FastNetworkSolver()
+	if err != nil {
+		return "", err
+	}
+	batchSolver, ok := solver.(network.BatchSolver)
+	if !ok {
+		return "", errors.New("network solver does not support batch activation")
+	}
+
+	samples := make([][]float64, 0, r.Width*r.Height)
+	for py := 0; py < r.Height; py++ {
+		for px := 0; px < r.Width; px++ {
+			x := 2*float64(px)/math.Max(float64(r.Width-1), 1) - 1
+			y := 2*float64(py)/math.Max(float64(r.Height-1), 1) - 1
+			d := math.Sqrt(x*x+y*y) / math.Sqrt2
+			samples = append(samples, []float64{x, y, d}[:inputs])
+		}
+	}
+	steps := r.ActivationSteps
+	if steps <= 0 {
+		steps = 1
+	}
+	outputs, err := batchSolver.ActivateBatch(samples, steps)
+	if err != nil {
+		return "", err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, r.Width, r.Height))
+	for i, out := range outputs {
+		var c color.RGBA
+		switch {
+		case len(out) >= 3:
+			c = color.RGBA{R: colorChannel(out[0]), G: colorChannel(out[1]), B: colorChannel(out[2]), A: 255}
+		case len(out) > 0:
+			v := colorChannel(out[0])
+			c = color.RGBA{R: v, G: v, B: v, A: 255}
+		default:
+			return "", errors.New("CPPN has no outputs")
+		}
+		img.SetRGBA(i%r.Width, i/r.Width, c)
+	}
+	return "image/png", png.Encode(w, img)
+}
+
+// colorChannel Converts the network output into the color channel value
+func colorChannel(value float64) uint8 {
+	if math.IsNaN(value) {
+		return 0
+	}
+	return uint8(math.Round(255 * math.Max(0, math.Min(1, value))))
+}
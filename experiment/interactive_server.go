@@ -0,0 +1,244 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The HTTP paths of the interactive evolution endpoints
+const (
+	// InteractivePagePath The path of the web page presenting candidates to the user
+	InteractivePagePath = "/"
+	// InteractiveRequestPath The path of the endpoint serving the JSON of the SelectionRequest awaiting selection.
+	// Responds with 204 No Content if no generation awaits selection.
+	InteractiveRequestPath = "/request.json"
+	// InteractiveCandidatePath The path prefix of the endpoint serving the rendered output of candidate by its index,
+	// e.g. /candidate/3
+	InteractiveCandidatePath = "/candidate/"
+	// InteractiveSelectionPath The path of the endpoint accepting the Selection in JSON format with POST request
+	InteractiveSelectionPath = "/selection"
+)
+
+// ErrNoPendingSelection The error to be returned when selection submitted while no generation awaits it
+var ErrNoPendingSelection = errors.New("no generation awaits selection")
+
+// InteractiveServer the Selector which presents candidates to the user on the local web page and waits for the user
+// to submit the selection. The page polls the server for new generations, thus the server should be started once and
+// serve all generations of the interactive evolution. Only one generation can await selection at a time.
+type InteractiveServer struct {
+	// the generation awaiting selection
+	request *SelectionRequest
+	// the channel to deliver the user's selection
+	selections chan *Selection
+	mutex      sync.Mutex
+}
+
+// NewInteractiveServer Creates new interactive server
+func NewInteractiveServer() *InteractiveServer {
+	return &InteractiveServer{selections: make(chan *Selection, 1)}
+}
+
+// Select Implements Selector. Publishes the request to be presented on the web page and blocks until the user submits
+// selection or context is done.
+func (s *InteractiveServer) Select(ctx context.Context, request *SelectionRequest) (*Selection, error) {
+	s.mutex.Lock()
+	if s.request != nil {
+		s.mutex.Unlock()
+		return nil, errors.Errorf("generation %d of trial %d already awaits selection",
+			s.request.Generation, s.request.TrialId)
+	}
+	s.request = request
+	s.mutex.Unlock()
+
+	select {
+	case selection := <-s.selections:
+		return selection, nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		if s.request == nil {
+			// the selection was submitted concurrently, drop it to not be received for the next generation
+			<-s.selections
+		}
+		s.request = nil
+		s.mutex.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Pending Returns the request awaiting selection or nil if there is no such
+func (s *InteractiveServer) Pending() *SelectionRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.request
+}
+
+// Submit Delivers the selection for the generation awaiting it. Returns ErrNoPendingSelection if there is no
+// generation awaiting selection, or validation error if selection doesn't match the awaiting generation.
+func (s *InteractiveServer) Submit(selection *Selection) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.request == nil {
+		return ErrNoPendingSelection
+	}
+	if err := selection.Validate(s.request); err != nil {
+		return err
+	}
+	s.request = nil
+	s.selections <- selection
+	return nil
+}
+
+// Handler Returns the HTTP handler serving the web page and the endpoints of the interactive evolution
+func (s *InteractiveServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(InteractivePagePath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != InteractivePagePath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(interactivePage))
+	})
+	mux.HandleFunc(InteractiveRequestPath, func(w http.ResponseWriter, _ *http.Request) {
+		request := s.Pending()
+		if request == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(request); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc(InteractiveCandidatePath, func(w http.ResponseWriter, r *http.Request) {
+		request := s.Pending()
+		index, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, InteractiveCandidatePath))
+		if request == nil || err != nil || index < 0 || index >= len(request.Candidates) {
+			http.NotFound(w, r)
+			return
+		}
+		candidate := request.Candidates[index]
+		w.Header().Set("Content-Type", candidate.ContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(candidate.Content)
+	})
+	mux.HandleFunc(InteractiveSelectionPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var selection Selection
+		if err := json.NewDecoder(r.Body).Decode(&selection); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := s.Submit(&selection); err == ErrNoPendingSelection {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			w.WriteHeader(http.StatusAccepted)
+		}
+	})
+	return mux
+}
+
+// NewInteractiveHTTPServer Creates HTTP server serving the interactive evolution web page at provided address. The
+// server should be started by the caller, e.g. with ListenAndServe in the separate goroutine.
+func NewInteractiveHTTPServer(addr string, server *InteractiveServer) *http.Server {
+	return &http.Server{Addr: addr, Handler: server.Handler()}
+}
+
+// interactivePage the web page which polls for the generation awaiting selection, shows its candidates, and submits
+// the favourites selected by the user
+const interactivePage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goNEAT interactive evolution</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+#candidates { display: flex; flex-wrap: wrap; gap: 8px; }
+.candidate { border: 3px solid #ddd; padding: 4px; cursor: pointer; text-align: center; font-size: 12px; }
+.candidate.selected { border-color: #1976d2; background: #e3f2fd; }
+.candidate img { display: block; max-width: 240px; }
+button { margin: 1em 0.5em 1em 0; padding: 0.5em 1em; }
+</style>
+</head>
+<body>
+<h3 id="title">Waiting for the next generation...</h3>
+<div id="candidates"></div>
+<button id="submit" disabled>Breed selected</button>
+<button id="accept" disabled>Accept first selected as solution</button>
+<script>
+let request = null;
+let selected = [];
+
+function render() {
+  const root = document.getElementById("candidates");
+  root.innerHTML = "";
+  document.getElementById("submit").disabled = request === null;
+  document.getElementById("accept").disabled = request === null || selected.length === 0;
+  if (request === null) {
+    document.getElementById("title").textContent = "Waiting for the next generation...";
+    return;
+  }
+  document.getElementById("title").textContent = "Trial " + request.trial_id + ", generation " +
+    request.generation + ": select your favourites";
+  request.candidates.forEach(function (c) {
+    const div = document.createElement("div");
+    div.className = "candidate" + (selected.includes(c.index) ? " selected" : "");
+    const img = document.createElement("img");
+    img.src = "candidate/" + c.index + "?g=" + request.trial_id + "-" + request.generation;
+    div.appendChild(img);
+    div.appendChild(document.createTextNode("genome " + c.genome_id + ", species " + c.species_id));
+    div.onclick = function () {
+      const i = selected.indexOf(c.index);
+      if (i >= 0) { selected.splice(i, 1); } else { selected.push(c.index); }
+      render();
+    };
+    root.appendChild(div);
+  });
+}
+
+function submit(accept) {
+  fetch("selection", {
+    method: "POST",
+    headers: {"Content-Type": "application/json"},
+    body: JSON.stringify({trial_id: request.trial_id, generation: request.generation, favourites: selected, accept: accept})
+  }).then(function (resp) {
+    if (!resp.ok) { return resp.text().then(function (t) { alert(t); }); }
+    request = null;
+    selected = [];
+    render();
+  });
+}
+
+function poll() {
+  fetch("request.json").then(function (resp) {
+    if (resp.status === 204) { return null; }
+    return resp.json();
+  }).then(function (r) {
+    const changed = (r === null) !== (request === null) ||
+      (r !== null && (r.trial_id !== request.trial_id || r.generation !== request.generation));
+    if (changed) {
+      request = r;
+      selected = [];
+      render();
+    }
+  }).finally(function () { setTimeout(poll, 1000); });
+}
+
+document.getElementById("submit").onclick = function () { submit(false); };
+document.getElementById("accept").onclick = function () { submit(true); };
+poll();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,144 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInteractiveServer_Handler(t *testing.T) {
+	server := NewInteractiveServer()
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	get := func(path string) (*http.Response, string) {
+		resp, err := http.Get(httpServer.URL + path)
+		require.NoError(t, err)
+		body, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+	post := func(body string) int {
+		resp, err := http.Post(httpServer.URL+InteractiveSelectionPath, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// the page is served
+	resp, body := get(InteractivePagePath)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, body, "goNEAT interactive evolution")
+
+	// no pending generation
+	resp, _ = get(InteractiveRequestPath)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, http.StatusConflict, post(`{"trial_id": 0, "generation": 0, "favourites": []}`))
+
+	// publish generation awaiting selection
+	request := &SelectionRequest{TrialId: 1, Generation: 3, Candidates: []Candidate{
+		{Index: 0, GenomeId: 10, SpeciesId: 1, ContentType: "image/svg+xml", Content: []byte("<svg/>")},
+		{Index: 1, GenomeId: 11, SpeciesId: 2, ContentType: "image/svg+xml", Content: []byte("<svg></svg>")},
+	}}
+	type result struct {
+		selection *Selection
+		err       error
+	}
+	results := make(chan result, 1)
+	go func() {
+		selection, err := server.Select(context.Background(), request)
+		results <- result{selection: selection, err: err}
+	}()
+	require.Eventually(t, func() bool { return server.Pending() != nil }, time.Second, time.Millisecond)
+
+	resp, body = get(InteractiveRequestPath)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var published SelectionRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &published))
+	assert.Equal(t, 3, published.Generation)
+	require.Len(t, published.Candidates, 2)
+	assert.Equal(t, 11, published.Candidates[1].GenomeId)
+	assert.Nil(t, published.Candidates[1].Content)
+
+	resp, body = get(InteractiveCandidatePath + "1")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "<svg></svg>", body)
+	resp, _ = get(InteractiveCandidatePath + "2")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// invalid selections are rejected
+	assert.Equal(t, http.StatusBadRequest, post(`{"trial_id": 1, "generation": 2, "favourites": [0]}`))
+	assert.Equal(t, http.StatusBadRequest, post(`{"trial_id": 1, "generation": 3, "favourites": [5]}`))
+	assert.Equal(t, http.StatusBadRequest, post(`not json`))
+
+	// valid selection is delivered
+	assert.Equal(t, http.StatusAccepted, post(`{"trial_id": 1, "generation": 3, "favourites": [1], "accept": true}`))
+	res := <-results
+	require.NoError(t, res.err)
+	assert.Equal(t, &Selection{TrialId: 1, Generation: 3, Favourites: []int{1}, Accept: true}, res.selection)
+	assert.Nil(t, server.Pending())
+	assert.Equal(t, http.StatusConflict, post(`{"trial_id": 1, "generation": 3, "favourites": [1]}`))
+
+	resp, _ = get(InteractiveSelectionPath)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestInteractiveServer_Select_cancel(t *testing.T) {
+	server := NewInteractiveServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := server.Select(ctx, &SelectionRequest{Generation: 1})
+		errs <- err
+	}()
+	require.Eventually(t, func() bool { return server.Pending() != nil }, time.Second, time.Millisecond)
+
+	// only one generation can await selection
+	_, err := server.Select(context.Background(), &SelectionRequest{Generation: 2})
+	assert.EqualError(t, err, "generation 1 of trial 0 already awaits selection")
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-errs)
+	assert.Nil(t, server.Pending())
+	assert.Equal(t, ErrNoPendingSelection, server.Submit(&Selection{Generation: 1}))
+}
+
+func TestInteractiveServer_Select_cancelSubmitted(t *testing.T) {
+	server := NewInteractiveServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := server.Select(ctx, &SelectionRequest{Generation: 1})
+		errs <- err
+	}()
+	require.Eventually(t, func() bool { return server.Pending() != nil }, time.Second, time.Millisecond)
+
+	// the selection is submitted while Select already gave up waiting due to canceled context
+	server.mutex.Lock()
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	server.request = nil
+	server.selections <- &Selection{Generation: 1}
+	server.mutex.Unlock()
+	assert.Equal(t, context.Canceled, <-errs)
+	assert.Len(t, server.selections, 0, "stale selection left for the next generation")
+
+	// the next generation receives its own selection
+	results := make(chan *Selection, 1)
+	go func() {
+		selection, _ := server.Select(context.Background(), &SelectionRequest{Generation: 2})
+		results <- selection
+	}()
+	require.Eventually(t, func() bool { return server.Pending() != nil }, time.Second, time.Millisecond)
+	require.NoError(t, server.Submit(&Selection{Generation: 2}))
+	assert.Equal(t, 2, (<-results).Generation)
+}
@@ -0,0 +1,189 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testRenderer renders the ID of the organism's genome as text
+var testRenderer = OrganismRendererFunc(func(w io.Writer, org *genetics.Organism) (string, error) {
+	_, err := io.WriteString(w, strings.Repeat("#", org.Genotype.Id))
+	return "text/plain", err
+})
+
+func TestSelection_Validate(t *testing.T) {
+	request := &SelectionRequest{TrialId: 1, Generation: 2, Candidates: make([]Candidate, 3)}
+	cases := []struct {
+		selection Selection
+		err       string
+	}{
+		{Selection{TrialId: 1, Generation: 2, Favourites: []int{0, 2}, Accept: true}, ""},
+		{Selection{TrialId: 1, Generation: 2}, ""},
+		{Selection{TrialId: 1, Generation: 1, Favourites: []int{0}},
+			"selection made for generation 1 of trial 1, but generation 2 of trial 1 expected"},
+		{Selection{TrialId: 1, Generation: 2, Favourites: []int{3}}, "favourite candidate index out of range: 3"},
+		{Selection{TrialId: 1, Generation: 2, Favourites: []int{1, 1}}, "duplicate favourite candidate index: 1"},
+		{Selection{TrialId: 1, Generation: 2, Accept: true},
+			"at least one favourite candidate expected to accept the solution"},
+	}
+	for i, c := range cases {
+		err := c.selection.Validate(request)
+		if len(c.err) == 0 {
+			assert.NoError(t, err, "case: %d", i)
+		} else {
+			assert.EqualError(t, err, c.err, "case: %d", i)
+		}
+	}
+}
+
+func TestScriptedSelector_Select(t *testing.T) {
+	selector := NewScriptedSelector(strings.NewReader(`{"favourites": [1, 2]}
+{"favourites": [0], "accept": true}`))
+	ctx := context.Background()
+
+	selection, err := selector.Select(ctx, &SelectionRequest{TrialId: 1, Generation: 0})
+	require.NoError(t, err)
+	assert.Equal(t, &Selection{TrialId: 1, Generation: 0, Favourites: []int{1, 2}}, selection)
+
+	selection, err = selector.Select(ctx, &SelectionRequest{TrialId: 1, Generation: 1})
+	require.NoError(t, err)
+	assert.Equal(t, &Selection{TrialId: 1, Generation: 1, Favourites: []int{0}, Accept: true}, selection)
+
+	_, err = selector.Select(ctx, &SelectionRequest{TrialId: 1, Generation: 2})
+	assert.EqualError(t, err, "no scripted selection for generation 2 of trial 1")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = selector.Select(cancelled, &SelectionRequest{})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestInteractiveGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.PopSize = 10
+	pop, err := genetics.NewPopulation(startGenome, opts)
+	require.NoError(t, err)
+
+	var request *SelectionRequest
+	selector := SelectorFunc(func(_ context.Context, r *SelectionRequest) (*Selection, error) {
+		request = r
+		return &Selection{TrialId: r.TrialId, Generation: r.Generation, Favourites: []int{3, 5}}, nil
+	})
+	evaluator := NewInteractiveGenerationEvaluator(testRenderer, selector)
+	epoch := Generation{Id: 2, TrialId: 1}
+	require.NoError(t, evaluator.GenerationEvaluate(pop, &epoch, opts))
+
+	require.NotNil(t, request)
+	assert.Equal(t, 1, request.TrialId)
+	assert.Equal(t, 2, request.Generation)
+	require.Len(t, request.Candidates, opts.PopSize)
+	for i, c := range request.Candidates {
+		assert.Equal(t, i, c.Index)
+		assert.Equal(t, "text/plain", c.ContentType)
+		assert.Equal(t, strings.Repeat("#", c.GenomeId), string(c.Content))
+		assert.True(t, c.SpeciesId > 0, "species ID expected")
+	}
+	for _, org := range pop.Organisms {
+		favourite := org.Genotype.Id == request.Candidates[3].GenomeId || org.Genotype.Id == request.Candidates[5].GenomeId
+		if favourite {
+			assert.Equal(t, evaluator.FavouriteFitness, org.Fitness)
+		} else {
+			assert.Equal(t, evaluator.BaseFitness, org.Fitness)
+		}
+		assert.False(t, org.IsWinner)
+	}
+	assert.False(t, epoch.Solved)
+	require.NotNil(t, epoch.Best)
+	assert.Equal(t, evaluator.FavouriteFitness, epoch.Best.Fitness)
+
+	// accept solution
+	selector = func(_ context.Context, r *SelectionRequest) (*Selection, error) {
+		return &Selection{TrialId: r.TrialId, Generation: r.Generation, Favourites: []int{4}, Accept: true}, nil
+	}
+	evaluator.Selector = selector
+	winner := pop.Organisms[4]
+	epoch = Generation{Id: 3, TrialId: 1}
+	require.NoError(t, evaluator.GenerationEvaluate(pop, &epoch, opts))
+	assert.True(t, epoch.Solved)
+	assert.True(t, winner.IsWinner)
+	assert.Equal(t, winner, epoch.Best)
+	assert.Equal(t, len(winner.Genotype.Nodes), epoch.WinnerNodes)
+
+	// invalid selection
+	evaluator.Selector = SelectorFunc(func(_ context.Context, r *SelectionRequest) (*Selection, error) {
+		return &Selection{TrialId: r.TrialId, Generation: r.Generation, Favourites: []int{100}}, nil
+	})
+	assert.Error(t, evaluator.GenerationEvaluate(pop, &Generation{}, opts))
+}
+
+func TestExperiment_Execute_Interactive(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 1
+	opts.PopSize = 20
+	opts.NumGenerations = 10
+
+	// favour the first two organisms and accept the champion of the third generation
+	script := `{"favourites": [0, 1]}
+{"favourites": [0, 1]}
+{"favourites": [2], "accept": true}`
+	evaluator := NewInteractiveGenerationEvaluator(testRenderer, NewScriptedSelector(strings.NewReader(script)))
+	exp := Experiment{Id: 1, Trials: make(Trials, opts.NumRuns), RandSeed: 42}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	require.Len(t, exp.Trials, 1)
+	trial := exp.Trials[0]
+	assert.True(t, trial.Solved())
+	require.Len(t, trial.Generations, 3)
+	assert.True(t, trial.Generations[2].Solved)
+	assert.Equal(t, evaluator.FavouriteFitness, trial.Generations[2].Best.Fitness)
+}
+
+func TestNetworkSVGRenderer_Render(t *testing.T) {
+	_, startGenome := loadTestOptionsAndGenome(t)
+	org, err := genetics.NewOrganism(0, startGenome, 1)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	contentType, err := NewNetworkSVGRenderer().Render(&buf, org)
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", contentType)
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="240" height="240"`))
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+	assert.Equal(t, len(startGenome.Nodes), strings.Count(svg, "<title>"))
+	assert.Equal(t, len(startGenome.Genes), strings.Count(svg, "<line"))
+}
+
+func TestCPPNImageRenderer_Render(t *testing.T) {
+	_, startGenome := loadTestOptionsAndGenome(t)
+	for _, gene := range startGenome.Genes {
+		gene.Link.Weight = 2.0
+	}
+	org, err := genetics.NewOrganism(0, startGenome, 1)
+	require.NoError(t, err)
+
+	renderer := CPPNImageRenderer{Width: 16, Height: 8, ActivationSteps: 5}
+	var buf bytes.Buffer
+	contentType, err := renderer.Render(&buf, org)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, 16, img.Bounds().Dx())
+	assert.Equal(t, 8, img.Bounds().Dy())
+	// the output of the network differs across the image
+	assert.NotEqual(t, img.At(0, 0), img.At(15, 7))
+
+	renderer.Width = 0
+	_, err = renderer.Render(&buf, org)
+	assert.EqualError(t, err, "invalid image size: 0x8")
+}
@@ -0,0 +1,347 @@
+package experiment
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The names of the files written by Manifest.WriteRecord into the output directory
+const (
+	ManifestFileName        = "manifest.yml"
+	ManifestOptionsFileName = "options.yml"
+	ManifestGenomeFileName  = "start_genome"
+)
+
+// Manifest The declarative description of the experiment run. It holds everything needed to execute the experiment:
+// the evaluator with its parameters, the NEAT options, the seed genome, the number of trials, the random seed,
+// and the output layout. See data/xor_experiment.yml for example.
+type Manifest struct {
+	// The name of the experiment, also used as the name of the results files
+	Name string `yaml:"name"`
+	// The evaluator of the experiment
+	Evaluator EvaluatorConfig `yaml:"evaluator"`
+	// The NEAT options of the experiment
+	Options OptionsConfig `yaml:"options"`
+	// The seed genome to start evolution with
+	Genome GenomeConfig `yaml:"genome"`
+	// The number of trials to execute, overrides the num_runs option if positive
+	Trials int `yaml:"trials,omitempty"`
+	// The maximal number of trials to be executed concurrently
+	ConcurrentTrials int `yaml:"concurrent_trials,omitempty"`
+	// The seed of the random-number generator, the current time is used if zero
+	Seed int64 `yaml:"seed,omitempty"`
+	// The maximal fitness score, the default of the evaluator is used if zero
+	MaxFitnessScore float64 `yaml:"max_fitness_score,omitempty"`
+	// The flag to run structural analysis of each generation champion, which may be expensive for recurrent networks
+	AnalyzeChampions bool `yaml:"analyze_champions,omitempty"`
+	// The output layout of the experiment
+	Output OutputConfig `yaml:"output"`
+
+	// BaseDir the directory to resolve relative paths of the options and the genome files against. It is set to
+	// the directory of the manifest file by LoadManifestFile.
+	BaseDir string `yaml:"-"`
+}
+
+// EvaluatorConfig The name of the experiment evaluator with its parameters
+type EvaluatorConfig struct {
+	// The name of the registered experiment evaluator, e.g. XOR or cart_pole (see Register)
+	Name string `yaml:"name"`
+	// The evaluator specific parameters
+	Parameters map[string]interface{} `yaml:"parameters,omitempty"`
+}
+
+// OptionsConfig The source of the NEAT options, which can be either loaded from a file or defined inline. The inline
+// values override the values loaded from the file, or the defaults if the file is not set.
+type OptionsConfig struct {
+	// The path to the options file in the plain text (.neat) or YAML (.yml, .yaml) format
+	Path string `yaml:"path,omitempty"`
+	// The values of the options by their names
+	Values map[string]interface{} `yaml:"values,omitempty"`
+}
+
+// GenomeConfig The source of the seed genome, which can be either loaded from a file or defined inline
+type GenomeConfig struct {
+	// The path to the genome file in the plain text or YAML (.yml, .yaml) format
+	Path string `yaml:"path,omitempty"`
+	// The inline genome definition
+	Inline string `yaml:"inline,omitempty"`
+	// The encoding of the genome definition [plain, yaml], if empty it is detected by the file extension
+	Encoding string `yaml:"encoding,omitempty"`
+}
+
+// OutputConfig The output layout of the experiment
+type OutputConfig struct {
+	// The output directory, ./out/<name> if empty
+	Dir string `yaml:"dir,omitempty"`
+	// If true the existing output directory is overwritten, otherwise it is backed up
+	Overwrite bool `yaml:"overwrite,omitempty"`
+	// If true the JSON logs of each trial are written into the output directory
+	TrialLogs bool `yaml:"trial_logs,omitempty"`
+	// The formats of the results files, all supported formats if empty (see ExportFormats)
+	Formats []string `yaml:"formats,omitempty"`
+}
+
+// LoadManifest Loads the experiment manifest encoded as YAML from provided reader
+func LoadManifest(r io.Reader) (*Manifest, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	m := &Manifest{}
+	if err := dec.Decode(m); err != nil {
+		return nil, errors.Wrap(err, "failed to decode experiment manifest from YAML")
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadManifestFile Loads the experiment manifest from the file at given path. The relative paths of the options and
+// the genome files are resolved against the directory of the manifest file.
+func LoadManifestFile(path string) (*Manifest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	m, err := LoadManifest(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load experiment manifest: [%s]", path)
+	}
+	m.BaseDir = filepath.Dir(path)
+	return m, nil
+}
+
+// Validate Checks that the manifest is complete and consistent
+func (m *Manifest) Validate() error {
+	if len(m.Name) == 0 {
+		return errors.New("experiment name must be set")
+	}
+	if len(m.Evaluator.Name) == 0 {
+		return errors.New("evaluator name must be set")
+	}
+	if len(m.Genome.Path) > 0 == (len(m.Genome.Inline) > 0) {
+		return errors.New("either path or inline definition of the seed genome must be set")
+	}
+	if _, err := m.Genome.encoding(); err != nil {
+		return err
+	}
+	if m.Trials < 0 || m.ConcurrentTrials < 0 {
+		return errors.Errorf("number of trials must not be negative: trials=%d, concurrent_trials=%d",
+			m.Trials, m.ConcurrentTrials)
+	}
+	if m.MaxFitnessScore < 0 {
+		return errors.Errorf("max fitness score must not be negative: %f", m.MaxFitnessScore)
+	}
+	for _, format := range m.Output.Formats {
+		if err := ValidateExportFormat(format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadOptions Loads the NEAT options defined by this manifest. The number of trials set in the manifest overrides
+// the num_runs option.
+func (m *Manifest) LoadOptions() (*neat.Options, error) {
+	var opts *neat.Options
+	if len(m.Options.Path) > 0 {
+		path := m.resolve(m.Options.Path)
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open NEAT options file")
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		if isYAMLFile(path) {
+			opts, err = neat.LoadYAMLOptions(file)
+		} else {
+			opts, err = neat.LoadNeatOptions(file)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load NEAT options: [%s]", path)
+		}
+	} else {
+		opts = neat.DefaultOptions()
+	}
+	for name, value := range m.Options.Values {
+		if err := opts.Set(name, neat.OptionValueString(value)); err != nil {
+			return nil, err
+		}
+	}
+	if m.Trials > 0 {
+		opts.NumRuns = m.Trials
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := neat.InitLogger(opts.LogLevel); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize logger")
+	}
+	return opts, nil
+}
+
+// LoadStartGenome Loads the seed genome defined by this manifest
+func (m *Manifest) LoadStartGenome() (*genetics.Genome, error) {
+	encoding, err := m.Genome.encoding()
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader
+	if len(m.Genome.Inline) > 0 {
+		r = strings.NewReader(strings.TrimSpace(m.Genome.Inline))
+	} else {
+		file, err := os.Open(m.resolve(m.Genome.Path))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open genome file")
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		r = file
+	}
+	reader, err := genetics.NewGenomeReader(r, encoding)
+	if err != nil {
+		return nil, err
+	}
+	genome, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read seed genome")
+	}
+	return genome, nil
+}
+
+// OutputDir Returns the output directory of the experiment
+func (m *Manifest) OutputDir() string {
+	if len(m.Output.Dir) > 0 {
+		return m.Output.Dir
+	}
+	return filepath.Join(".", "out", m.Name)
+}
+
+// OutputFormats Returns the formats of the results files to be written
+func (m *Manifest) OutputFormats() []string {
+	if len(m.Output.Formats) > 0 {
+		return m.Output.Formats
+	}
+	return ExportFormats
+}
+
+// Write Writes this manifest into provided writer encoded as YAML, which can be loaded with LoadManifest
+func (m *Manifest) Write(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resolve Returns provided path resolved against the base directory of the manifest
+func (m *Manifest) resolve(path string) string {
+	if filepath.IsAbs(path) || len(m.BaseDir) == 0 {
+		return path
+	}
+	return filepath.Join(m.BaseDir, path)
+}
+
+// encoding Returns the encoding of the genome definition
+func (g *GenomeConfig) encoding() (genetics.GenomeEncoding, error) {
+	return ResolveGenomeEncoding(g.Encoding, g.Path)
+}
+
+// ResolveGenomeEncoding Returns the genome encoding with given name, either "plain" or "yaml". If name is empty, the
+// encoding is detected by the extension of the file with given path.
+func ResolveGenomeEncoding(name, path string) (genetics.GenomeEncoding, error) {
+	switch name {
+	case "plain":
+		return genetics.PlainGenomeEncoding, nil
+	case "yaml":
+		return genetics.YAMLGenomeEncoding, nil
+	case "":
+		if isYAMLFile(path) {
+			return genetics.YAMLGenomeEncoding, nil
+		}
+		return genetics.PlainGenomeEncoding, nil
+	default:
+		return 0, errors.Errorf("unsupported genome encoding: [%s]", name)
+	}
+}
+
+func isYAMLFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// Bool Returns the boolean value of the evaluator parameter with given name or default value if parameter not set
+func (c *EvaluatorConfig) Bool(name string, def bool) (bool, error) {
+	if value, ok := c.Parameters[name]; ok {
+		b, err := cast.ToBoolE(value)
+		return b, errors.Wrapf(err, "invalid value of evaluator parameter: [%s]", name)
+	}
+	return def, nil
+}
+
+// Int Returns the integer value of the evaluator parameter with given name or default value if parameter not set
+func (c *EvaluatorConfig) Int(name string, def int) (int, error) {
+	if value, ok := c.Parameters[name]; ok {
+		i, err := cast.ToIntE(value)
+		return i, errors.Wrapf(err, "invalid value of evaluator parameter: [%s]", name)
+	}
+	return def, nil
+}
+
+// Float Returns the float value of the evaluator parameter with given name or default value if parameter not set
+func (c *EvaluatorConfig) Float(name string, def float64) (float64, error) {
+	if value, ok := c.Parameters[name]; ok {
+		f, err := cast.ToFloat64E(value)
+		return f, errors.Wrapf(err, "invalid value of evaluator parameter: [%s]", name)
+	}
+	return def, nil
+}
+
+// String Returns the string value of the evaluator parameter with given name or default value if parameter not set
+func (c *EvaluatorConfig) String(name string, def string) (string, error) {
+	if value, ok := c.Parameters[name]; ok {
+		s, err := cast.ToStringE(value)
+		return s, errors.Wrapf(err, "invalid value of evaluator parameter: [%s]", name)
+	}
+	return def, nil
+}
+
+// WriteRecord Writes the copy of the manifest, the effective NEAT options, and the seed genome into the output directory
+// to keep record of the experiment configuration next to its results. The copy of the manifest refers to the recorded
+// options and genome files and holds the effective seed of the random-number generator and the number of trials, thus
+// the experiment can be repeated by running the recorded manifest.
+func (m *Manifest) WriteRecord(outDir string, opts *neat.Options, genome *genetics.Genome, seed int64) error {
+	record := *m
+	record.Options = OptionsConfig{Path: ManifestOptionsFileName}
+	record.Genome = GenomeConfig{Path: ManifestGenomeFileName, Encoding: "plain"}
+	record.Seed = seed
+	record.Trials = opts.NumRuns
+	files := map[string]func(io.Writer) error{
+		ManifestFileName:        record.Write,
+		ManifestOptionsFileName: opts.WriteYAML,
+		ManifestGenomeFileName:  genome.Write,
+	}
+	for name, write := range files {
+		var buf bytes.Buffer
+		if err := write(&buf); err != nil {
+			return errors.Wrapf(err, "failed to write: [%s]", name)
+		}
+		if err := ioutil.WriteFile(filepath.Join(outDir, name), buf.Bytes(), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package experiment
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const inlineGenomeManifest = `
+name: inline
+evaluator:
+  name: test
+  parameters:
+    markov: false
+    steps: 100
+    action_type: discrete
+options:
+  values:
+    pop_size: 20
+    compat_threshold: 0.5
+    node_activators: [SineActivation 0.5, LinearActivation 0.5]
+genome:
+  inline: |
+    genomestart 1
+    trait 1 0.1 0 0 0 0 0 0 0
+    node 1 0 1 1
+    node 2 0 0 2
+    gene 1 1 2 0.5 false 1 0 true
+    genomeend 1
+trials: 3
+output:
+  formats: [csv, json]
+`
+
+func TestLoadManifestFile(t *testing.T) {
+	m, err := LoadManifestFile("../data/xor_experiment.yml")
+	require.NoError(t, err, "failed to load manifest")
+	assert.Equal(t, "XOR", m.Name)
+	assert.Equal(t, "XOR", m.Evaluator.Name)
+	assert.Equal(t, filepath.Join("..", "data"), m.BaseDir)
+	assert.Equal(t, int64(42), m.Seed)
+	assert.Equal(t, 16.0, m.MaxFitnessScore)
+	assert.Equal(t, "./out/xor", m.OutputDir())
+
+	opts, err := m.LoadOptions()
+	require.NoError(t, err, "failed to load options")
+	assert.Equal(t, 10, opts.NumRuns, "trials must override num_runs")
+	assert.Equal(t, 200, opts.PopSize)
+
+	genome, err := m.LoadStartGenome()
+	require.NoError(t, err, "failed to load start genome")
+	assert.Equal(t, 1, genome.Id)
+	assert.Len(t, genome.Nodes, 4)
+}
+
+func TestLoadManifest_inline(t *testing.T) {
+	m, err := LoadManifest(strings.NewReader(inlineGenomeManifest))
+	require.NoError(t, err, "failed to load manifest")
+	assert.Equal(t, filepath.Join(".", "out", "inline"), m.OutputDir())
+	assert.Equal(t, []string{ExportFormatCSV, ExportFormatJSON}, m.OutputFormats())
+
+	opts, err := m.LoadOptions()
+	require.NoError(t, err, "failed to load options")
+	assert.Equal(t, 20, opts.PopSize)
+	assert.Equal(t, 0.5, opts.CompatThreshold)
+	assert.Equal(t, 3, opts.NumRuns)
+	assert.Len(t, opts.NodeActivators, 2)
+	assert.Equal(t, neat.DefaultOptions().MutateAddNodeProb, opts.MutateAddNodeProb)
+
+	genome, err := m.LoadStartGenome()
+	require.NoError(t, err, "failed to load start genome")
+	assert.Len(t, genome.Nodes, 2)
+	assert.Len(t, genome.Genes, 1)
+
+	markov, err := m.Evaluator.Bool("markov", true)
+	require.NoError(t, err)
+	assert.False(t, markov)
+	steps, err := m.Evaluator.Int("steps", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 100, steps)
+	action, err := m.Evaluator.String("action_type", "continuous")
+	require.NoError(t, err)
+	assert.Equal(t, "discrete", action)
+	rate, err := m.Evaluator.Float("rate", 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, rate)
+	_, err = m.Evaluator.Int("action_type", 0)
+	assert.Error(t, err)
+}
+
+func TestLoadManifest_invalid(t *testing.T) {
+	testCases := map[string]string{
+		"no name":        "evaluator: {name: XOR}\ngenome: {path: genes}",
+		"no evaluator":   "name: test\ngenome: {path: genes}",
+		"no genome":      "name: test\nevaluator: {name: XOR}",
+		"both genomes":   "name: test\nevaluator: {name: XOR}\ngenome: {path: genes, inline: genes}",
+		"bad encoding":   "name: test\nevaluator: {name: XOR}\ngenome: {path: genes, encoding: xml}",
+		"bad format":     "name: test\nevaluator: {name: XOR}\ngenome: {path: genes}\noutput: {formats: [xls]}",
+		"negative":       "name: test\nevaluator: {name: XOR}\ngenome: {path: genes}\ntrials: -1",
+		"unknown field":  "name: test\nevaluator: {name: XOR}\ngenome: {path: genes}\nunknown: 1",
+		"malformed YAML": "name: [test",
+	}
+	for name, manifest := range testCases {
+		_, err := LoadManifest(strings.NewReader(manifest))
+		assert.Error(t, err, name)
+	}
+
+	m, err := LoadManifest(strings.NewReader("name: test\nevaluator: {name: XOR}\ngenome: {path: genes}\noptions: {values: {pop_size: 0}}"))
+	require.NoError(t, err)
+	_, err = m.LoadOptions()
+	assert.Error(t, err, "invalid options expected")
+}
+
+func TestResolveGenomeEncoding(t *testing.T) {
+	cases := []struct {
+		name, path string
+		expected   genetics.GenomeEncoding
+	}{
+		{"", "genome.yml", genetics.YAMLGenomeEncoding},
+		{"", "genome.YAML", genetics.YAMLGenomeEncoding},
+		{"", "xorstartgenes", genetics.PlainGenomeEncoding},
+		{"plain", "genome.yml", genetics.PlainGenomeEncoding},
+		{"yaml", "genome", genetics.YAMLGenomeEncoding},
+	}
+	for _, c := range cases {
+		encoding, err := ResolveGenomeEncoding(c.name, c.path)
+		require.NoError(t, err, c.path)
+		assert.Equal(t, c.expected, encoding, c.path)
+	}
+	_, err := ResolveGenomeEncoding("json", "genome")
+	assert.EqualError(t, err, "unsupported genome encoding: [json]")
+}
+
+func TestManifest_WriteRecord(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "manifest")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	m, err := LoadManifest(strings.NewReader(inlineGenomeManifest))
+	require.NoError(t, err)
+	opts, err := m.LoadOptions()
+	require.NoError(t, err)
+	genome, err := m.LoadStartGenome()
+	require.NoError(t, err)
+
+	opts.NumRuns = 7
+	err = m.WriteRecord(outDir, opts, genome, 42)
+	require.NoError(t, err, "failed to write record")
+
+	written, err := LoadManifestFile(filepath.Join(outDir, ManifestFileName))
+	require.NoError(t, err, "failed to load written manifest")
+	assert.Equal(t, m.Evaluator, written.Evaluator)
+	assert.Equal(t, GenomeConfig{Path: ManifestGenomeFileName, Encoding: "plain"}, written.Genome)
+	assert.Equal(t, OptionsConfig{Path: ManifestOptionsFileName}, written.Options)
+	assert.Equal(t, int64(42), written.Seed, "effective seed expected")
+	assert.Equal(t, 7, written.Trials, "effective number of trials expected")
+
+	// the recorded manifest refers to the recorded files
+	recordedOpts, err := written.LoadOptions()
+	require.NoError(t, err, "failed to load recorded options")
+	assert.Equal(t, opts, recordedOpts)
+	recordedGenome, err := written.LoadStartGenome()
+	require.NoError(t, err, "failed to load recorded genome")
+	equal, err := genome.IsEqual(recordedGenome)
+	require.NoError(t, err)
+	assert.True(t, equal, "recorded genome must be equal to the seed genome")
+
+	optFile, err := os.Open(filepath.Join(outDir, ManifestOptionsFileName))
+	require.NoError(t, err)
+	writtenOpts, err := neat.LoadYAMLOptions(optFile)
+	_ = optFile.Close()
+	require.NoError(t, err)
+	assert.Equal(t, opts, writtenOpts)
+
+	_, err = os.Stat(filepath.Join(outDir, ManifestGenomeFileName))
+	assert.NoError(t, err)
+}
+
+func TestExperiment_WriteFiles(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	exp := Experiment{Id: 1, Name: "test", Trials: Trials{*buildTestTrial(1, 2)}}
+	err = exp.WriteFiles(outDir, "test", []string{ExportFormatCSV, ExportFormatJSON})
+	require.NoError(t, err, "failed to write files")
+
+	data, err := ioutil.ReadFile(filepath.Join(outDir, "test.csv"))
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, exp.WriteCSV(&buf))
+	assert.Equal(t, buf.Bytes(), data)
+	_, err = os.Stat(filepath.Join(outDir, "test.json"))
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(outDir, "test.npz"))
+	assert.True(t, os.IsNotExist(err))
+
+	err = exp.WriteFiles(outDir, "test", []string{"xls"})
+	assert.Error(t, err)
+}
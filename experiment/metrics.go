@@ -0,0 +1,151 @@
+package experiment
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// The HTTP paths of the metrics endpoints
+const (
+	// MetricsPath The path of the endpoint serving metrics in the Prometheus text exposition format
+	MetricsPath = "/metrics"
+	// MetricsJSONPath The path of the endpoint serving the JSON snapshot of metrics
+	MetricsJSONPath = "/metrics.json"
+)
+
+// MetricsSnapshot the snapshot of the live metrics of the experiment's execution
+type MetricsSnapshot struct {
+	// The ID of the most recently evaluated trial
+	TrialId int `json:"trial_id"`
+	// The ID of the most recently evaluated generation
+	Generation int `json:"generation"`
+	// The fitness score of the champion of the most recently evaluated generation
+	BestFitness float64 `json:"best_fitness"`
+	// The mean fitness score of the population
+	MeanFitness float64 `json:"mean_fitness"`
+	// The standard deviation of fitness scores of the population
+	StandardDev float64 `json:"standard_dev"`
+	// The number of species in the population
+	SpeciesCount int `json:"species_count"`
+	// The complexity of the champion's phenotype
+	ChampionComplexity int `json:"champion_complexity"`
+	// The total number of organisms evaluated since the first trial started
+	Evaluations int `json:"evaluations"`
+	// The average number of organisms evaluated per second since the first trial started
+	EvaluationsPerSecond float64 `json:"evaluations_per_second"`
+	// The number of trials started
+	TrialsStarted int `json:"trials_started"`
+	// The number of trials finished
+	TrialsFinished int `json:"trials_finished"`
+	// The time when metrics was updated last time
+	Updated time.Time `json:"updated"`
+}
+
+// MetricsCollector the EventObserver which collects live metrics of the experiment's execution and serves them over
+// HTTP in the Prometheus text exposition format and as the JSON snapshot. When trials executed concurrently, the
+// metrics of the most recently evaluated generation are reported.
+type MetricsCollector struct {
+	snapshot MetricsSnapshot
+	started  time.Time
+	mutex    sync.Mutex
+}
+
+// NewMetricsCollector Creates new metrics collector, which should be subscribed to the experiment's EventBus
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{}
+}
+
+// OnEvent Implements EventObserver
+func (c *MetricsCollector) OnEvent(event Event) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch event.Type {
+	case EventTrialStarted:
+		if c.started.IsZero() {
+			c.started = event.Time
+		}
+		c.snapshot.TrialsStarted++
+	case EventTrialFinished:
+		c.snapshot.TrialsFinished++
+	case EventGenerationEvaluated:
+		c.snapshot.TrialId = event.TrialId
+		c.snapshot.Generation = event.Generation
+		c.snapshot.BestFitness = event.Fitness
+		c.snapshot.MeanFitness = event.MeanFitness
+		c.snapshot.StandardDev = event.StandardDev
+		c.snapshot.SpeciesCount = event.Count
+		c.snapshot.ChampionComplexity = event.Complexity
+		c.snapshot.Evaluations += event.Evaluations
+		if elapsed := event.Time.Sub(c.started).Seconds(); !c.started.IsZero() && elapsed > 0 {
+			c.snapshot.EvaluationsPerSecond = float64(c.snapshot.Evaluations) / elapsed
+		}
+	default:
+		return
+	}
+	c.snapshot.Updated = event.Time
+}
+
+// Snapshot Returns the current snapshot of metrics
+func (c *MetricsCollector) Snapshot() MetricsSnapshot {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.snapshot
+}
+
+// WritePrometheus Writes the current metrics into provided writer in the Prometheus text exposition format
+func (c *MetricsCollector) WritePrometheus(w io.Writer) error {
+	s := c.Snapshot()
+	metrics := []struct {
+		name, kind, help string
+		value            float64
+	}{
+		{"neat_trial", "gauge", "The ID of the most recently evaluated trial.", float64(s.TrialId)},
+		{"neat_generation", "gauge", "The ID of the most recently evaluated generation.", float64(s.Generation)},
+		{"neat_best_fitness", "gauge", "The fitness score of the generation champion.", s.BestFitness},
+		{"neat_mean_fitness", "gauge", "The mean fitness score of the population.", s.MeanFitness},
+		{"neat_fitness_stddev", "gauge", "The standard deviation of fitness scores of the population.", s.StandardDev},
+		{"neat_species", "gauge", "The number of species in the population.", float64(s.SpeciesCount)},
+		{"neat_champion_complexity", "gauge", "The complexity of the champion phenotype.", float64(s.ChampionComplexity)},
+		{"neat_evaluations_total", "counter", "The total number of organisms evaluated.", float64(s.Evaluations)},
+		{"neat_evaluations_per_second", "gauge", "The average number of organisms evaluated per second.", s.EvaluationsPerSecond},
+		{"neat_trials_started_total", "counter", "The number of trials started.", float64(s.TrialsStarted)},
+		{"neat_trials_finished_total", "counter", "The number of trials finished.", float64(s.TrialsFinished)},
+	}
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", m.name, m.help, m.name, m.kind, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// Handler Returns the HTTP handler serving metrics at MetricsPath and MetricsJSONPath
+func (c *MetricsCollector) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(MetricsPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := c.WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	mux.HandleFunc(MetricsJSONPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(c.Snapshot()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	return mux
+}
+
+// NewMetricsServer Creates HTTP server serving metrics of the collector at provided address. The server should be
+// started by the caller, e.g. with ListenAndServe in the separate goroutine.
+func NewMetricsServer(addr string, collector *MetricsCollector) *http.Server {
+	return &http.Server{Addr: addr, Handler: collector.Handler()}
+}
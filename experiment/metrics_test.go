@@ -0,0 +1,95 @@
+package experiment
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsCollector_OnEvent(t *testing.T) {
+	started := time.Now()
+	collector := NewMetricsCollector()
+	bus := NewEventBus(collector)
+	bus.Publish(Event{Type: EventTrialStarted, Time: started, TrialId: 1})
+	bus.Publish(Event{Type: EventGenerationEvaluated, Time: started.Add(time.Second), TrialId: 1, Generation: 0,
+		Fitness: 3.0, Evaluations: 100})
+	bus.Publish(Event{Type: EventGenerationEvaluated, Time: started.Add(2 * time.Second), TrialId: 1, Generation: 1,
+		Fitness: 4.0, MeanFitness: 2.0, StandardDev: 0.5, Count: 3, Complexity: 12, Evaluations: 100})
+	bus.Publish(Event{Type: EventSpeciesCreated, Time: started.Add(3 * time.Second), TrialId: 1, Generation: 1})
+
+	snapshot := collector.Snapshot()
+	assert.Equal(t, MetricsSnapshot{
+		TrialId:              1,
+		Generation:           1,
+		BestFitness:          4.0,
+		MeanFitness:          2.0,
+		StandardDev:          0.5,
+		SpeciesCount:         3,
+		ChampionComplexity:   12,
+		Evaluations:          200,
+		EvaluationsPerSecond: 100,
+		TrialsStarted:        1,
+		Updated:              started.Add(2 * time.Second),
+	}, snapshot)
+}
+
+func TestMetricsCollector_Handler(t *testing.T) {
+	collector := NewMetricsCollector()
+	collector.OnEvent(Event{Type: EventGenerationEvaluated, TrialId: 2, Generation: 5, Fitness: 1.5, Count: 4})
+	server := httptest.NewServer(collector.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + MetricsPath)
+	require.NoError(t, err, "failed to scrape metrics")
+	body, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain"))
+	text := string(body)
+	for _, line := range []string{
+		"# TYPE neat_generation gauge", "neat_trial 2", "neat_generation 5", "neat_best_fitness 1.5",
+		"neat_species 4", "# TYPE neat_evaluations_total counter",
+	} {
+		assert.Contains(t, text, line+"\n")
+	}
+
+	resp, err = http.Get(server.URL + MetricsJSONPath)
+	require.NoError(t, err, "failed to get metrics snapshot")
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	var snapshot MetricsSnapshot
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&snapshot))
+	assert.Equal(t, 5, snapshot.Generation)
+	assert.Equal(t, 1.5, snapshot.BestFitness)
+}
+
+func TestExperiment_Execute_Metrics(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 1
+	opts.NumGenerations = 2
+
+	collector := NewMetricsCollector()
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	exp := Experiment{Id: 1, Events: NewEventBus(collector)}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	snapshot := collector.Snapshot()
+	assert.Equal(t, 1, snapshot.Generation)
+	assert.Equal(t, opts.PopSize*opts.NumGenerations, snapshot.Evaluations)
+	assert.Equal(t, 1, snapshot.TrialsFinished)
+	assert.True(t, snapshot.MeanFitness > 0, "mean fitness expected")
+	assert.True(t, snapshot.StandardDev > 0, "fitness standard deviation expected")
+	assert.True(t, snapshot.SpeciesCount > 0, "species expected")
+	assert.True(t, snapshot.ChampionComplexity > 0, "champion complexity expected")
+}
@@ -0,0 +1,303 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// OrganismEvaluator the interface describing evaluator of the single organism. It is the only thing to be implemented
+// by the new task in order to get fully functional GenerationEvaluator with ParallelGenerationEvaluator.
+//
+// The implementation should not modify shared state, because organisms may be evaluated concurrently. Each organism has
+// its own phenotype, thus it is safe to activate it within evaluation.
+type OrganismEvaluator interface {
+	// OrganismEvaluate Invoked to evaluate provided organism within given execution context. Returns the fitness
+	// score of the organism, the error value (the distance from the ideal solution), and the flag to indicate whether
+	// organism is a winner, i.e. solves the task.
+	OrganismEvaluate(organism *genetics.Organism, opts *neat.Options) (fitness, errValue float64, winner bool, err error)
+}
+
+// ContextOrganismEvaluator the optional interface which can be implemented by OrganismEvaluator to support
+// interruption of the organism evaluation. The provided context holds the NEAT options (see neat.FromContext) and
+// is canceled when the evaluation deadline exceeded or the whole experiment is interrupted.
+type ContextOrganismEvaluator interface {
+	// OrganismEvaluateContext Invoked to evaluate provided organism within given context. Returns the same values as
+	// OrganismEvaluator.OrganismEvaluate.
+	OrganismEvaluateContext(ctx context.Context, organism *genetics.Organism) (fitness, errValue float64, winner bool, err error)
+}
+
+// GenerationPostEvaluator the optional interface which can be implemented by OrganismEvaluator to do additional
+// processing of the generation after all organisms evaluated, e.g. to run extra tests of the generation champion.
+// It is invoked sequentially before the generation statistics collected.
+type GenerationPostEvaluator interface {
+	// PostEvaluate Invoked after all organisms of the population were evaluated.
+	PostEvaluate(pop *genetics.Population, epoch *Generation, opts *neat.Options) error
+}
+
+// ParallelGenerationEvaluator the GenerationEvaluator which evaluates organisms of the population concurrently using
+// provided OrganismEvaluator on the bounded pool of workers. After evaluation, it collects the winner of the generation
+// and statistics about the population, and dumps the population and winner genomes into the output directory.
+type ParallelGenerationEvaluator struct {
+	// The evaluator of individual organisms
+	Evaluator OrganismEvaluator
+	// The output path to store execution results. If empty, nothing will be dumped.
+	OutputPath string
+	// The prefix of the dumped genome files names, e.g. "xor" produces "xor_winner_5-6"
+	NamePrefix string
+	// The optional function to build the name of the dumped genome file of given kind, e.g. "winner" or "optimal".
+	// If nil, the name is built from NamePrefix, kind, and the number of nodes and links of the organism's phenotype.
+	GenomeFileName func(org *genetics.Organism, kind string) string
+	// The maximal number of organisms evaluated concurrently. If zero or negative, the number of CPUs will be used.
+	WorkersCount int
+	// The number of genome nodes in the optimal solution. If positive, the winner genomes of this size will be
+	// dumped as optimal ones.
+	OptimalNodesCount int
+	// The maximal duration of single organism evaluation. If zero, the evaluation time is unlimited. The timeout is
+	// only enforced for the ContextOrganismEvaluator which receives the context with deadline and is expected to
+	// return when it is done.
+	OrganismTimeout time.Duration
+	// The fitness to be assigned to the organism which evaluation failed due to the panic or timeout. The error value
+	// of such organism is set to 1.0.
+	PenaltyFitness float64
+}
+
+// organismResult holds the results of single organism evaluation
+type organismResult struct {
+	fitness  float64
+	errValue float64
+	winner   bool
+	// the error which aborts the generation evaluation
+	err error
+	// the reason of the failed evaluation which is penalized
+	failure error
+}
+
+// NewParallelGenerationEvaluator Creates new generation evaluator which uses provided organism evaluator with given
+// number of concurrent workers. The files with dumped genomes are stored in the outputPath with namePrefix prepended.
+func NewParallelGenerationEvaluator(evaluator OrganismEvaluator, outputPath, namePrefix string, workersCount int) *ParallelGenerationEvaluator {
+	return &ParallelGenerationEvaluator{
+		Evaluator:    evaluator,
+		OutputPath:   outputPath,
+		NamePrefix:   namePrefix,
+		WorkersCount: workersCount,
+	}
+}
+
+// GenerationEvaluate This method evaluates one epoch for given population and prints results into output directory if any.
+func (e *ParallelGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *Generation, opts *neat.Options) error {
+	return e.GenerationEvaluateContext(neat.NewContext(context.Background(), opts), pop, epoch)
+}
+
+// GenerationEvaluateContext This method evaluates one epoch for given population within given context and prints
+// results into output directory if any. The evaluations of organisms which panic or exceed OrganismTimeout are
+// penalized with PenaltyFitness and counted in Generation.FailedEvaluations.
+func (e *ParallelGenerationEvaluator) GenerationEvaluateContext(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	post, _ := e.Evaluator.(GenerationPostEvaluator)
+	return e.generationEvaluate(ctx, pop, epoch, e.WorkersCount, post)
+}
+
+// generationEvaluate Evaluates one epoch using given number of concurrent workers and invokes provided post evaluator,
+// if any, after all organisms evaluated
+func (e *ParallelGenerationEvaluator) generationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation,
+	workers int, post GenerationPostEvaluator) error {
+	opts, found := neat.FromContext(ctx)
+	if !found {
+		return neat.ErrNEATOptionsNotFound
+	}
+	failed, err := e.evaluateOrganisms(ctx, pop.Organisms, opts, workers)
+	if err != nil {
+		return err
+	}
+	epoch.FailedEvaluations = failed
+
+	// find the winner in the population order to keep results deterministic
+	for _, org := range pop.Organisms {
+		if org.IsWinner && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = opts.PopSize*epoch.Id + org.Genotype.Id
+			epoch.Best = org
+			if e.OptimalNodesCount > 0 && epoch.WinnerNodes == e.OptimalNodesCount {
+				if err := e.dumpGenome(ctx, org, epoch, "optimal"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if post != nil {
+		if err := post.PostEvaluate(pop, epoch, opts); err != nil {
+			return err
+		}
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+
+	if len(e.OutputPath) == 0 {
+		return nil
+	}
+
+	// Only print to file every print_every generations
+	if epoch.Solved || (opts.PrintEvery > 0 && epoch.Id%opts.PrintEvery == 0) {
+		popPath := fmt.Sprintf("%s/gen_%d", OutDirForTrial(e.OutputPath, epoch.TrialId), epoch.Id)
+		if file, err := os.Create(popPath); err != nil {
+			return err
+		} else if err = pop.WriteBySpecies(file); err != nil {
+			neat.LoggerFromContext(ctx).Error("Failed to dump population", "error", err)
+			return err
+		}
+		publishCheckpoint(ctx, epoch, popPath)
+	}
+
+	if epoch.Solved && epoch.Best != nil {
+		// Prints the winner organism to file!
+		if err := e.dumpGenome(ctx, epoch.Best, epoch, "winner"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evaluateOrganisms Evaluates provided organisms concurrently and stores results of evaluation into organisms.
+// Returns the number of failed evaluations and the first encountered error if any.
+func (e *ParallelGenerationEvaluator) evaluateOrganisms(ctx context.Context, organisms []*genetics.Organism, opts *neat.Options, workers int) (int, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(organisms) {
+		workers = len(organisms)
+	}
+
+	jobs := make(chan *genetics.Organism)
+	errs := make(chan error, workers)
+	failures := make(chan int, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var workerErr error
+			failed := 0
+			for org := range jobs {
+				if workerErr != nil {
+					// drain remaining jobs
+					continue
+				}
+				res := e.evaluateOrganism(ctx, org, opts)
+				if res.err != nil {
+					workerErr = res.err
+					continue
+				}
+				if res.failure != nil {
+					neat.LoggerFromContext(ctx).Warn("Evaluation of organism failed",
+						neat.LogKeyOrganism, org.Genotype.Id, "error", res.failure)
+					failed++
+					res.fitness, res.errValue, res.winner = e.PenaltyFitness, 1.0, false
+				}
+				org.Fitness = res.fitness
+				org.Error = res.errValue
+				org.IsWinner = res.winner
+			}
+			failures <- failed
+			if workerErr != nil {
+				errs <- workerErr
+			}
+		}()
+	}
+
+	for _, org := range organisms {
+		jobs <- org
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	close(failures)
+
+	failed := 0
+	for count := range failures {
+		failed += count
+	}
+	// return the first error if any
+	return failed, <-errs
+}
+
+// evaluateOrganism Evaluates single organism recovering from panic. The evaluation deadline is passed to the
+// ContextOrganismEvaluator within context, and the evaluation is waited to return before checking the deadline.
+func (e *ParallelGenerationEvaluator) evaluateOrganism(ctx context.Context, org *genetics.Organism, opts *neat.Options) organismResult {
+	if err := ctx.Err(); err != nil {
+		return organismResult{err: err}
+	}
+	ce, withContext := e.Evaluator.(ContextOrganismEvaluator)
+	orgCtx := ctx
+	if withContext && e.OrganismTimeout > 0 {
+		var cancel context.CancelFunc
+		orgCtx, cancel = context.WithTimeout(ctx, e.OrganismTimeout)
+		defer cancel()
+	}
+
+	res := func() (res organismResult) {
+		defer func() {
+			if r := recover(); r != nil {
+				res = organismResult{failure: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		if withContext {
+			res.fitness, res.errValue, res.winner, res.err = ce.OrganismEvaluateContext(orgCtx, org)
+		} else {
+			res.fitness, res.errValue, res.winner, res.err = e.Evaluator.OrganismEvaluate(org, opts)
+		}
+		return res
+	}()
+	if res.failure != nil {
+		return res
+	}
+	if err := ctx.Err(); err != nil {
+		// the whole evaluation was interrupted
+		return organismResult{err: err}
+	}
+	if orgCtx.Err() == context.DeadlineExceeded {
+		return organismResult{failure: fmt.Errorf("evaluation timeout of %s exceeded", e.OrganismTimeout)}
+	}
+	if res.err != nil {
+		res.err = fmt.Errorf("failed to evaluate organism: %d, reason: %s", org.Genotype.Id, res.err)
+	}
+	return res
+}
+
+// dumpGenome Dumps genome of provided organism into the trial output directory using given kind of the file name
+func (e *ParallelGenerationEvaluator) dumpGenome(ctx context.Context, org *genetics.Organism, epoch *Generation, kind string) error {
+	if len(e.OutputPath) == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("%s_%s_%d-%d", e.NamePrefix, kind, org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
+	if e.GenomeFileName != nil {
+		name = e.GenomeFileName(org, kind)
+	}
+	orgPath := fmt.Sprintf("%s/%s", OutDirForTrial(e.OutputPath, epoch.TrialId), name)
+	if file, err := os.Create(orgPath); err != nil {
+		return err
+	} else if err = org.Genotype.Write(file); err != nil {
+		neat.LoggerFromContext(ctx).Error(fmt.Sprintf("Failed to dump %s genome", kind), "error", err)
+		return err
+	} else {
+		neat.LoggerFromContext(ctx).Info(fmt.Sprintf("Generation #%d %s %d dumped to: %s", epoch.Id, kind, org.Genotype.Id, orgPath),
+			neat.LogKeyOrganism, org.Genotype.Id)
+		publishCheckpoint(ctx, epoch, orgPath)
+	}
+	return nil
+}
+
+// publishCheckpoint Publishes EventCheckpointWritten into the event bus of the context if any
+func publishCheckpoint(ctx context.Context, epoch *Generation, path string) {
+	if bus, ok := EventBusFromContext(ctx); ok {
+		bus.Publish(Event{Type: EventCheckpointWritten, TrialId: epoch.TrialId, Generation: epoch.Id, Path: path})
+	}
+}
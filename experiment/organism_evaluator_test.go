@@ -0,0 +1,269 @@
+package experiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testOrganismEvaluator assigns fitness equal to the genome ID and counts evaluated organisms
+type testOrganismEvaluator struct {
+	winnerId  int
+	failingId int
+	panicId   int
+	slowId    int
+	evaluated map[int]int
+	postCalls int
+	mutex     sync.Mutex
+}
+
+func (e *testOrganismEvaluator) OrganismEvaluate(organism *genetics.Organism, _ *neat.Options) (float64, float64, bool, error) {
+	id := organism.Genotype.Id
+	if id == e.failingId {
+		return 0, 0, false, errors.New("evaluation failed")
+	} else if id == e.panicId {
+		panic("fitness function failure")
+	} else if id == e.slowId {
+		time.Sleep(200 * time.Millisecond)
+	}
+	e.mutex.Lock()
+	e.evaluated[id]++
+	e.mutex.Unlock()
+	return float64(id), 1.0 / float64(id), id == e.winnerId, nil
+}
+
+// testContextOrganismEvaluator evaluates organisms within context, the slow organism is evaluated until context is done
+type testContextOrganismEvaluator struct {
+	*testOrganismEvaluator
+	// the number of slow evaluations returned
+	slowReturned int32
+}
+
+func (e *testContextOrganismEvaluator) OrganismEvaluateContext(ctx context.Context, organism *genetics.Organism) (float64, float64, bool, error) {
+	if organism.Genotype.Id == e.slowId {
+		<-ctx.Done()
+		atomic.AddInt32(&e.slowReturned, 1)
+		return 0, 0, false, ctx.Err()
+	}
+	opts, _ := neat.FromContext(ctx)
+	return e.OrganismEvaluate(organism, opts)
+}
+
+func (e *testOrganismEvaluator) PostEvaluate(_ *genetics.Population, _ *Generation, _ *neat.Options) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.postCalls++
+	return nil
+}
+
+func buildTestPopulation(size int) (*genetics.Population, error) {
+	species := genetics.NewSpecies(1)
+	for i := 1; i <= size; i++ {
+		org, err := genetics.NewOrganism(0, buildTestGenome(i), 1)
+		if err != nil {
+			return nil, err
+		}
+		org.Species = species
+		species.Organisms = append(species.Organisms, org)
+	}
+	return &genetics.Population{
+		Species:   []*genetics.Species{species},
+		Organisms: species.Organisms,
+	}, nil
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "parallel_evaluator")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	popSize := 20
+	pop, err := buildTestPopulation(popSize)
+	require.NoError(t, err, "failed to create population")
+
+	orgEvaluator := &testOrganismEvaluator{winnerId: 7, evaluated: make(map[int]int)}
+	evaluator := NewParallelGenerationEvaluator(orgEvaluator, outDir, "test", 3)
+	evaluator.OptimalNodesCount = 4
+
+	context := &neat.Options{PopSize: popSize, PrintEvery: 10}
+	epoch := Generation{Id: 2, TrialId: 1}
+	err = evaluator.GenerationEvaluate(pop, &epoch, context)
+	require.NoError(t, err, "failed to evaluate generation")
+
+	// check that all organisms evaluated once
+	assert.Len(t, orgEvaluator.evaluated, popSize)
+	for id, count := range orgEvaluator.evaluated {
+		assert.Equal(t, 1, count, "wrong evaluations count of organism: %d", id)
+	}
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+		assert.Equal(t, org.Genotype.Id == 7, org.IsWinner)
+	}
+	assert.Equal(t, 1, orgEvaluator.postCalls)
+
+	// check winner bookkeeping and statistics
+	assert.True(t, epoch.Solved)
+	require.NotNil(t, epoch.Best)
+	assert.Equal(t, 7, epoch.Best.Genotype.Id)
+	assert.Equal(t, 4, epoch.WinnerNodes)
+	assert.Equal(t, 3, epoch.WinnerGenes)
+	assert.Equal(t, popSize*2+7, epoch.WinnerEvals)
+	assert.Len(t, epoch.Fitness, 1)
+
+	// check dumps
+	trialDir := fmt.Sprintf("%s/1", outDir)
+	for _, name := range []string{"gen_2", "test_winner_4-3", "test_optimal_4-3"} {
+		_, err = os.Stat(fmt.Sprintf("%s/%s", trialDir, name))
+		assert.NoError(t, err, "file expected: %s", name)
+	}
+}
+
+func TestParallelGenerationEvaluator_GenomeFileName(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "parallel_evaluator")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	pop, err := buildTestPopulation(10)
+	require.NoError(t, err, "failed to create population")
+
+	orgEvaluator := &testOrganismEvaluator{winnerId: 7, evaluated: make(map[int]int)}
+	evaluator := NewParallelGenerationEvaluator(orgEvaluator, outDir, "test", 0)
+	evaluator.GenomeFileName = func(org *genetics.Organism, kind string) string {
+		return fmt.Sprintf("custom_%s_%.1f", kind, org.Fitness)
+	}
+
+	epoch := Generation{Id: 1, TrialId: 1}
+	err = evaluator.GenerationEvaluate(pop, &epoch, &neat.Options{PopSize: 10})
+	require.NoError(t, err, "failed to evaluate generation")
+
+	_, err = os.Stat(fmt.Sprintf("%s/1/custom_winner_7.0", outDir))
+	assert.NoError(t, err, "custom named winner genome file expected")
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_Error(t *testing.T) {
+	pop, err := buildTestPopulation(10)
+	require.NoError(t, err, "failed to create population")
+
+	orgEvaluator := &testOrganismEvaluator{failingId: 5, evaluated: make(map[int]int)}
+	evaluator := NewParallelGenerationEvaluator(orgEvaluator, "", "test", 0)
+
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(pop, &epoch, &neat.Options{PopSize: 10, PrintEvery: 1})
+	assert.EqualError(t, err, "failed to evaluate organism: 5, reason: evaluation failed")
+	assert.Zero(t, orgEvaluator.postCalls)
+	assert.False(t, epoch.Solved)
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluateContext_Failures(t *testing.T) {
+	popSize := 10
+	pop, err := buildTestPopulation(popSize)
+	require.NoError(t, err, "failed to create population")
+
+	orgEvaluator := &testContextOrganismEvaluator{
+		testOrganismEvaluator: &testOrganismEvaluator{panicId: 2, slowId: 4, evaluated: make(map[int]int)},
+	}
+	evaluator := NewParallelGenerationEvaluator(orgEvaluator, "", "test", 4)
+	evaluator.OrganismTimeout = 100 * time.Millisecond
+	evaluator.PenaltyFitness = -1.0
+
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: popSize, PrintEvery: 1})
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluateContext(ctx, pop, &epoch)
+	require.NoError(t, err, "failed to evaluate generation")
+
+	assert.Equal(t, 2, epoch.FailedEvaluations)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&orgEvaluator.slowReturned), "timed out evaluation expected to return")
+	for _, org := range pop.Organisms {
+		id := org.Genotype.Id
+		if id == 2 || id == 4 {
+			assert.Equal(t, -1.0, org.Fitness, "penalty expected for organism: %d", id)
+			assert.Equal(t, 1.0, org.Error)
+		} else {
+			assert.Equal(t, float64(id), org.Fitness)
+		}
+	}
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluateContext_TimeoutNotEnforced(t *testing.T) {
+	popSize := 5
+	pop, err := buildTestPopulation(popSize)
+	require.NoError(t, err, "failed to create population")
+
+	// the evaluator without context can not be interrupted, thus it is not timed out
+	orgEvaluator := &testOrganismEvaluator{slowId: 4, evaluated: make(map[int]int)}
+	evaluator := NewParallelGenerationEvaluator(orgEvaluator, "", "test", 2)
+	evaluator.OrganismTimeout = 10 * time.Millisecond
+	evaluator.PenaltyFitness = -1.0
+
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: popSize, PrintEvery: 1})
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluateContext(ctx, pop, &epoch)
+	require.NoError(t, err, "failed to evaluate generation")
+
+	assert.Zero(t, epoch.FailedEvaluations)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+	}
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluateContext_Canceled(t *testing.T) {
+	pop, err := buildTestPopulation(10)
+	require.NoError(t, err, "failed to create population")
+
+	orgEvaluator := &testOrganismEvaluator{evaluated: make(map[int]int)}
+	evaluator := NewParallelGenerationEvaluator(orgEvaluator, "", "test", 2)
+
+	ctx, cancel := context.WithCancel(neat.NewContext(context.Background(), &neat.Options{PopSize: 10}))
+	cancel()
+	err = evaluator.GenerationEvaluateContext(ctx, pop, &Generation{})
+	assert.EqualError(t, err, context.Canceled.Error())
+	assert.Len(t, orgEvaluator.evaluated, 0)
+
+	// without options
+	err = evaluator.GenerationEvaluateContext(context.Background(), pop, &Generation{})
+	assert.EqualError(t, err, neat.ErrNEATOptionsNotFound.Error())
+}
+
+func TestNewContextGenerationEvaluator(t *testing.T) {
+	pop, err := buildTestPopulation(5)
+	require.NoError(t, err, "failed to create population")
+
+	parallel := NewParallelGenerationEvaluator(&testOrganismEvaluator{evaluated: make(map[int]int)}, "", "test", 1)
+	assert.Equal(t, parallel, NewContextGenerationEvaluator(parallel), "context evaluator expected to be returned as is")
+
+	legacy := &legacyGenerationEvaluator{}
+	adapter := NewContextGenerationEvaluator(legacy)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: 5})
+	err = adapter.GenerationEvaluateContext(ctx, pop, &Generation{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, legacy.calls)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = adapter.GenerationEvaluateContext(ctx, pop, &Generation{})
+	assert.EqualError(t, err, context.Canceled.Error())
+	assert.Equal(t, 1, legacy.calls)
+}
+
+type legacyGenerationEvaluator struct {
+	calls int
+}
+
+func (e *legacyGenerationEvaluator) GenerationEvaluate(_ *genetics.Population, _ *Generation, _ *neat.Options) error {
+	e.calls++
+	return nil
+}
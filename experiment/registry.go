@@ -0,0 +1,225 @@
+package experiment
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ParameterType defines the type of value of the registered experiment parameter
+type ParameterType string
+
+const (
+	ParameterTypeBool   ParameterType = "bool"
+	ParameterTypeInt    ParameterType = "int"
+	ParameterTypeFloat  ParameterType = "float"
+	ParameterTypeString ParameterType = "string"
+)
+
+// ParameterSchema The description of the parameter of the registered experiment evaluator
+type ParameterSchema struct {
+	// The name of the parameter in the experiment manifest
+	Name string
+	// The type of the parameter value
+	Type ParameterType
+	// The default value of the parameter
+	Default interface{}
+	// The human readable description of the parameter
+	Description string
+	// The list of allowed values of the string parameter, empty if any value allowed
+	Allowed []string
+}
+
+// convert Returns provided value converted to the type of this parameter
+func (p *ParameterSchema) convert(value interface{}) (interface{}, error) {
+	var converted interface{}
+	var err error
+	switch p.Type {
+	case ParameterTypeBool:
+		converted, err = cast.ToBoolE(value)
+	case ParameterTypeInt:
+		converted, err = cast.ToIntE(value)
+	case ParameterTypeFloat:
+		converted, err = cast.ToFloat64E(value)
+	case ParameterTypeString:
+		var str string
+		if str, err = cast.ToStringE(value); err == nil && len(p.Allowed) > 0 && !contains(p.Allowed, str) {
+			err = errors.Errorf("unsupported value [%s], allowed values: %s", str, strings.Join(p.Allowed, ", "))
+		}
+		converted = str
+	default:
+		return nil, errors.Errorf("unsupported type of parameter [%s]: %s", p.Name, p.Type)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid value of parameter [%s]", p.Name)
+	}
+	return converted, nil
+}
+
+// RegisteredEvaluatorFactory Creates the generation evaluator of the registered experiment. The parameters of provided
+// configuration are validated against the schema of the registered experiment and complemented with the defaults. The
+// outDir is the directory to store the results of evaluation.
+type RegisteredEvaluatorFactory func(config EvaluatorConfig, outDir string) (GenerationEvaluator, error)
+
+// Registration The experiment registered within the experiments registry
+type Registration struct {
+	// The name of the experiment used to refer it in the experiment manifest and the command line
+	Name string
+	// The human readable description of the experiment
+	Description string
+	// The maximal fitness score the evaluator can produce, used unless overridden by the experiment manifest
+	MaxFitnessScore float64
+	// The parameters of the evaluator
+	Parameters []ParameterSchema
+	// The factory to create evaluator of the experiment
+	NewEvaluator RegisteredEvaluatorFactory
+}
+
+// Validate Checks that registration is complete and the defaults of parameters match their types
+func (r *Registration) Validate() error {
+	if len(r.Name) == 0 {
+		return errors.New("experiment name must be set")
+	}
+	if r.NewEvaluator == nil {
+		return errors.Errorf("evaluator factory of experiment [%s] must be set", r.Name)
+	}
+	names := make(map[string]bool, len(r.Parameters))
+	for _, p := range r.Parameters {
+		if names[p.Name] {
+			return errors.Errorf("duplicate parameter [%s] of experiment [%s]", p.Name, r.Name)
+		}
+		names[p.Name] = true
+		if _, err := p.convert(p.Default); err != nil {
+			return errors.Wrapf(err, "invalid default of experiment [%s]", r.Name)
+		}
+	}
+	return nil
+}
+
+// Describe Writes the human readable description of the registered experiment with its parameters
+func (r *Registration) Describe(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%s\n\t%s\n\tMax fitness score: %g\n", r.Name, r.Description, r.MaxFitnessScore); err != nil {
+		return err
+	}
+	if len(r.Parameters) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w, "\tParameters:"); err != nil {
+		return err
+	}
+	for _, p := range r.Parameters {
+		line := fmt.Sprintf("\t\t%s (%s, default: %v) - %s", p.Name, p.Type, p.Default, p.Description)
+		if len(p.Allowed) > 0 {
+			line += fmt.Sprintf(" [%s]", strings.Join(p.Allowed, ", "))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalize Returns provided evaluator parameters converted to their types and complemented with defaults
+func (r *Registration) normalize(params map[string]interface{}) (map[string]interface{}, error) {
+	schemas := make(map[string]*ParameterSchema, len(r.Parameters))
+	normalized := make(map[string]interface{}, len(r.Parameters))
+	for i := range r.Parameters {
+		p := &r.Parameters[i]
+		schemas[p.Name] = p
+		normalized[p.Name], _ = p.convert(p.Default)
+	}
+	for name, value := range params {
+		p, ok := schemas[name]
+		if !ok {
+			return nil, errors.Errorf("unknown parameter [%s] of experiment [%s]", name, r.Name)
+		}
+		converted, err := p.convert(value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[name] = converted
+	}
+	return normalized, nil
+}
+
+// registry the experiments registered by name
+var registry = struct {
+	sync.RWMutex
+	entries map[string]Registration
+}{entries: make(map[string]Registration)}
+
+// Register Registers the experiment to be found by its name. Usually invoked from the init function of the package
+// defining the experiment evaluator. Returns error if registration is invalid or the experiment with the same name
+// already registered.
+func Register(r Registration) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.entries[r.Name]; ok {
+		return errors.Errorf("experiment already registered: [%s]", r.Name)
+	}
+	registry.entries[r.Name] = r
+	return nil
+}
+
+// MustRegister Registers the experiment like Register but panics on error
+func MustRegister(r Registration) {
+	if err := Register(r); err != nil {
+		panic(err)
+	}
+}
+
+// LookupRegistration Returns the registered experiment with given name
+func LookupRegistration(name string) (Registration, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	r, ok := registry.entries[name]
+	return r, ok
+}
+
+// Registered Returns all registered experiments sorted by name
+func Registered() []Registration {
+	registry.RLock()
+	defer registry.RUnlock()
+	registrations := make([]Registration, 0, len(registry.entries))
+	for _, r := range registry.entries {
+		registrations = append(registrations, r)
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		return registrations[i].Name < registrations[j].Name
+	})
+	return registrations
+}
+
+// NewRegisteredEvaluator Creates the generation evaluator of the registered experiment named by provided configuration.
+// Returns the evaluator and the maximal fitness score it can produce.
+func NewRegisteredEvaluator(config EvaluatorConfig, outDir string) (GenerationEvaluator, float64, error) {
+	r, ok := LookupRegistration(config.Name)
+	if !ok {
+		return nil, 0, errors.Errorf("unsupported experiment: [%s]", config.Name)
+	}
+	params, err := r.normalize(config.Parameters)
+	if err != nil {
+		return nil, 0, err
+	}
+	evaluator, err := r.NewEvaluator(EvaluatorConfig{Name: config.Name, Parameters: params}, outDir)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to create evaluator of experiment [%s]", config.Name)
+	}
+	return evaluator, r.MaxFitnessScore, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
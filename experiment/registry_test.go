@@ -0,0 +1,109 @@
+package experiment
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// registryTestEvaluator records the configuration it was created with
+type registryTestEvaluator struct {
+	GenerationEvaluator
+	config EvaluatorConfig
+	outDir string
+}
+
+func registryTestRegistration(name string) Registration {
+	return Registration{
+		Name:            name,
+		Description:     "The test experiment",
+		MaxFitnessScore: 10,
+		Parameters: []ParameterSchema{
+			{Name: "steps", Type: ParameterTypeInt, Default: 100, Description: "The number of steps"},
+			{Name: "mode", Type: ParameterTypeString, Default: "fast", Description: "The mode", Allowed: []string{"fast", "slow"}},
+			{Name: "random", Type: ParameterTypeBool, Default: false, Description: "The random flag"},
+		},
+		NewEvaluator: func(config EvaluatorConfig, outDir string) (GenerationEvaluator, error) {
+			return &registryTestEvaluator{config: config, outDir: outDir}, nil
+		},
+	}
+}
+
+func TestRegister(t *testing.T) {
+	reg := registryTestRegistration("test_register")
+	require.NoError(t, Register(reg))
+	assert.Error(t, Register(reg), "duplicate registration expected")
+	assert.Panics(t, func() {
+		MustRegister(reg)
+	})
+
+	found, ok := LookupRegistration("test_register")
+	require.True(t, ok)
+	assert.Equal(t, reg.Description, found.Description)
+	_, ok = LookupRegistration("unknown")
+	assert.False(t, ok)
+
+	names := make([]string, 0)
+	for _, r := range Registered() {
+		names = append(names, r.Name)
+	}
+	assert.Contains(t, names, "test_register")
+	assert.IsIncreasing(t, names)
+}
+
+func TestRegistration_Validate(t *testing.T) {
+	reg := registryTestRegistration("")
+	assert.Error(t, reg.Validate(), "name expected")
+
+	reg = registryTestRegistration("test")
+	reg.NewEvaluator = nil
+	assert.Error(t, reg.Validate(), "factory expected")
+
+	reg = registryTestRegistration("test")
+	reg.Parameters[0].Default = "many"
+	assert.Error(t, reg.Validate(), "default of wrong type")
+
+	reg = registryTestRegistration("test")
+	reg.Parameters[1].Default = "medium"
+	assert.Error(t, reg.Validate(), "default not allowed")
+
+	reg = registryTestRegistration("test")
+	reg.Parameters = append(reg.Parameters, reg.Parameters[0])
+	assert.Error(t, reg.Validate(), "duplicate parameter")
+}
+
+func TestNewRegisteredEvaluator(t *testing.T) {
+	require.NoError(t, Register(registryTestRegistration("test_new_evaluator")))
+
+	evaluator, maxFitness, err := NewRegisteredEvaluator(EvaluatorConfig{
+		Name: "test_new_evaluator", Parameters: map[string]interface{}{"steps": "20", "mode": "slow"},
+	}, "out")
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, maxFitness)
+	testEvaluator := evaluator.(*registryTestEvaluator)
+	assert.Equal(t, "out", testEvaluator.outDir)
+	assert.Equal(t, map[string]interface{}{"steps": 20, "mode": "slow", "random": false}, testEvaluator.config.Parameters)
+
+	testCases := map[string]EvaluatorConfig{
+		"unknown experiment": {Name: "unknown"},
+		"unknown parameter":  {Name: "test_new_evaluator", Parameters: map[string]interface{}{"speed": 1}},
+		"wrong type":         {Name: "test_new_evaluator", Parameters: map[string]interface{}{"random": "maybe"}},
+		"not allowed":        {Name: "test_new_evaluator", Parameters: map[string]interface{}{"mode": "medium"}},
+	}
+	for name, config := range testCases {
+		_, _, err = NewRegisteredEvaluator(config, "out")
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRegistration_Describe(t *testing.T) {
+	reg := registryTestRegistration("test_describe")
+	var buf bytes.Buffer
+	require.NoError(t, reg.Describe(&buf))
+	expected := "test_describe\n\tThe test experiment\n\tMax fitness score: 10\n\tParameters:\n" +
+		"\t\tsteps (int, default: 100) - The number of steps\n" +
+		"\t\tmode (string, default: fast) - The mode [fast, slow]\n" +
+		"\t\trandom (bool, default: false) - The random flag\n"
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,125 @@
+package experiment
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The standard reasons of the trial termination
+const (
+	// TrialStopReasonSolved The trial stopped because the solution was found
+	TrialStopReasonSolved = "solved"
+	// TrialStopReasonMaxGenerations The trial stopped because the maximal number of generations was evaluated
+	TrialStopReasonMaxGenerations = "maximal number of generations reached"
+)
+
+// TerminationCriterion the interface describing condition to stop the trial before the maximal number of generations
+// evaluated. The criteria can be composed using And and Or functions.
+type TerminationCriterion interface {
+	// ShouldTerminate Invoked after each generation evaluation with the trial holding all evaluated generations
+	// including the current one. Returns true and human-readable reason if trial should be stopped. Note that the
+	// fitness of the best organisms is adjusted by the epoch of population, thus Generation.BestFitness should be used
+	// instead.
+	ShouldTerminate(trial *Trial, epoch *Generation) (bool, string)
+}
+
+// TerminationCriterionFunc the function adapter to the TerminationCriterion interface
+type TerminationCriterionFunc func(trial *Trial, epoch *Generation) (bool, string)
+
+// ShouldTerminate Implements TerminationCriterion
+func (f TerminationCriterionFunc) ShouldTerminate(trial *Trial, epoch *Generation) (bool, string) {
+	return f(trial, epoch)
+}
+
+// And Creates criterion which terminates the trial only when all provided criteria are met.
+func And(criteria ...TerminationCriterion) TerminationCriterion {
+	return TerminationCriterionFunc(func(trial *Trial, epoch *Generation) (bool, string) {
+		if len(criteria) == 0 {
+			return false, ""
+		}
+		reasons := make([]string, len(criteria))
+		for i, c := range criteria {
+			stop, reason := c.ShouldTerminate(trial, epoch)
+			if !stop {
+				return false, ""
+			}
+			reasons[i] = reason
+		}
+		return true, strings.Join(reasons, " and ")
+	})
+}
+
+// Or Creates criterion which terminates the trial when any of provided criteria is met. The reason of the first met
+// criterion is returned.
+func Or(criteria ...TerminationCriterion) TerminationCriterion {
+	return TerminationCriterionFunc(func(trial *Trial, epoch *Generation) (bool, string) {
+		for _, c := range criteria {
+			if stop, reason := c.ShouldTerminate(trial, epoch); stop {
+				return true, reason
+			}
+		}
+		return false, ""
+	})
+}
+
+// FitnessThreshold Creates criterion which terminates the trial when fitness of the generation champion reaches
+// provided threshold.
+func FitnessThreshold(threshold float64) TerminationCriterion {
+	return TerminationCriterionFunc(func(_ *Trial, epoch *Generation) (bool, string) {
+		if epoch.Best != nil && epoch.BestFitness >= threshold {
+			return true, fmt.Sprintf("fitness threshold %f reached", threshold)
+		}
+		return false, ""
+	})
+}
+
+// Stagnation Creates criterion which terminates the trial when the best fitness was not improved during provided
+// number of the most recent generations.
+func Stagnation(generations int) TerminationCriterion {
+	return TerminationCriterionFunc(func(trial *Trial, _ *Generation) (bool, string) {
+		bestIndex, bestFitness := -1, 0.0
+		for i, g := range trial.Generations {
+			if g.Best != nil && (bestIndex < 0 || g.BestFitness > bestFitness) {
+				bestIndex, bestFitness = i, g.BestFitness
+			}
+		}
+		if bestIndex >= 0 && len(trial.Generations)-1-bestIndex >= generations {
+			return true, fmt.Sprintf("no fitness improvement in %d generations", generations)
+		}
+		return false, ""
+	})
+}
+
+// WallClockBudget Creates criterion which terminates the trial when the time elapsed since trial start exceeds
+// provided budget, see Trial.Duration.
+func WallClockBudget(budget time.Duration) TerminationCriterion {
+	return TerminationCriterionFunc(func(trial *Trial, _ *Generation) (bool, string) {
+		if trial.Duration >= budget {
+			return true, fmt.Sprintf("wall-clock budget %s exhausted", budget)
+		}
+		return false, ""
+	})
+}
+
+// EvaluationsBudget Creates criterion which terminates the trial when the total number of organisms evaluations
+// reaches provided budget.
+func EvaluationsBudget(budget int) TerminationCriterion {
+	return TerminationCriterionFunc(func(trial *Trial, _ *Generation) (bool, string) {
+		if trial.Evaluations() >= budget {
+			return true, fmt.Sprintf("evaluations budget %d exhausted", budget)
+		}
+		return false, ""
+	})
+}
+
+// TargetComplexity Creates criterion which terminates the trial when the phenotype complexity of the generation
+// champion reaches provided target.
+func TargetComplexity(complexity int) TerminationCriterion {
+	return TerminationCriterionFunc(func(_ *Trial, epoch *Generation) (bool, string) {
+		if epoch.Best != nil && epoch.BestComplexity >= complexity {
+			return true, fmt.Sprintf("target complexity %d reached", complexity)
+		}
+		return false, ""
+	})
+}
@@ -0,0 +1,129 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"testing"
+	"time"
+)
+
+func buildTerminationTestTrial(fitness []float64) *Trial {
+	trial := Trial{Id: 1}
+	for i, f := range fitness {
+		gen := buildTestGeneration(i+1, f)
+		gen.Duration = time.Second
+		gen.Evaluations = 100
+		trial.Generations = append(trial.Generations, *gen)
+		trial.Duration += gen.Duration
+	}
+	return &trial
+}
+
+func TestTerminationCriteria(t *testing.T) {
+	trial := buildTerminationTestTrial([]float64{1.0, 3.0, 2.0, 3.0, 2.5})
+	epoch := &trial.Generations[len(trial.Generations)-1]
+
+	testCases := []struct {
+		name      string
+		criterion TerminationCriterion
+		stop      bool
+		reason    string
+	}{
+		{"fitness reached", FitnessThreshold(2.5), true, "fitness threshold 2.500000 reached"},
+		{"fitness not reached", FitnessThreshold(3.0), false, ""},
+		{"stagnation", Stagnation(3), true, "no fitness improvement in 3 generations"},
+		{"no stagnation", Stagnation(4), false, ""},
+		{"wall-clock exhausted", WallClockBudget(5 * time.Second), true, "wall-clock budget 5s exhausted"},
+		{"wall-clock left", WallClockBudget(time.Minute), false, ""},
+		{"evaluations exhausted", EvaluationsBudget(500), true, "evaluations budget 500 exhausted"},
+		{"evaluations left", EvaluationsBudget(501), false, ""},
+		{"and", And(FitnessThreshold(2.0), EvaluationsBudget(100)), true,
+			"fitness threshold 2.000000 reached and evaluations budget 100 exhausted"},
+		{"and not all", And(FitnessThreshold(2.0), EvaluationsBudget(1000)), false, ""},
+		{"and empty", And(), false, ""},
+		{"or", Or(FitnessThreshold(10.0), Stagnation(3), EvaluationsBudget(100)), true,
+			"no fitness improvement in 3 generations"},
+		{"or none", Or(FitnessThreshold(10.0), Stagnation(10)), false, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stop, reason := tc.criterion.ShouldTerminate(trial, epoch)
+			assert.Equal(t, tc.stop, stop)
+			assert.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestTargetComplexity(t *testing.T) {
+	org, err := genetics.NewOrganism(1.0, buildTestGenome(1), 1)
+	require.NoError(t, err, "failed to create organism")
+	epoch := &Generation{Best: org}
+	epoch.recordBest()
+	complexity := org.Phenotype.Complexity()
+
+	stop, _ := TargetComplexity(complexity).ShouldTerminate(&Trial{}, epoch)
+	assert.True(t, stop)
+	stop, _ = TargetComplexity(complexity+1).ShouldTerminate(&Trial{}, epoch)
+	assert.False(t, stop)
+}
+
+func TestExperiment_Execute_TerminationCriterion(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 2
+
+	evaluator := NewParallelGenerationEvaluator(newNeverSolvingEvaluator(), "", "test", 2)
+	exp := Experiment{
+		Id:                   1,
+		TerminationCriterion: EvaluationsBudget(opts.PopSize * 3),
+	}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	require.Len(t, exp.Trials, opts.NumRuns)
+	for _, trial := range exp.Trials {
+		assert.Len(t, trial.Generations, 3)
+		assert.Equal(t, opts.PopSize*3, trial.Evaluations())
+		assert.Equal(t, fmt.Sprintf("evaluations budget %d exhausted", opts.PopSize*3), trial.StopReason)
+	}
+}
+
+// constantFitnessEvaluator assigns the same fitness to all organisms
+type constantFitnessEvaluator float64
+
+func (e constantFitnessEvaluator) OrganismEvaluate(_ *genetics.Organism, _ *neat.Options) (float64, float64, bool, error) {
+	return float64(e), 0, false, nil
+}
+
+func TestExperiment_Execute_TerminationCriterion_rawFitness(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	opts.NumRuns = 1
+	opts.NumGenerations = 10
+
+	// the fitness adjusted by the epoch of population is much less than the threshold
+	evaluator := NewParallelGenerationEvaluator(constantFitnessEvaluator(15.95), "", "test", 2)
+	exp := Experiment{
+		Id:                   1,
+		TerminationCriterion: Or(FitnessThreshold(15.9), Stagnation(3)),
+	}
+	err := exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	trial := exp.Trials[0]
+	require.Len(t, trial.Generations, 1)
+	assert.Equal(t, "fitness threshold 15.900000 reached", trial.StopReason)
+	generation := trial.Generations[0]
+	assert.Equal(t, 15.95, generation.BestFitness)
+	assert.True(t, generation.Best.Fitness < 15.9, "fitness of the best organism expected to be adjusted")
+	assert.True(t, generation.BestComplexity > 0)
+
+	// the stagnation is detected by the raw fitness of the champions
+	exp = Experiment{Id: 1, TerminationCriterion: Stagnation(3)}
+	err = exp.Execute(neat.NewContext(context.Background(), opts), startGenome, evaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+	assert.Len(t, exp.Trials[0].Generations, 4)
+	assert.Equal(t, "no fitness improvement in 3 generations", exp.Trials[0].StopReason)
+}
@@ -16,8 +16,13 @@ type Trial struct {
 	// The winner generation
 	WinnerGeneration *Generation
 
-	// The elapsed time between trial start and finish
+	// The elapsed time between trial start and finish. While trial is running, it holds the time elapsed between trial
+	// start and the end of the last evaluated generation.
 	Duration time.Duration
+	// The reason why this trial was stopped
+	StopReason string
+	// The seed of the random numbers generator used for this trial or zero if generator was not seeded per trial
+	Seed int64
 }
 
 // AvgEpochDuration Calculates average duration of evaluations among all generations of organism populations in this trial
@@ -29,6 +34,24 @@ func (t *Trial) AvgEpochDuration() time.Duration {
 	return total / time.Duration(len(t.Generations))
 }
 
+// GenerationsDuration Returns the total duration of all generations evaluated in this trial
+func (t *Trial) GenerationsDuration() time.Duration {
+	total := time.Duration(0)
+	for _, g := range t.Generations {
+		total += g.Duration
+	}
+	return total
+}
+
+// Evaluations Returns the total number of organisms evaluations performed in this trial
+func (t *Trial) Evaluations() int {
+	total := 0
+	for _, g := range t.Generations {
+		total += g.Evaluations
+	}
+	return total
+}
+
 // RecentEpochEvalTime is to get time of the epoch executed most recently within this trial
 func (t *Trial) RecentEpochEvalTime() time.Time {
 	var u time.Time
@@ -74,7 +97,7 @@ func (t *Trial) Solved() bool {
 func (t *Trial) BestFitness() Floats {
 	var x Floats = make([]float64, len(t.Generations))
 	for i, e := range t.Generations {
-		x[i] = e.Best.Fitness
+		x[i] = e.BestFitness
 
 	}
 	return x
@@ -98,6 +121,18 @@ func (t *Trial) BestComplexity() Floats {
 	return x
 }
 
+// BestModularity returns the Newman modularity score of the best organism's phenotype for each epoch in this trial or
+// zero if structural analysis is missing
+func (t *Trial) BestModularity() Floats {
+	var x Floats = make([]float64, len(t.Generations))
+	for i, e := range t.Generations {
+		if e.BestStructure != nil {
+			x[i] = e.BestStructure.Modularity
+		}
+	}
+	return x
+}
+
 // Diversity returns number of species for each epoch
 func (t *Trial) Diversity() Floats {
 	var x Floats = make([]float64, len(t.Generations))
@@ -146,6 +181,12 @@ func (t *Trial) Encode(enc *gob.Encoder) error {
 	if err := enc.Encode(t.Id); err != nil {
 		return err
 	}
+	if err := enc.Encode(t.StopReason); err != nil {
+		return err
+	}
+	if err := enc.Encode(t.Seed); err != nil {
+		return err
+	}
 	if err := enc.Encode(len(t.Generations)); err != nil {
 		return err
 	}
@@ -159,9 +200,22 @@ func (t *Trial) Encode(enc *gob.Encoder) error {
 
 // Decode Decodes trial data
 func (t *Trial) Decode(dec *gob.Decoder) error {
+	return t.decode(dec, formatVersion)
+}
+
+// decode Decodes trial data encoded with specified format version
+func (t *Trial) decode(dec *gob.Decoder, version int) error {
 	if err := dec.Decode(&t.Id); err != nil {
 		return err
 	}
+	if version >= formatVersion1 {
+		if err := dec.Decode(&t.StopReason); err != nil {
+			return err
+		}
+		if err := dec.Decode(&t.Seed); err != nil {
+			return err
+		}
+	}
 	var ngen int
 	if err := dec.Decode(&ngen); err != nil {
 		return err
@@ -169,7 +223,7 @@ func (t *Trial) Decode(dec *gob.Decoder) error {
 	t.Generations = make([]Generation, ngen)
 	for i := 0; i < ngen; i++ {
 		gen := Generation{}
-		if err := gen.Decode(dec); err != nil {
+		if err := gen.decode(dec, version); err != nil {
 			return err
 		}
 		t.Generations[i] = gen
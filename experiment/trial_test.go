@@ -32,7 +32,7 @@ func TestTrial_Encode_Decode(t *testing.T) {
 }
 
 func buildTestTrial(id, numGenerations int) *Trial {
-	trial := Trial{Id: id, Generations: make([]Generation, numGenerations)}
+	trial := Trial{Id: id, Generations: make([]Generation, numGenerations), StopReason: TrialStopReasonSolved, Seed: 42}
 	for i := 0; i < numGenerations; i++ {
 		trial.Generations[i] = *buildTestGeneration(i+1, float64(i+1)*math.E)
 	}
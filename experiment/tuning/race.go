@@ -0,0 +1,644 @@
+package tuning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"gonum.org/v1/gonum/mathext"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const (
+	// BestOptionsFileName The name of the file in the race output directory to write the best options in the plain
+	// text format
+	BestOptionsFileName = "best.neat"
+	// BestYAMLOptionsFileName The name of the file in the race output directory to write the best options as YAML
+	BestYAMLOptionsFileName = "best.yml"
+	// RaceResultsFileName The name of the file in the race output directory to write the results of all candidates
+	RaceResultsFileName = "race.json"
+)
+
+// RaceSpec the specification of the racing-based automatic configuration of NEAT options
+type RaceSpec struct {
+	// The name of the race
+	Name string `yaml:"name"`
+	// The seed of the random numbers generator used for sampling of candidates. If set, the trials with the same index
+	// are executed with the same seed for all candidates.
+	Seed int64 `yaml:"seed,omitempty"`
+	// The number of candidates raced in each iteration
+	Candidates int `yaml:"candidates"`
+	// The maximal number of the best candidates surviving the race to take part in the next iteration. If zero, the
+	// half of candidates is used.
+	Elites int `yaml:"elites,omitempty"`
+	// The number of trials executed by each candidate before the first statistical test. If zero, five trials used.
+	FirstTest int `yaml:"first_test,omitempty"`
+	// The maximal number of trials executed by each candidate in one race. If zero, the doubled FirstTest is used.
+	MaxTrials int `yaml:"max_trials,omitempty"`
+	// The significance level of the statistical tests. If zero, 0.05 is used.
+	Alpha float64 `yaml:"alpha,omitempty"`
+	// The maximal total number of trials executed. Zero means unlimited.
+	BudgetTrials int `yaml:"budget_trials,omitempty"`
+	// The maximal total number of organism evaluations. Zero means unlimited.
+	BudgetEvaluations int `yaml:"budget_evaluations,omitempty"`
+	// The maximal number of trials executed in parallel. The trials are executed one by one if Seed is set, because
+	// they reseed the shared random numbers generator.
+	Workers int `yaml:"workers,omitempty"`
+	// The parameters to be tuned
+	Parameters []Parameter `yaml:"parameters"`
+}
+
+// Validate is to check that specification is valid
+func (s *RaceSpec) Validate() error {
+	if s.Candidates < 2 {
+		return errors.New("at least two candidates must be raced")
+	}
+	if s.Elites < 0 || s.Elites >= s.Candidates {
+		return errors.New("the number of elites must be less than the number of candidates")
+	}
+	if s.FirstTest < 0 || s.MaxTrials < 0 || (s.MaxTrials > 0 && s.MaxTrials < s.FirstTest) {
+		return errors.New("the maximal number of trials must not be less than the number of trials before the first test")
+	}
+	if s.Alpha < 0 || s.Alpha >= 1 {
+		return errors.Errorf("invalid significance level: %f", s.Alpha)
+	}
+	if s.BudgetTrials <= 0 && s.BudgetEvaluations <= 0 {
+		return errors.New("either budget of trials or budget of evaluations must be set")
+	}
+	if len(s.Parameters) == 0 {
+		return errors.New("no parameters to tune")
+	}
+	names := make(map[string]bool)
+	for i := range s.Parameters {
+		if err := s.Parameters[i].Validate(SamplingLatinHypercube); err != nil {
+			return err
+		}
+		if names[s.Parameters[i].Name] {
+			return errors.Errorf("duplicate parameter: [%s]", s.Parameters[i].Name)
+		}
+		names[s.Parameters[i].Name] = true
+	}
+	return nil
+}
+
+// withDefaults Returns the copy of specification with default values set for omitted fields
+func (s RaceSpec) withDefaults() RaceSpec {
+	if s.Elites == 0 {
+		s.Elites = s.Candidates / 2
+	}
+	if s.FirstTest == 0 {
+		s.FirstTest = 5
+	}
+	if s.MaxTrials == 0 {
+		s.MaxTrials = 2 * s.FirstTest
+	}
+	if s.Alpha == 0 {
+		s.Alpha = 0.05
+	}
+	if s.Workers < 1 {
+		s.Workers = 1
+	}
+	return s
+}
+
+// LoadRaceSpec Loads the race specification encoded as YAML
+func LoadRaceSpec(r io.Reader) (*RaceSpec, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var spec RaceSpec
+	if err = yaml.Unmarshal(content, &spec); err != nil {
+		return nil, errors.Wrap(err, "failed to decode race specification from YAML")
+	}
+	if err = spec.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid race specification")
+	}
+	return &spec, nil
+}
+
+// TrialScore the outcome of one trial executed by the candidate
+type TrialScore struct {
+	// Whether the trial was solved
+	Solved bool `json:"solved"`
+	// The number of evaluations to solve if solved, or the total number of evaluations otherwise
+	Evaluations int `json:"evaluations"`
+	// The best fitness score of the trial
+	Fitness float64 `json:"fitness"`
+}
+
+// better Returns 1 if this score is better than other, -1 if worse, and 0 if equal. The solved trial is better than
+// unsolved; solved trials are compared by the number of evaluations, and unsolved by the best fitness.
+func (s TrialScore) better(other TrialScore) int {
+	switch {
+	case s.Solved != other.Solved:
+		if s.Solved {
+			return 1
+		}
+		return -1
+	case s.Solved && s.Evaluations != other.Evaluations:
+		if s.Evaluations < other.Evaluations {
+			return 1
+		}
+		return -1
+	case !s.Solved && s.Fitness != other.Fitness:
+		if s.Fitness > other.Fitness {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// Candidate the candidate configuration of the race
+type Candidate struct {
+	Configuration
+	// The index of iteration where candidate was sampled
+	Iteration int `json:"iteration"`
+	// The scores of trials executed by the candidate by trial index
+	Scores map[int]TrialScore `json:"scores"`
+	// The mean rank of the candidate among survivors of its last race, lower is better
+	MeanRank float64 `json:"mean_rank"`
+	// Whether the candidate was eliminated in its last race
+	Eliminated bool `json:"eliminated"`
+
+	options *neat.Options
+}
+
+// SuccessRate Returns the fraction of solved trials executed by the candidate
+func (c *Candidate) SuccessRate() float64 {
+	if len(c.Scores) == 0 {
+		return 0
+	}
+	solved := 0
+	for _, s := range c.Scores {
+		if s.Solved {
+			solved++
+		}
+	}
+	return float64(solved) / float64(len(c.Scores))
+}
+
+// RaceResult the results of the race
+type RaceResult struct {
+	// The best candidate found
+	Best *Candidate `json:"best"`
+	// The NEAT options of the best candidate
+	Options *neat.Options `json:"-"`
+	// All raced candidates
+	Candidates []*Candidate `json:"candidates"`
+	// The number of race iterations
+	Iterations int `json:"iterations"`
+	// The total number of executed trials
+	Trials int `json:"trials"`
+	// The total number of organism evaluations
+	Evaluations int `json:"evaluations"`
+}
+
+// Race the automatic configuration of NEAT options using iterated racing (F-race). In each iteration the candidates
+// execute trials one by one, and after FirstTest trials the candidates which are statistically worse than the best
+// one, according to the Friedman test and its post-hoc pairwise comparisons, are eliminated. The survivors become elites
+// of the next iteration, which samples new candidates around them with shrinking spread. The race stops when the budget
+// of trials or evaluations is exhausted, and the best elite is returned. If OutDir is set, the best options are written
+// into it in the plain text and YAML formats along with results of all candidates.
+type Race struct {
+	// The race specification
+	Spec *RaceSpec
+	// The base NEAT options to be modified by each candidate
+	Options *neat.Options
+	// The start genome of the experiment
+	StartGenome *genetics.Genome
+	// The factory of the generation evaluators, which receives the candidate configuration
+	NewEvaluator EvaluatorFactory
+	// The output directory, optional
+	OutDir string
+
+	spec        RaceSpec
+	rng         *rand.Rand
+	trials      int
+	evaluations int
+	mutex       sync.Mutex
+}
+
+// Run Executes the race and returns its results. The execution can be interrupted by canceling provided context, in
+// which case the best candidate found so far is returned along with the context error.
+func (r *Race) Run(ctx context.Context) (*RaceResult, error) {
+	if err := r.Spec.Validate(); err != nil {
+		return nil, err
+	}
+	r.spec = r.Spec.withDefaults()
+	r.rng = rand.New(rand.NewSource(r.spec.Seed))
+	r.trials, r.evaluations = 0, 0
+
+	result := &RaceResult{}
+	var elites []*Candidate
+	var runErr error
+	for iteration := 0; ; iteration++ {
+		var configs []Configuration
+		if iteration == 0 {
+			configs = SampleConfigurations(&Spec{
+				Sampling: SamplingLatinHypercube, Samples: r.spec.Candidates, Seed: r.spec.Seed, Parameters: r.spec.Parameters,
+			})
+		} else {
+			configs = r.sampleAround(elites, r.spec.Candidates-len(elites), iteration)
+		}
+		candidates := make([]*Candidate, 0, r.spec.Candidates)
+		candidates = append(candidates, elites...)
+		for _, c := range configs {
+			c.Id = len(result.Candidates)
+			opts, err := ApplyParameters(r.Options, c.Parameters)
+			if err != nil {
+				return nil, errors.Wrapf(err, "candidate %d", c.Id)
+			}
+			candidate := &Candidate{Configuration: c, Iteration: iteration, Scores: make(map[int]TrialScore), options: opts}
+			candidates = append(candidates, candidate)
+			result.Candidates = append(result.Candidates, candidate)
+		}
+		neat.InfoLog(fmt.Sprintf("Race [%s] iteration %d started with %d candidates", r.spec.Name, iteration, len(candidates)))
+
+		survivors, exhausted, err := r.race(ctx, candidates)
+		if len(survivors) > 0 {
+			elites = survivors
+			if len(elites) > r.spec.Elites {
+				elites = elites[:r.spec.Elites]
+			}
+		}
+		result.Iterations = iteration + 1
+		if err != nil {
+			runErr = err
+			break
+		}
+		if exhausted {
+			break
+		}
+	}
+	if len(elites) == 0 || len(elites[0].Scores) == 0 {
+		if runErr == nil {
+			runErr = errors.New("the budget is too small to execute any trial")
+		}
+		return nil, runErr
+	}
+
+	result.Best = elites[0]
+	result.Options = elites[0].options
+	result.Trials, result.Evaluations = r.trials, r.evaluations
+	neat.InfoLog(fmt.Sprintf("Race [%s] finished, the best candidate %d: %v, success rate: %f",
+		r.spec.Name, result.Best.Id, result.Best.Parameters, result.Best.SuccessRate()))
+	if len(r.OutDir) > 0 {
+		if err := r.writeResults(result); err != nil {
+			return result, err
+		}
+	}
+	return result, runErr
+}
+
+// race Executes one race among candidates and returns the survivors ordered by mean rank and the flag indicating
+// whether the budget was exhausted
+func (r *Race) race(ctx context.Context, candidates []*Candidate) ([]*Candidate, bool, error) {
+	alive := candidates
+	for _, c := range alive {
+		c.Eliminated = false
+	}
+	exhausted := false
+	trials := 0
+	for trial := 0; trial < r.spec.MaxTrials && len(alive) > 1; trial++ {
+		var pending []*Candidate
+		for _, c := range alive {
+			if _, ok := c.Scores[trial]; !ok {
+				pending = append(pending, c)
+			}
+		}
+		if !r.affordable(len(pending)) {
+			exhausted = true
+			break
+		}
+		if err := r.executeTrials(ctx, pending, trial); err != nil {
+			return rankCandidates(alive, trials), false, err
+		}
+		trials = trial + 1
+		if trials >= r.spec.FirstTest {
+			alive = r.eliminate(alive, trials)
+		}
+	}
+	return rankCandidates(alive, trials), exhausted || !r.affordable(1), nil
+}
+
+// affordable Checks whether the given number of trials can be executed within budget
+func (r *Race) affordable(trials int) bool {
+	if r.spec.BudgetTrials > 0 && r.trials+trials > r.spec.BudgetTrials {
+		return false
+	}
+	if r.spec.BudgetEvaluations > 0 && r.evaluations >= r.spec.BudgetEvaluations {
+		return false
+	}
+	return true
+}
+
+// executeTrials Executes the trial with given index for each candidate using the bounded pool of workers
+func (r *Race) executeTrials(ctx context.Context, candidates []*Candidate, trial int) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan *Candidate)
+	errs := make(chan error, len(candidates))
+	var wg sync.WaitGroup
+	workers := parallelWorkers(r.spec.Workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				score, evaluations, err := r.executeTrial(ctx, c, trial)
+				if err != nil {
+					errs <- errors.Wrapf(err, "candidate %d, trial %d", c.Id, trial)
+					cancel()
+					continue
+				}
+				r.mutex.Lock()
+				c.Scores[trial] = score
+				r.trials++
+				r.evaluations += evaluations
+				r.mutex.Unlock()
+			}
+		}()
+	}
+	for _, c := range candidates {
+		jobs <- c
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+	if err := <-errs; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// executeTrial Executes one trial of the experiment with options of the candidate
+func (r *Race) executeTrial(ctx context.Context, c *Candidate, trial int) (TrialScore, int, error) {
+	outDir := ""
+	if len(r.OutDir) > 0 {
+		outDir = filepath.Join(r.OutDir, fmt.Sprintf("candidate_%d", c.Id), fmt.Sprintf("trial_%d", trial))
+		if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+			return TrialScore{}, 0, err
+		}
+	}
+	evaluator, err := r.NewEvaluator(c.Configuration, outDir)
+	if err != nil {
+		return TrialScore{}, 0, err
+	}
+	opts := *c.options
+	opts.NumRuns = 1
+	exp := experiment.Experiment{
+		Id:       c.Id,
+		Name:     fmt.Sprintf("%s candidate %d", r.spec.Name, c.Id),
+		RandSeed: r.trialSeed(trial),
+	}
+	if err = exp.Execute(neat.NewContext(ctx, &opts), r.StartGenome, evaluator, nil); err != nil {
+		return TrialScore{}, 0, err
+	}
+	t := &exp.Trials[0]
+	score := TrialScore{Solved: t.Solved(), Evaluations: t.Evaluations(), Fitness: finiteOrZero(t.BestFitness().Max())}
+	if score.Solved {
+		_, _, score.Evaluations, _ = t.Winner()
+	}
+	return score, t.Evaluations(), nil
+}
+
+// trialSeed Returns the seed of the trial with given index, which is the same for all candidates to compare them on
+// common random numbers. Returns zero if Seed is not set.
+func (r *Race) trialSeed(trial int) int64 {
+	if r.spec.Seed == 0 {
+		return 0
+	}
+	return r.spec.Seed + int64(trial) + 1
+}
+
+// eliminate Applies the Friedman test to the scores of the first trials of alive candidates and, if the difference
+// is significant, eliminates candidates which are worse than the best one according to the post-hoc test. Returns
+// the survivors.
+func (r *Race) eliminate(alive []*Candidate, trials int) []*Candidate {
+	ranks := rankScores(alive, trials)
+	k, b := float64(len(alive)), float64(trials)
+	rankSums := make([]float64, len(alive))
+	sumSquares := 0.0
+	for _, block := range ranks {
+		for j, rank := range block {
+			rankSums[j] += rank
+			sumSquares += rank * rank
+		}
+	}
+	sumRankSquares := 0.0
+	for _, s := range rankSums {
+		sumRankSquares += s * s
+	}
+	c := b * k * (k + 1) * (k + 1) / 4
+	if sumSquares-c <= 0 {
+		// all scores are tied
+		return alive
+	}
+	statistic := (k - 1) * (sumRankSquares - b*b*k*(k+1)*(k+1)/4) / (sumSquares - c)
+	if friedmanPValue(statistic, k) >= r.spec.Alpha {
+		return alive
+	}
+
+	// Conover post-hoc comparisons with the best candidate
+	best := rankSums[0]
+	for _, s := range rankSums {
+		best = math.Min(best, s)
+	}
+	df := (b - 1) * (k - 1)
+	critical := studentTCritical(r.spec.Alpha, df) *
+		math.Sqrt(2*b*(sumSquares-sumRankSquares/b)/df)
+	survivors := make([]*Candidate, 0, len(alive))
+	for j, c := range alive {
+		if rankSums[j]-best > critical {
+			c.Eliminated = true
+			neat.DebugLog(fmt.Sprintf("Race [%s] candidate %d eliminated after %d trials", r.spec.Name, c.Id, trials))
+		} else {
+			survivors = append(survivors, c)
+		}
+	}
+	return survivors
+}
+
+// rankScores Returns the ranks of candidates in each of the first trials, with the best candidate getting the rank one
+// and ties getting the average rank
+func rankScores(candidates []*Candidate, trials int) [][]float64 {
+	ranks := make([][]float64, trials)
+	indices := make([]int, len(candidates))
+	for t := 0; t < trials; t++ {
+		for i := range indices {
+			indices[i] = i
+		}
+		sort.SliceStable(indices, func(i, j int) bool {
+			return candidates[indices[i]].Scores[t].better(candidates[indices[j]].Scores[t]) > 0
+		})
+		ranks[t] = make([]float64, len(candidates))
+		for i := 0; i < len(indices); {
+			j := i + 1
+			for j < len(indices) && candidates[indices[j]].Scores[t].better(candidates[indices[i]].Scores[t]) == 0 {
+				j++
+			}
+			for l := i; l < j; l++ {
+				ranks[t][indices[l]] = float64(i+j+1) / 2
+			}
+			i = j
+		}
+	}
+	return ranks
+}
+
+// rankCandidates Sets the mean ranks of candidates in the first trials and returns candidates ordered by mean rank. If
+// no trials executed, returns only candidates having scores of the previous races.
+func rankCandidates(candidates []*Candidate, trials int) []*Candidate {
+	if trials == 0 {
+		// no trials executed in this race, keep elites of the previous one in order
+		ranked := make([]*Candidate, 0, len(candidates))
+		for _, c := range candidates {
+			if len(c.Scores) > 0 {
+				ranked = append(ranked, c)
+			}
+		}
+		return ranked
+	}
+	ranks := rankScores(candidates, trials)
+	for j, c := range candidates {
+		c.MeanRank = 0
+		for _, block := range ranks {
+			c.MeanRank += block[j]
+		}
+		c.MeanRank /= float64(trials)
+	}
+	ranked := make([]*Candidate, len(candidates))
+	copy(ranked, candidates)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].MeanRank < ranked[j].MeanRank
+	})
+	return ranked
+}
+
+// friedmanPValue Returns the p-value of the Friedman statistic, which follows the chi-squared distribution with k-1
+// degrees of freedom
+func friedmanPValue(statistic, k float64) float64 {
+	return mathext.GammaIncRegComp((k-1)/2, statistic/2)
+}
+
+// studentTCritical Returns the critical value of the two-sided Student's t-test with given significance level and
+// degrees of freedom
+func studentTCritical(alpha, df float64) float64 {
+	x := mathext.InvRegIncBeta(df/2, 0.5, alpha)
+	return math.Sqrt(df * (1 - x) / x)
+}
+
+// sampleAround Samples new configurations around the elites. The parent elite is selected with probability
+// proportional to its rank, and the values of numeric parameters are perturbed with the normal noise which spread
+// halves every iteration. The categorical values are inherited or resampled with the same shrinking probability.
+func (r *Race) sampleAround(elites []*Candidate, count, iteration int) []Configuration {
+	weights := make([]float64, len(elites))
+	for i := range weights {
+		weights[i] = float64(len(elites) - i)
+	}
+	spread := math.Pow(0.5, float64(iteration))
+	configs := make([]Configuration, count)
+	for i := range configs {
+		parent := elites[rouletteIndex(weights, r.rng)]
+		configs[i].Parameters = make(map[string]interface{}, len(r.spec.Parameters))
+		for _, p := range r.spec.Parameters {
+			value := parent.Parameters[p.Name]
+			if len(p.Values) > 0 {
+				if r.rng.Float64() < spread/2 {
+					value = p.Values[r.rng.Intn(len(p.Values))]
+				}
+			} else {
+				value = p.perturb(toFloat(value), spread/2, r.rng)
+			}
+			configs[i].Parameters[p.Name] = value
+		}
+	}
+	return configs
+}
+
+// perturb Returns the value of the numeric parameter perturbed by the normal noise with standard deviation of given
+// fraction of the parameter range, clipped to the range
+func (p *Parameter) perturb(value, fraction float64, rng *rand.Rand) float64 {
+	min, max := p.Min, p.Max
+	if p.Log {
+		min, max, value = math.Log(min), math.Log(max), math.Log(value)
+	}
+	value = math.Max(min, math.Min(max, value+rng.NormFloat64()*fraction*(max-min)))
+	if p.Log {
+		return math.Exp(value)
+	}
+	return value
+}
+
+func rouletteIndex(weights []float64, rng *rand.Rand) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	point := rng.Float64() * total
+	for i, w := range weights {
+		if point < w {
+			return i
+		}
+		point -= w
+	}
+	return len(weights) - 1
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return math.NaN()
+}
+
+// writeResults Writes the best options and results of all candidates into the output directory
+func (r *Race) writeResults(result *RaceResult) error {
+	if err := os.MkdirAll(r.OutDir, os.ModePerm); err != nil {
+		return err
+	}
+	writers := map[string]func(w io.Writer) error{
+		BestOptionsFileName:     result.Options.Write,
+		BestYAMLOptionsFileName: result.Options.WriteYAML,
+		RaceResultsFileName: func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(result)
+		},
+	}
+	for name, write := range writers {
+		file, err := os.Create(filepath.Join(r.OutDir, name))
+		if err != nil {
+			return err
+		}
+		if err = write(file); err != nil {
+			_ = file.Close()
+			return errors.Wrapf(err, "failed to write %s", name)
+		}
+		if err = file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package tuning
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRaceSpec(t *testing.T) {
+	specFile, err := os.Open("../../data/xor_race.yml")
+	require.NoError(t, err, "failed to open race specification")
+	defer func() {
+		_ = specFile.Close()
+	}()
+
+	spec, err := LoadRaceSpec(specFile)
+	require.NoError(t, err, "failed to load race specification")
+	assert.Equal(t, "xor_race", spec.Name)
+	assert.Equal(t, 12, spec.Candidates)
+	assert.Equal(t, 4, spec.Elites)
+	assert.Equal(t, 5, spec.FirstTest)
+	assert.Equal(t, 15, spec.MaxTrials)
+	assert.Equal(t, 500, spec.BudgetTrials)
+	assert.Len(t, spec.Parameters, 4)
+
+	_, err = LoadRaceSpec(strings.NewReader("candidates: 4\nparameters: [{name: pop_size, values: [1]}]"))
+	assert.Error(t, err, "budget expected")
+	_, err = LoadRaceSpec(strings.NewReader("candidates: 4\nelites: 4\nbudget_trials: 10\nparameters: [{name: pop_size, values: [1]}]"))
+	assert.Error(t, err, "elites less than candidates expected")
+}
+
+func TestTrialScore_better(t *testing.T) {
+	solvedFast := TrialScore{Solved: true, Evaluations: 100}
+	solvedSlow := TrialScore{Solved: true, Evaluations: 200}
+	unsolvedFit := TrialScore{Fitness: 10, Evaluations: 300}
+	unsolved := TrialScore{Fitness: 5, Evaluations: 300}
+	assert.Equal(t, 1, solvedFast.better(solvedSlow))
+	assert.Equal(t, -1, solvedSlow.better(solvedFast))
+	assert.Equal(t, 1, solvedSlow.better(unsolvedFit))
+	assert.Equal(t, 1, unsolvedFit.better(unsolved))
+	assert.Equal(t, 0, unsolved.better(unsolved))
+}
+
+func TestRankScores(t *testing.T) {
+	candidates := []*Candidate{
+		{Scores: map[int]TrialScore{0: {Fitness: 1}, 1: {Solved: true, Evaluations: 10}}},
+		{Scores: map[int]TrialScore{0: {Fitness: 3}, 1: {Solved: true, Evaluations: 10}}},
+		{Scores: map[int]TrialScore{0: {Fitness: 2}, 1: {Fitness: 3}}},
+	}
+	ranks := rankScores(candidates, 2)
+	assert.Equal(t, [][]float64{{3, 1, 2}, {1.5, 1.5, 3}}, ranks)
+
+	ranked := rankCandidates(candidates, 2)
+	assert.Equal(t, candidates[1], ranked[0])
+	assert.Equal(t, 1.25, ranked[0].MeanRank)
+}
+
+func TestFriedmanPValue(t *testing.T) {
+	assert.InDelta(t, 0.05, friedmanPValue(5.991465, 3), 1e-6)
+	assert.InDelta(t, 2.228139, studentTCritical(0.05, 10), 1e-6)
+}
+
+func TestRace_eliminate(t *testing.T) {
+	race := Race{spec: RaceSpec{Alpha: 0.05}}
+	candidates := make([]*Candidate, 4)
+	for i := range candidates {
+		candidates[i] = &Candidate{Scores: make(map[int]TrialScore)}
+		for trial := 0; trial < 6; trial++ {
+			candidates[i].Scores[trial] = TrialScore{Fitness: float64(i)}
+		}
+	}
+	survivors := race.eliminate(candidates, 6)
+	assert.Contains(t, survivors, candidates[3])
+	assert.NotContains(t, survivors, candidates[0])
+	assert.True(t, candidates[0].Eliminated)
+
+	// no difference
+	for _, c := range candidates {
+		c.Eliminated = false
+		for trial := range c.Scores {
+			c.Scores[trial] = TrialScore{Fitness: 1}
+		}
+	}
+	survivors = race.eliminate(candidates, 6)
+	assert.Len(t, survivors, 4)
+}
+
+func TestRace_trialSeed(t *testing.T) {
+	r := Race{spec: RaceSpec{Seed: 42}}
+	assert.Equal(t, int64(43), r.trialSeed(0))
+	assert.Equal(t, int64(45), r.trialSeed(2))
+
+	r.spec.Seed = 0
+	assert.Zero(t, r.trialSeed(2), "no seed expected without race seed")
+}
+
+func TestRace_Run(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "race")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	factory := &countingFactory{executed: make(map[int]int)}
+	race := Race{
+		Spec: &RaceSpec{Name: "test", Seed: 42, Candidates: 6, Elites: 2, FirstTest: 3, MaxTrials: 5,
+			BudgetTrials: 50, Workers: 3, Parameters: []Parameter{
+				{Name: "compat_threshold", Min: 0.5, Max: 4},
+				{Name: "pop_size", Values: []interface{}{10, 20}},
+			}},
+		Options:      opts,
+		StartGenome:  startGenome,
+		NewEvaluator: factory.NewEvaluator,
+		OutDir:       outDir,
+	}
+	result, err := race.Run(context.Background())
+	require.NoError(t, err, "failed to run race")
+	require.NotNil(t, result.Best)
+	assert.True(t, result.Trials <= 50, "budget exceeded: %d", result.Trials)
+	assert.True(t, result.Iterations > 1, "more than one iteration expected")
+	assert.True(t, len(result.Candidates) > 6, "new candidates expected")
+	assert.True(t, result.Best.Parameters["compat_threshold"].(float64) > 2.0)
+	assert.Equal(t, 1.0, result.Best.SuccessRate())
+	assert.Equal(t, result.Best.Parameters["compat_threshold"], result.Options.CompatThreshold)
+	assert.Equal(t, opts.NumRuns, result.Options.NumRuns)
+
+	// check that the best options written
+	neatFile, err := os.Open(filepath.Join(outDir, BestOptionsFileName))
+	require.NoError(t, err, "failed to open best options")
+	neatOpts, err := neat.LoadNeatOptions(neatFile)
+	_ = neatFile.Close()
+	require.NoError(t, err, "failed to load best options")
+	assert.Equal(t, result.Options.CompatThreshold, neatOpts.CompatThreshold)
+	assert.Equal(t, result.Options.PopSize, neatOpts.PopSize)
+
+	yamlFile, err := os.Open(filepath.Join(outDir, BestYAMLOptionsFileName))
+	require.NoError(t, err, "failed to open best YAML options")
+	yamlOpts, err := neat.LoadYAMLOptions(yamlFile)
+	_ = yamlFile.Close()
+	require.NoError(t, err, "failed to load best YAML options")
+	assert.Equal(t, result.Options.CompatThreshold, yamlOpts.CompatThreshold)
+
+	_, err = os.Stat(filepath.Join(outDir, RaceResultsFileName))
+	assert.NoError(t, err, "race results expected")
+}
+
+func TestRace_Run_budgetEvaluations(t *testing.T) {
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	factory := &countingFactory{executed: make(map[int]int)}
+	race := Race{
+		Spec: &RaceSpec{Name: "test", Candidates: 3, FirstTest: 2, BudgetEvaluations: opts.PopSize * 10,
+			Parameters: []Parameter{{Name: "compat_threshold", Min: 0.5, Max: 1.5}}},
+		Options:      opts,
+		StartGenome:  startGenome,
+		NewEvaluator: factory.NewEvaluator,
+	}
+	result, err := race.Run(context.Background())
+	require.NoError(t, err, "failed to run race")
+	// the budget is checked before each step of the race, thus it can be exceeded by the last step
+	assert.True(t, result.Evaluations >= opts.PopSize*10, "budget must be exhausted: %d", result.Evaluations)
+	assert.True(t, result.Evaluations <= opts.PopSize*10+3*opts.PopSize*opts.NumGenerations)
+	assert.Equal(t, 0.0, result.Best.SuccessRate())
+}
@@ -0,0 +1,95 @@
+package tuning
+
+import (
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"math"
+	"math/rand"
+)
+
+// Configuration the sampled configuration of the NEAT options
+type Configuration struct {
+	// The ID of the configuration
+	Id int `json:"id"`
+	// The values of the explored parameters by names
+	Parameters map[string]interface{} `json:"parameters"`
+}
+
+// SampleConfigurations Returns configurations sampled from the parameters space according to the specification. The
+// sampling is deterministic for the given seed of the specification.
+func SampleConfigurations(spec *Spec) []Configuration {
+	rng := rand.New(rand.NewSource(spec.Seed))
+	var configs []Configuration
+	switch spec.Sampling {
+	case SamplingGrid:
+		configs = sampleGrid(spec.Parameters)
+	case SamplingRandom:
+		configs = make([]Configuration, spec.Samples)
+		for i := range configs {
+			configs[i].Parameters = make(map[string]interface{}, len(spec.Parameters))
+			for _, p := range spec.Parameters {
+				configs[i].Parameters[p.Name] = p.valueAt(rng.Float64())
+			}
+		}
+	case SamplingLatinHypercube:
+		configs = make([]Configuration, spec.Samples)
+		for i := range configs {
+			configs[i].Parameters = make(map[string]interface{}, len(spec.Parameters))
+		}
+		n := float64(spec.Samples)
+		for _, p := range spec.Parameters {
+			for i, stratum := range rng.Perm(spec.Samples) {
+				configs[i].Parameters[p.Name] = p.valueAt((float64(stratum) + rng.Float64()) / n)
+			}
+		}
+	}
+	for i := range configs {
+		configs[i].Id = i
+	}
+	return configs
+}
+
+// sampleGrid Returns all combinations of the parameters levels, with the last parameter changing fastest
+func sampleGrid(params []Parameter) []Configuration {
+	configs := []Configuration{{Parameters: make(map[string]interface{})}}
+	for _, p := range params {
+		levels := p.levels()
+		expanded := make([]Configuration, 0, len(configs)*len(levels))
+		for _, c := range configs {
+			for _, level := range levels {
+				values := make(map[string]interface{}, len(c.Parameters)+1)
+				for k, v := range c.Parameters {
+					values[k] = v
+				}
+				values[p.Name] = level
+				expanded = append(expanded, Configuration{Parameters: values})
+			}
+		}
+		configs = expanded
+	}
+	return configs
+}
+
+// ApplyParameters Returns the copy of provided NEAT options with values of named parameters replaced. The parameters
+// are referred by their names in the NEAT options schema, and the values of integer options are rounded. The applied
+// values are stored back into parameters map.
+func ApplyParameters(base *neat.Options, params map[string]interface{}) (*neat.Options, error) {
+	opts := base.Clone()
+	for name, value := range params {
+		schema, ok := neat.LookupOption(name)
+		if !ok {
+			return nil, errors.Errorf("unknown NEAT option: [%s]", name)
+		}
+		if f, isFloat := value.(float64); isFloat && schema.Type == neat.OptionTypeInt {
+			value = int(math.Round(f))
+			params[name] = value
+		}
+		if err := opts.Set(name, neat.OptionValueString(value)); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply parameters: %v", params)
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "failed to apply parameters: %v", params)
+	}
+	return opts, nil
+}
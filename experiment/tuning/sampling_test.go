@@ -0,0 +1,96 @@
+package tuning
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"os"
+	"sort"
+	"testing"
+)
+
+func TestSampleConfigurations_Grid(t *testing.T) {
+	spec := &Spec{Sampling: SamplingGrid, Trials: 1, Parameters: []Parameter{
+		{Name: "compat_threshold", Min: 1, Max: 3, Steps: 3},
+		{Name: "genome_compat_method", Values: []interface{}{"linear", "fast"}},
+	}}
+	require.NoError(t, spec.Validate())
+
+	configs := SampleConfigurations(spec)
+	require.Len(t, configs, 6)
+	expected := []map[string]interface{}{
+		{"compat_threshold": 1.0, "genome_compat_method": "linear"},
+		{"compat_threshold": 1.0, "genome_compat_method": "fast"},
+		{"compat_threshold": 2.0, "genome_compat_method": "linear"},
+		{"compat_threshold": 2.0, "genome_compat_method": "fast"},
+		{"compat_threshold": 3.0, "genome_compat_method": "linear"},
+		{"compat_threshold": 3.0, "genome_compat_method": "fast"},
+	}
+	for i, c := range configs {
+		assert.Equal(t, i, c.Id)
+		assert.Equal(t, expected[i], c.Parameters)
+	}
+}
+
+func TestSampleConfigurations_Random(t *testing.T) {
+	spec := &Spec{Sampling: SamplingRandom, Samples: 10, Seed: 7, Trials: 1, Parameters: []Parameter{
+		{Name: "mutate_add_node_prob", Min: 0.001, Max: 0.1, Log: true},
+		{Name: "pop_size", Values: []interface{}{50, 100}},
+	}}
+	configs := SampleConfigurations(spec)
+	require.Len(t, configs, 10)
+	for _, c := range configs {
+		prob := c.Parameters["mutate_add_node_prob"].(float64)
+		assert.True(t, prob >= 0.001 && prob <= 0.1, "value out of range: %f", prob)
+		assert.Contains(t, []interface{}{50, 100}, c.Parameters["pop_size"])
+	}
+	// sampling is deterministic for the seed
+	assert.Equal(t, configs, SampleConfigurations(spec))
+}
+
+func TestSampleConfigurations_LatinHypercube(t *testing.T) {
+	samples := 5
+	spec := &Spec{Sampling: SamplingLatinHypercube, Samples: samples, Seed: 3, Trials: 1, Parameters: []Parameter{
+		{Name: "compat_threshold", Min: 0, Max: 5},
+		{Name: "survival_thresh", Min: 0.1, Max: 0.6},
+	}}
+	configs := SampleConfigurations(spec)
+	require.Len(t, configs, samples)
+
+	// each stratum of each parameter range must be sampled exactly once
+	for _, p := range spec.Parameters {
+		strata := make([]int, samples)
+		for i, c := range configs {
+			u := (c.Parameters[p.Name].(float64) - p.Min) / (p.Max - p.Min)
+			strata[i] = int(u * float64(samples))
+		}
+		sort.Ints(strata)
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, strata, "strata of %s", p.Name)
+	}
+}
+
+func TestApplyParameters(t *testing.T) {
+	optFile, err := os.Open("../../data/xor_test.neat")
+	require.NoError(t, err, "failed to open options file")
+	base, err := neat.LoadNeatOptions(optFile)
+	require.NoError(t, err, "failed to load options")
+
+	params := map[string]interface{}{"compat_threshold": 1.5, "pop_size": 123.6, "genome_compat_method": "linear"}
+	opts, err := ApplyParameters(base, params)
+	require.NoError(t, err, "failed to apply parameters")
+	assert.Equal(t, 1.5, opts.CompatThreshold)
+	assert.Equal(t, 124, opts.PopSize)
+	assert.Equal(t, neat.GenomeCompatibilityMethodLinear, opts.GenCompatMethod)
+	assert.Equal(t, 124, params["pop_size"], "applied value expected")
+	assert.Equal(t, base.MutateAddNodeProb, opts.MutateAddNodeProb)
+	assert.Len(t, opts.NodeActivators, 1)
+
+	// the base options are not modified
+	assert.Equal(t, 3.0, base.CompatThreshold)
+	assert.Equal(t, neat.GenomeCompatibilityMethodFast, base.GenCompatMethod)
+
+	_, err = ApplyParameters(base, map[string]interface{}{"unknown_option": 1.0})
+	assert.Error(t, err)
+	_, err = ApplyParameters(base, map[string]interface{}{"epoch_executor": "unknown"})
+	assert.Error(t, err)
+}
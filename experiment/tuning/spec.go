@@ -0,0 +1,176 @@
+package tuning
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"math"
+	"strings"
+)
+
+// SamplingMethod defines the method to sample configurations from the parameters space
+type SamplingMethod string
+
+const (
+	// SamplingGrid samples all combinations of the parameters levels
+	SamplingGrid SamplingMethod = "grid"
+	// SamplingRandom samples parameters values uniformly at random
+	SamplingRandom SamplingMethod = "random"
+	// SamplingLatinHypercube samples parameters values using Latin hypercube, i.e. each of equally sized strata of
+	// every parameter range is sampled exactly once
+	SamplingLatinHypercube SamplingMethod = "lhs"
+)
+
+// Validate is to check if this sampling method is supported
+func (m SamplingMethod) Validate() error {
+	if m != SamplingGrid && m != SamplingRandom && m != SamplingLatinHypercube {
+		return errors.Errorf("unsupported sampling method: [%s]", m)
+	}
+	return nil
+}
+
+// Parameter the definition of the range of values of the NEAT option to be explored. The option is referred by its
+// name as in the YAML configuration file, e.g. compat_threshold. The values are either listed explicitly or sampled
+// from the range [Min, Max]. The values of integer options are rounded.
+type Parameter struct {
+	// The name of the NEAT option
+	Name string `yaml:"name"`
+	// The explicit list of values, e.g. for the categorical options
+	Values []interface{} `yaml:"values,omitempty"`
+	// The minimal value of the range
+	Min float64 `yaml:"min,omitempty"`
+	// The maximal value of the range
+	Max float64 `yaml:"max,omitempty"`
+	// The number of equally spaced values taken from the range by the grid sampling
+	Steps int `yaml:"steps,omitempty"`
+	// If true, the range is explored in the logarithmic scale
+	Log bool `yaml:"log,omitempty"`
+}
+
+// Validate is to check that parameter definition is valid for the given sampling method
+func (p *Parameter) Validate(sampling SamplingMethod) error {
+	if len(p.Name) == 0 {
+		return errors.New("parameter name is empty")
+	}
+	if len(p.Values) > 0 {
+		return nil
+	}
+	if p.Min >= p.Max {
+		return errors.Errorf("parameter [%s]: either values or the range with min < max must be set", p.Name)
+	}
+	if p.Log && p.Min <= 0 {
+		return errors.Errorf("parameter [%s]: the logarithmic range must be positive", p.Name)
+	}
+	if sampling == SamplingGrid && p.Steps < 2 {
+		return errors.Errorf("parameter [%s]: at least two steps required for the grid sampling of the range", p.Name)
+	}
+	return nil
+}
+
+// levels Returns the values of the parameter explored by the grid sampling
+func (p *Parameter) levels() []interface{} {
+	if len(p.Values) > 0 {
+		return p.Values
+	}
+	levels := make([]interface{}, p.Steps)
+	for i := range levels {
+		levels[i] = p.valueAt(float64(i) / float64(p.Steps-1))
+	}
+	return levels
+}
+
+// valueAt Returns the value of the parameter at the given quantile of its range or values list
+func (p *Parameter) valueAt(u float64) interface{} {
+	if len(p.Values) > 0 {
+		index := int(u * float64(len(p.Values)))
+		if index >= len(p.Values) {
+			index = len(p.Values) - 1
+		}
+		return p.Values[index]
+	}
+	if p.Log {
+		return math.Exp(math.Log(p.Min) + u*(math.Log(p.Max)-math.Log(p.Min)))
+	}
+	return p.Min + u*(p.Max-p.Min)
+}
+
+// Spec the specification of the hyperparameters sweep
+type Spec struct {
+	// The name of the sweep
+	Name string `yaml:"name"`
+	// The method to sample configurations
+	Sampling SamplingMethod `yaml:"sampling"`
+	// The number of configurations sampled by the random and Latin hypercube sampling
+	Samples int `yaml:"samples,omitempty"`
+	// The seed of the random numbers generator used for sampling and for execution of experiments
+	Seed int64 `yaml:"seed,omitempty"`
+	// The number of trials executed for each configuration
+	Trials int `yaml:"trials"`
+	// The maximal number of configurations executed in parallel
+	Workers int `yaml:"workers,omitempty"`
+	// The maximal number of trials of one configuration executed concurrently
+	ConcurrentTrials int `yaml:"concurrent_trials,omitempty"`
+	// The parameters to be explored
+	Parameters []Parameter `yaml:"parameters"`
+}
+
+// Validate is to check that specification is valid
+func (s *Spec) Validate() error {
+	if err := s.Sampling.Validate(); err != nil {
+		return err
+	}
+	if s.Sampling != SamplingGrid && s.Samples <= 0 {
+		return errors.Errorf("the number of samples must be positive for [%s] sampling", s.Sampling)
+	}
+	if s.Trials <= 0 {
+		return errors.New("the number of trials must be positive")
+	}
+	if len(s.Parameters) == 0 {
+		return errors.New("no parameters to explore")
+	}
+	names := make(map[string]bool)
+	for i := range s.Parameters {
+		if err := s.Parameters[i].Validate(s.Sampling); err != nil {
+			return err
+		}
+		if names[s.Parameters[i].Name] {
+			return errors.Errorf("duplicate parameter: [%s]", s.Parameters[i].Name)
+		}
+		names[s.Parameters[i].Name] = true
+	}
+	return nil
+}
+
+// ParameterNames Returns the names of the explored parameters in order of definition
+func (s *Spec) ParameterNames() []string {
+	names := make([]string, len(s.Parameters))
+	for i, p := range s.Parameters {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// LoadSpec Loads the sweep specification encoded as YAML
+func LoadSpec(r io.Reader) (*Spec, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err = yaml.Unmarshal(content, &spec); err != nil {
+		return nil, errors.Wrap(err, "failed to decode sweep specification from YAML")
+	}
+	spec.Sampling = SamplingMethod(strings.ToLower(string(spec.Sampling)))
+	if err = spec.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid sweep specification")
+	}
+	return &spec, nil
+}
+
+// String Returns the human readable representation of the specification
+func (s *Spec) String() string {
+	return fmt.Sprintf("sweep [%s]: %s sampling of %v, %d trials per configuration",
+		s.Name, s.Sampling, s.ParameterNames(), s.Trials)
+}
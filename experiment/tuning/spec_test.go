@@ -0,0 +1,47 @@
+package tuning
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadSpec(t *testing.T) {
+	specFile, err := os.Open("../../data/xor_sweep.yml")
+	require.NoError(t, err, "failed to open sweep specification")
+	defer func() {
+		_ = specFile.Close()
+	}()
+
+	spec, err := LoadSpec(specFile)
+	require.NoError(t, err, "failed to load sweep specification")
+	assert.Equal(t, "xor_sweep", spec.Name)
+	assert.Equal(t, SamplingLatinHypercube, spec.Sampling)
+	assert.Equal(t, 20, spec.Samples)
+	assert.Equal(t, int64(42), spec.Seed)
+	assert.Equal(t, 10, spec.Trials)
+	assert.Equal(t, 4, spec.Workers)
+	assert.Equal(t, []string{"compat_threshold", "mutate_add_node_prob", "pop_size"}, spec.ParameterNames())
+	assert.True(t, spec.Parameters[1].Log)
+	assert.Equal(t, []interface{}{100, 150, 200}, spec.Parameters[2].Values)
+}
+
+func TestLoadSpec_invalid(t *testing.T) {
+	testCases := map[string]string{
+		"sampling":   "sampling: sobol\ntrials: 1\nparameters: [{name: pop_size, values: [1]}]",
+		"samples":    "sampling: random\ntrials: 1\nparameters: [{name: pop_size, values: [1]}]",
+		"trials":     "sampling: grid\nparameters: [{name: pop_size, values: [1]}]",
+		"parameters": "sampling: grid\ntrials: 1",
+		"range":      "sampling: grid\ntrials: 1\nparameters: [{name: compat_threshold, min: 2, max: 1, steps: 2}]",
+		"steps":      "sampling: grid\ntrials: 1\nparameters: [{name: compat_threshold, min: 1, max: 2}]",
+		"log":        "sampling: random\nsamples: 1\ntrials: 1\nparameters: [{name: compat_threshold, min: 0, max: 2, log: true}]",
+		"duplicate":  "sampling: grid\ntrials: 1\nparameters: [{name: pop_size, values: [1]}, {name: pop_size, values: [2]}]",
+		"name":       "sampling: grid\ntrials: 1\nparameters: [{values: [1]}]",
+	}
+	for name, spec := range testCases {
+		_, err := LoadSpec(strings.NewReader(spec))
+		assert.Error(t, err, "error expected for invalid %s", name)
+	}
+}
@@ -0,0 +1,367 @@
+// Package tuning provides tools to find the best NEAT options for the experiment, such as the hyperparameters sweep.
+package tuning
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// SweepLogFileName The name of the file in the sweep output directory to append results of each completed
+	// configuration to. It is used to resume the interrupted sweep.
+	SweepLogFileName = "sweep.jsonl"
+	// SweepResultsFileName The name of the file in the sweep output directory to write the consolidated results table
+	SweepResultsFileName = "sweep_results.csv"
+	// ExperimentFileName The name of the file in the configuration output directory to write results of the experiment
+	ExperimentFileName = "experiment.json"
+)
+
+// EvaluatorFactory Creates the generation evaluator for the experiment with given configuration. The outDir is the
+// output directory of the configuration.
+type EvaluatorFactory func(config Configuration, outDir string) (experiment.GenerationEvaluator, error)
+
+// Result the results of the experiment executed with one configuration of the sweep
+type Result struct {
+	Configuration
+	// The number of executed trials
+	Trials int `json:"trials"`
+	// The number of solved trials
+	Solved int `json:"solved"`
+	// The success rate of the trials
+	SuccessRate float64 `json:"success_rate"`
+	// The efficiency score of the experiment, see experiment.Experiment.EfficiencyScore
+	EfficiencyScore float64 `json:"efficiency_score"`
+	// The mean of the best fitness scores of the trials
+	MeanBestFitness float64 `json:"mean_best_fitness"`
+	// The mean number of evaluations to solve among the solved trials or zero if no trial solved
+	MeanEvaluationsToSolve float64 `json:"mean_evaluations_to_solve"`
+	// The mean number of generations per trial
+	MeanGenerations float64 `json:"mean_generations"`
+	// The duration of the experiment execution
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// NewResult Creates the result of the configuration from the executed experiment
+func NewResult(config Configuration, exp *experiment.Experiment, duration time.Duration) Result {
+	return Result{
+		Configuration:          config,
+		Trials:                 len(exp.Trials),
+		Solved:                 exp.TrialsSolved(),
+		SuccessRate:            exp.SuccessRate(),
+		EfficiencyScore:        finiteOrZero(exp.EfficiencyScore()),
+		MeanBestFitness:        finiteOrZero(exp.BestFitness().Mean()),
+		MeanEvaluationsToSolve: finiteOrZero(exp.EvaluationsToSolve().Mean()),
+		MeanGenerations:        exp.AvgGenerationsPerTrial(),
+		Duration:               duration,
+	}
+}
+
+// finiteOrZero Returns zero for NaN and infinite values, which are produced by statistics of empty samples and
+// can not be encoded into JSON
+func finiteOrZero(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+// Results is a collection of results of the sweep sortable by rank: the higher success rate goes first, and
+// configurations with equal success rate are ordered by the efficiency score.
+type Results []Result
+
+func (rs Results) Len() int {
+	return len(rs)
+}
+func (rs Results) Swap(i, j int) {
+	rs[i], rs[j] = rs[j], rs[i]
+}
+func (rs Results) Less(i, j int) bool {
+	if rs[i].SuccessRate != rs[j].SuccessRate {
+		return rs[i].SuccessRate > rs[j].SuccessRate
+	}
+	if rs[i].EfficiencyScore != rs[j].EfficiencyScore {
+		return rs[i].EfficiencyScore > rs[j].EfficiencyScore
+	}
+	return rs[i].Id < rs[j].Id
+}
+
+// WriteCSV Writes the results table with the rank, the values of the given parameters and the statistics of each
+// configuration in the current order of results
+func (rs Results) WriteCSV(w io.Writer, parameters []string) error {
+	out := csv.NewWriter(w)
+	header := append([]string{"rank", "configuration"}, parameters...)
+	header = append(header, "trials", "solved", "success_rate", "efficiency_score", "mean_best_fitness",
+		"mean_evaluations_to_solve", "mean_generations", "duration_seconds")
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for i, r := range rs {
+		record := []string{strconv.Itoa(i + 1), strconv.Itoa(r.Id)}
+		for _, name := range parameters {
+			record = append(record, fmt.Sprint(r.Parameters[name]))
+		}
+		record = append(record, strconv.Itoa(r.Trials), strconv.Itoa(r.Solved), formatFloat(r.SuccessRate),
+			formatFloat(r.EfficiencyScore), formatFloat(r.MeanBestFitness), formatFloat(r.MeanEvaluationsToSolve),
+			formatFloat(r.MeanGenerations), formatFloat(r.Duration.Seconds()))
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Sweep the runner of the hyperparameters sweep. It executes the experiment with the given number of trials for each
+// configuration sampled according to the specification and writes results into the output directory:
+// - sweep.jsonl - the results of the completed configurations in order of completion
+// - sweep_results.csv - the consolidated table of results ranked by success rate and efficiency score
+// - config_[id]/experiment.json - the full results of the experiment executed with configuration
+// If the output directory already has results of some configurations, these are not executed again, which allows
+// resuming the interrupted sweep. The specification must not be changed between runs.
+type Sweep struct {
+	// The sweep specification
+	Spec *Spec
+	// The base NEAT options to be modified by each configuration
+	Options *neat.Options
+	// The start genome of the experiment
+	StartGenome *genetics.Genome
+	// The factory of the generation evaluators
+	NewEvaluator EvaluatorFactory
+	// The output directory
+	OutDir string
+	// The maximal fitness score of the experiment, see experiment.Experiment.MaxFitnessScore
+	MaxFitnessScore float64
+
+	mutex sync.Mutex
+}
+
+// Run Executes the sweep and returns the ranked results of all configurations. The execution can be interrupted by
+// canceling provided context, in which case the results of completed configurations are preserved to resume later.
+func (s *Sweep) Run(ctx context.Context) (Results, error) {
+	if err := s.Spec.Validate(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.OutDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	// sample configurations and prepare the options of each
+	configs := SampleConfigurations(s.Spec)
+	options := make([]*neat.Options, len(configs))
+	for i := range configs {
+		opts, err := ApplyParameters(s.Options, configs[i].Parameters)
+		if err != nil {
+			return nil, errors.Wrapf(err, "configuration %d", configs[i].Id)
+		}
+		opts.NumRuns = s.Spec.Trials
+		options[i] = opts
+	}
+
+	// load results of the previous run
+	completed, err := loadSweepLog(filepath.Join(s.OutDir, SweepLogFileName), configs)
+	if err != nil {
+		return nil, err
+	}
+	results := make(Results, 0, len(configs))
+	pending := make([]int, 0, len(configs))
+	for i, c := range configs {
+		if r, ok := completed[c.Id]; ok {
+			results = append(results, r)
+		} else {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) < len(configs) {
+		neat.InfoLog(fmt.Sprintf("Sweep [%s] resumed, %d of %d configurations completed",
+			s.Spec.Name, len(configs)-len(pending), len(configs)))
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(s.OutDir, SweepLogFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = logFile.Close()
+	}()
+
+	// execute pending configurations on the pool of workers
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan int)
+	errs := make(chan error, len(pending))
+	var wg sync.WaitGroup
+	workers := parallelWorkers(s.Spec.Workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				result, err := s.runConfiguration(ctx, configs[i], options[i])
+				if err == nil {
+					err = s.appendResult(logFile, result)
+				}
+				if err != nil {
+					errs <- errors.Wrapf(err, "configuration %d", configs[i].Id)
+					cancel()
+					continue
+				}
+				s.mutex.Lock()
+				results = append(results, *result)
+				s.mutex.Unlock()
+			}
+		}()
+	}
+	for _, i := range pending {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	sort.Sort(results)
+	if err = s.writeResultsTable(results); err != nil {
+		return nil, err
+	}
+	if err = <-errs; err != nil {
+		return results, err
+	}
+	return results, ctx.Err()
+}
+
+// parallelWorkers Returns the number of workers to execute experiments in parallel, which is at least one
+func parallelWorkers(workers int) int {
+	if workers < 1 {
+		return 1
+	}
+	return workers
+}
+
+// runConfiguration Executes the experiment with given configuration
+func (s *Sweep) runConfiguration(ctx context.Context, config Configuration, opts *neat.Options) (*Result, error) {
+	outDir := filepath.Join(s.OutDir, fmt.Sprintf("config_%d", config.Id))
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	evaluator, err := s.NewEvaluator(config, outDir)
+	if err != nil {
+		return nil, err
+	}
+	exp := experiment.Experiment{
+		Id:                  config.Id,
+		Name:                fmt.Sprintf("%s config %d", s.Spec.Name, config.Id),
+		RandSeed:            s.Spec.Seed,
+		MaxFitnessScore:     s.MaxFitnessScore,
+		MaxConcurrentTrials: s.Spec.ConcurrentTrials,
+	}
+	neat.InfoLog(fmt.Sprintf("Sweep [%s] configuration %d started: %v", s.Spec.Name, config.Id, config.Parameters))
+	start := time.Now()
+	if err = exp.Execute(neat.NewContext(ctx, opts), s.StartGenome, evaluator, nil); err != nil {
+		return nil, err
+	}
+	result := NewResult(config, &exp, time.Since(start))
+
+	expFile, err := os.Create(filepath.Join(outDir, ExperimentFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = expFile.Close()
+	}()
+	if err = exp.WriteJSON(expFile); err != nil {
+		return nil, err
+	}
+	neat.InfoLog(fmt.Sprintf("Sweep [%s] configuration %d finished, success rate: %f, efficiency score: %f",
+		s.Spec.Name, config.Id, result.SuccessRate, result.EfficiencyScore))
+	return &result, nil
+}
+
+// appendResult Appends the result of the completed configuration to the sweep log
+func (s *Sweep) appendResult(w io.Writer, result *Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// writeResultsTable Writes the consolidated results table
+func (s *Sweep) writeResultsTable(results Results) error {
+	file, err := os.Create(filepath.Join(s.OutDir, SweepResultsFileName))
+	if err != nil {
+		return err
+	}
+	if err = results.WriteCSV(file, s.Spec.ParameterNames()); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// loadSweepLog Loads the results of the configurations completed by the previous run of the sweep. Returns error if
+// the completed configuration differs from the one sampled now, i.e. the specification was changed.
+func loadSweepLog(path string, configs []Configuration) (map[int]Result, error) {
+	completed := make(map[int]Result)
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return completed, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var r Result
+		if err = json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, errors.Wrap(err, "failed to decode sweep log")
+		}
+		if r.Id < 0 || r.Id >= len(configs) {
+			return nil, errors.Errorf("sweep log has unknown configuration: %d", r.Id)
+		}
+		if !sameParameters(r.Parameters, configs[r.Id].Parameters) {
+			return nil, errors.Errorf("sweep log has configuration %d with parameters %v, expected %v, "+
+				"the specification was changed", r.Id, r.Parameters, configs[r.Id].Parameters)
+		}
+		// use the parameters values as sampled to have the same types of values
+		r.Parameters = configs[r.Id].Parameters
+		completed[r.Id] = r
+	}
+	return completed, scanner.Err()
+}
+
+func sameParameters(a, b map[string]interface{}) bool {
+	aData, aErr := json.Marshal(a)
+	bData, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aData) == string(bData)
+}
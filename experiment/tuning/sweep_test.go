@@ -0,0 +1,167 @@
+package tuning
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/experiment"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/genetics"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// thresholdEvaluator solves the task when the compatibility threshold of options is above the limit
+type thresholdEvaluator struct {
+	limit float64
+}
+
+func (e *thresholdEvaluator) OrganismEvaluate(_ *genetics.Organism, opts *neat.Options) (float64, float64, bool, error) {
+	return opts.CompatThreshold, 0, opts.CompatThreshold > e.limit, nil
+}
+
+// countingFactory creates evaluators and counts configurations executed
+type countingFactory struct {
+	executed map[int]int
+	mutex    sync.Mutex
+}
+
+func (f *countingFactory) NewEvaluator(config Configuration, _ string) (experiment.GenerationEvaluator, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.executed[config.Id]++
+	return experiment.NewParallelGenerationEvaluator(&thresholdEvaluator{limit: 2.0}, "", "test", 2), nil
+}
+
+func loadTestOptionsAndGenome(t *testing.T) (*neat.Options, *genetics.Genome) {
+	optFile, err := os.Open("../../data/xor_test.neat")
+	require.NoError(t, err, "failed to open options file")
+	opts, err := neat.LoadNeatOptions(optFile)
+	require.NoError(t, err, "failed to load options")
+	opts.PopSize = 20
+	opts.NumGenerations = 2
+
+	genomeFile, err := os.Open("../../data/xorstartgenes")
+	require.NoError(t, err, "failed to open genome file")
+	startGenome, err := genetics.ReadGenome(genomeFile, 1)
+	require.NoError(t, err, "failed to read start genome")
+	return opts, startGenome
+}
+
+func TestSweep_Run(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "sweep")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	factory := &countingFactory{executed: make(map[int]int)}
+	sweep := Sweep{
+		Spec: &Spec{Name: "test", Sampling: SamplingGrid, Trials: 2, Workers: 2, Seed: 42, Parameters: []Parameter{
+			{Name: "compat_threshold", Values: []interface{}{1.0, 3.0}},
+			{Name: "pop_size", Values: []interface{}{10, 20}},
+		}},
+		Options:      opts,
+		StartGenome:  startGenome,
+		NewEvaluator: factory.NewEvaluator,
+		OutDir:       outDir,
+	}
+	results, err := sweep.Run(context.Background())
+	require.NoError(t, err, "failed to run sweep")
+	require.Len(t, results, 4)
+	assert.Equal(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 1}, factory.executed)
+
+	// the solving configurations are ranked first
+	for i, r := range results {
+		assert.Equal(t, 2, r.Trials)
+		if i < 2 {
+			assert.Equal(t, 3.0, r.Parameters["compat_threshold"])
+			assert.Equal(t, 1.0, r.SuccessRate)
+			assert.Equal(t, 3.0, r.MeanBestFitness)
+		} else {
+			assert.Equal(t, 1.0, r.Parameters["compat_threshold"])
+			assert.Equal(t, 0.0, r.SuccessRate)
+		}
+	}
+
+	// check output files
+	for id := range factory.executed {
+		_, err = os.Stat(filepath.Join(outDir, "config_"+string(rune('0'+id)), ExperimentFileName))
+		assert.NoError(t, err, "experiment results expected for configuration %d", id)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(outDir, SweepResultsFileName))
+	require.NoError(t, err, "failed to read results table")
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err, "failed to parse results table")
+	require.Len(t, records, 5)
+	assert.Equal(t, []string{"rank", "configuration", "compat_threshold", "pop_size", "trials", "solved"}, records[0][:6])
+	assert.Equal(t, "1", records[1][0])
+	assert.Equal(t, "3", records[1][2])
+
+	// drop the last completed configuration from the log and resume
+	logPath := filepath.Join(outDir, SweepLogFileName)
+	data, err = ioutil.ReadFile(logPath)
+	require.NoError(t, err, "failed to read sweep log")
+	lines := bytes.Split(bytes.TrimSpace(data), []byte("\n"))
+	require.Len(t, lines, 4)
+	lastId := -1
+	for id := range factory.executed {
+		if bytes.Contains(lines[3], []byte(`"id":`+string(rune('0'+id))+`,`)) {
+			lastId = id
+		}
+	}
+	require.True(t, lastId >= 0, "configuration ID not found in log")
+	err = ioutil.WriteFile(logPath, append(bytes.Join(lines[:3], []byte("\n")), '\n'), 0644)
+	require.NoError(t, err)
+
+	resumed, err := sweep.Run(context.Background())
+	require.NoError(t, err, "failed to resume sweep")
+	require.Len(t, resumed, 4)
+	assert.Equal(t, 2, factory.executed[lastId], "only the dropped configuration expected to be executed again")
+	for i := range results {
+		assert.Equal(t, results[i].SuccessRate, resumed[i].SuccessRate)
+	}
+
+	// the changed specification is detected
+	sweep.Spec.Parameters[0].Values = []interface{}{1.5, 3.0}
+	_, err = sweep.Run(context.Background())
+	assert.Error(t, err)
+}
+
+func TestSweep_Run_canceled(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "sweep")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	opts, startGenome := loadTestOptionsAndGenome(t)
+	factory := &countingFactory{executed: make(map[int]int)}
+	sweep := Sweep{
+		Spec: &Spec{Name: "test", Sampling: SamplingRandom, Samples: 3, Trials: 1, Parameters: []Parameter{
+			{Name: "compat_threshold", Min: 1, Max: 4},
+		}},
+		Options:      opts,
+		StartGenome:  startGenome,
+		NewEvaluator: factory.NewEvaluator,
+		OutDir:       outDir,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := sweep.Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, results)
+	assert.Empty(t, factory.executed)
+}
+
+func TestParallelWorkers(t *testing.T) {
+	assert.Equal(t, 1, parallelWorkers(0))
+	assert.Equal(t, 1, parallelWorkers(-1))
+	assert.Equal(t, 3, parallelWorkers(3))
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/yaricom/goNEAT/v2/neat/genetics"
 	"github.com/yaricom/goNEAT/v2/neat/network"
 	"math"
-	"os"
 	"sort"
 )
 
@@ -22,9 +21,7 @@ const nonMarkovLongMaxSteps = 100000
 // The maximal number of time steps for Non-Markov generalization run
 const nonMarkovGeneralizationMaxSteps = 1000
 
-type cartDoublePoleGenerationEvaluator struct {
-	// The output path to store execution results
-	OutputPath string
+type cartDoublePoleOrganismEvaluator struct {
 	// The flag to indicate whether to apply Markov evaluation variant
 	Markov bool
 
@@ -34,11 +31,37 @@ type cartDoublePoleGenerationEvaluator struct {
 
 // NewCartDoublePoleGenerationEvaluator is the generations evaluator for double-pole balancing experiment: both Markov and non-Markov versions
 func NewCartDoublePoleGenerationEvaluator(outDir string, markov bool, actionType ActionType) experiment.GenerationEvaluator {
-	return &cartDoublePoleGenerationEvaluator{
-		OutputPath: outDir,
+	orgEvaluator := NewCartDoublePoleOrganismEvaluator(markov, actionType)
+	evaluator := experiment.NewParallelGenerationEvaluator(orgEvaluator, outDir, "pole2", 0)
+	evaluator.GenomeFileName = doublePoleGenomeFileName
+	return evaluator
+}
+
+// NewCartDoublePoleOrganismEvaluator is the organism evaluator for double-pole balancing experiment, e.g. to be
+// registered by the remote evaluation worker.
+func NewCartDoublePoleOrganismEvaluator(markov bool, actionType ActionType) experiment.OrganismEvaluator {
+	return &cartDoublePoleOrganismEvaluator{
+		Markov:     markov,
+		ActionType: actionType,
+	}
+}
+
+// NewCartDoublePoleDistributedEvaluator is the coordinator of distributed evaluation for double-pole balancing
+// experiment. The organisms are evaluated by remote workers with the evaluator registered for the given task, while the
+// champion tests of the Non-Markov version are run locally.
+func NewCartDoublePoleDistributedEvaluator(task string, workerURLs []string, outDir string, markov bool, actionType ActionType) *experiment.DistributedGenerationEvaluator {
+	evaluator := experiment.NewDistributedGenerationEvaluator(task, workerURLs, outDir, "pole2")
+	evaluator.PostEvaluator = &cartDoublePoleOrganismEvaluator{
 		Markov:     markov,
 		ActionType: actionType,
 	}
+	evaluator.GenomeFileName = doublePoleGenomeFileName
+	return evaluator
+}
+
+// doublePoleGenomeFileName keeps the winner fitness in the dumped genome file name
+func doublePoleGenomeFileName(org *genetics.Organism, kind string) string {
+	return fmt.Sprintf("pole2_%s_%.1f_%d-%d", kind, org.Fitness, org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
 }
 
 // CartPole The structure to describe cart pole emulation
@@ -65,202 +88,152 @@ type CartPole struct {
 	poleVelocitySum float64
 }
 
-// GenerationEvaluate Perform evaluation of one epoch on double pole balancing
-func (e *cartDoublePoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiment.Generation, context *neat.Options) (err error) {
+// OrganismEvaluate Perform evaluation of the organism on double pole balancing. Each organism is evaluated with its
+// own cart pole emulator, thus organisms can be evaluated concurrently.
+func (e *cartDoublePoleOrganismEvaluator) OrganismEvaluate(organism *genetics.Organism, _ *neat.Options) (fitness, errValue float64, winner bool, err error) {
 	cartPole := newCartPole(e.Markov)
+	return e.orgEvaluate(organism, cartPole)
+}
 
-	cartPole.nonMarkovLong = false
-	cartPole.generalizationTest = false
-
-	// Evaluate each organism on a test
-	for _, org := range pop.Organisms {
-		winner, err := e.orgEvaluate(org, cartPole)
-		if err != nil {
-			return err
-		}
+// PostEvaluate Checks for the winner in Non-Markov case by running the champion of the generation through the long run
+// and generalization tests.
+func (e *cartDoublePoleOrganismEvaluator) PostEvaluate(pop *genetics.Population, epoch *experiment.Generation, context *neat.Options) (err error) {
+	if e.Markov {
+		return nil
+	}
+	cartPole := newCartPole(e.Markov)
 
-		if winner && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
-			// This will be winner in Markov case
-			epoch.Solved = true
-			epoch.WinnerNodes = len(org.Genotype.Nodes)
-			epoch.WinnerGenes = org.Genotype.Extrons()
-			epoch.WinnerEvals = context.PopSize*epoch.Id + org.Genotype.Id
-			epoch.Best = org
-			org.IsWinner = true
+	// The best individual (i.e. the one with the highest fitness value) of every generation is tested for
+	// its ability to balance the system for a longer time period. If a potential solution passes this test
+	// by keeping the system balanced for 100’000 time steps, the so called generalization score(GS) of this
+	// particular individual is calculated. This score measures the potential of a controller to balance the
+	// system starting from different initial conditions. It's calculated with a series of experiments, running
+	// over 1000 time steps, starting from 625 different initial conditions.
+	// The initial conditions are chosen by assigning each value of the set Ω = [0.05 0.25 0.5 0.75 0.95] to
+	// each of the states x, ∆x/∆t, θ1 and ∆θ1/∆t, scaled to the range of the variables.The short pole angle θ2
+	// and its angular velocity ∆θ2/∆t are set to zero. The GS is then defined as the number of successful runs
+	// from the 625 initial conditions and an individual is defined as a solution if it reaches a generalization
+	// score of 200 or more.
+
+	// Sort the species by max organism fitness in descending order - the highest fitness first
+	sortedSpecies := make([]*genetics.Species, len(pop.Species))
+	copy(sortedSpecies, pop.Species)
+	sort.Sort(sort.Reverse(genetics.ByOrganismFitness(sortedSpecies)))
+
+	// First update what is checked and unchecked
+	var currSpecies *genetics.Species
+	for _, currSpecies = range sortedSpecies {
+		max, _ := currSpecies.ComputeMaxAndAvgFitness()
+		if max > currSpecies.MaxFitnessEver {
+			currSpecies.IsChecked = false
 		}
 	}
 
-	// Check for winner in Non-Markov case
-	if !e.Markov {
-		// The best individual (i.e. the one with the highest fitness value) of every generation is tested for
-		// its ability to balance the system for a longer time period. If a potential solution passes this test
-		// by keeping the system balanced for 100’000 time steps, the so called generalization score(GS) of this
-		// particular individual is calculated. This score measures the potential of a controller to balance the
-		// system starting from different initial conditions. It's calculated with a series of experiments, running
-		// over 1000 time steps, starting from 625 different initial conditions.
-		// The initial conditions are chosen by assigning each value of the set Ω = [0.05 0.25 0.5 0.75 0.95] to
-		// each of the states x, ∆x/∆t, θ1 and ∆θ1/∆t, scaled to the range of the variables.The short pole angle θ2
-		// and its angular velocity ∆θ2/∆t are set to zero. The GS is then defined as the number of successful runs
-		// from the 625 initial conditions and an individual is defined as a solution if it reaches a generalization
-		// score of 200 or more.
-
-		// Sort the species by max organism fitness in descending order - the highest fitness first
-		sortedSpecies := make([]*genetics.Species, len(pop.Species))
-		copy(sortedSpecies, pop.Species)
-		sort.Sort(sort.Reverse(genetics.ByOrganismFitness(sortedSpecies)))
-
-		// First update what is checked and unchecked
-		var currSpecies *genetics.Species
-		for _, currSpecies = range sortedSpecies {
-			max, _ := currSpecies.ComputeMaxAndAvgFitness()
-			if max > currSpecies.MaxFitnessEver {
-				currSpecies.IsChecked = false
-			}
+	// Now find first (most fit) species that is unchecked
+	currSpecies = nil
+	for _, currSpecies = range sortedSpecies {
+		if !currSpecies.IsChecked {
+			break
 		}
+	}
+	if currSpecies == nil {
+		currSpecies = sortedSpecies[0]
+	}
 
-		// Now find first (most fit) species that is unchecked
-		currSpecies = nil
-		for _, currSpecies = range sortedSpecies {
-			if !currSpecies.IsChecked {
-				break
-			}
-		}
-		if currSpecies == nil {
-			currSpecies = sortedSpecies[0]
-		}
+	// Remember it was checked
+	currSpecies.IsChecked = true
 
-		// Remember it was checked
-		currSpecies.IsChecked = true
+	// the organism champion
+	champion := currSpecies.FindChampion()
 
-		// the organism champion
-		champion := currSpecies.FindChampion()
-		championFitness := champion.Fitness
+	// Now check to make sure the champion can do 100'000 evaluations
+	cartPole.nonMarkovLong = true
+	cartPole.generalizationTest = false
 
-		// Now check to make sure the champion can do 100'000 evaluations
-		cartPole.nonMarkovLong = true
-		cartPole.generalizationTest = false
+	_, _, longRunPassed, err := e.orgEvaluate(champion, cartPole)
+	if err != nil {
+		return err
+	}
+	if longRunPassed {
+
+		// the champion passed non-Markov long test, start generalization
+		cartPole.nonMarkovLong = false
+		cartPole.generalizationTest = true
+
+		// Given that the champion passed long run test, now run it on generalization tests running
+		// over 1'000 time steps, starting from 625 different initial conditions
+		stateVals := [5]float64{0.05, 0.25, 0.5, 0.75, 0.95}
+		generalizationScore := 0
+		for s0c := 0; s0c < 5; s0c++ {
+			for s1c := 0; s1c < 5; s1c++ {
+				for s2c := 0; s2c < 5; s2c++ {
+					for s3c := 0; s3c < 5; s3c++ {
+						cartPole.state[0] = stateVals[s0c]*4.32 - 2.16
+						cartPole.state[1] = stateVals[s1c]*2.70 - 1.35
+						cartPole.state[2] = stateVals[s2c]*0.12566304 - 0.06283152 // 0.06283152 = 3.6 degrees
+						cartPole.state[3] = stateVals[s3c]*0.30019504 - 0.15009752 // 0.15009752 = 8.6 degrees
+						// The short pole angle and its angular velocity are set to zero.
+						cartPole.state[4] = 0.0
+						cartPole.state[5] = 0.0
+
+						// The champion needs to be flushed here because it may have
+						// leftover activation from its last test run that could affect
+						// its recurrent memory
+						if _, err = champion.Phenotype.Flush(); err != nil {
+							return err
+						}
 
-		longRunPassed, err := e.orgEvaluate(champion, cartPole)
-		if err != nil {
-			return err
-		}
-		if longRunPassed {
-
-			// the champion passed non-Markov long test, start generalization
-			cartPole.nonMarkovLong = false
-			cartPole.generalizationTest = true
-
-			// Given that the champion passed long run test, now run it on generalization tests running
-			// over 1'000 time steps, starting from 625 different initial conditions
-			stateVals := [5]float64{0.05, 0.25, 0.5, 0.75, 0.95}
-			generalizationScore := 0
-			for s0c := 0; s0c < 5; s0c++ {
-				for s1c := 0; s1c < 5; s1c++ {
-					for s2c := 0; s2c < 5; s2c++ {
-						for s3c := 0; s3c < 5; s3c++ {
-							cartPole.state[0] = stateVals[s0c]*4.32 - 2.16
-							cartPole.state[1] = stateVals[s1c]*2.70 - 1.35
-							cartPole.state[2] = stateVals[s2c]*0.12566304 - 0.06283152 // 0.06283152 = 3.6 degrees
-							cartPole.state[3] = stateVals[s3c]*0.30019504 - 0.15009752 // 0.15009752 = 8.6 degrees
-							// The short pole angle and its angular velocity are set to zero.
-							cartPole.state[4] = 0.0
-							cartPole.state[5] = 0.0
-
-							// The champion needs to be flushed here because it may have
-							// leftover activation from its last test run that could affect
-							// its recurrent memory
-							if _, err = champion.Phenotype.Flush(); err != nil {
-								return err
-							}
+						if _, _, generalized, err := e.orgEvaluate(champion, cartPole); generalized {
+							generalizationScore++
 
-							if generalized, err := e.orgEvaluate(champion, cartPole); generalized {
-								generalizationScore++
-
-								if neat.LogLevel == neat.LogLevelDebug {
-									neat.DebugLog(
-										fmt.Sprintf("x: %f, xv: %f, t1: %f, t2: %f, angle: %f\n",
-											cartPole.state[0], cartPole.state[1],
-											cartPole.state[2], cartPole.state[4], thirtySixDegrees))
-								}
-							} else if err != nil {
-								return err
+							if neat.LogLevel == neat.LogLevelDebug {
+								neat.DebugLog(
+									fmt.Sprintf("x: %f, xv: %f, t1: %f, t2: %f, angle: %f\n",
+										cartPole.state[0], cartPole.state[1],
+										cartPole.state[2], cartPole.state[4], thirtySixDegrees))
 							}
+						} else if err != nil {
+							return err
 						}
 					}
 				}
 			}
+		}
 
-			if generalizationScore >= 200 {
-				// The generalization test winner
-				neat.InfoLog(
-					fmt.Sprintf("The non-Markov champion found! (Generalization Score = %d)",
-						generalizationScore))
-				champion.Fitness = float64(generalizationScore)
-				champion.IsWinner = true
-				epoch.Solved = true
-				epoch.WinnerNodes = len(champion.Genotype.Nodes)
-				epoch.WinnerGenes = champion.Genotype.Extrons()
-				epoch.WinnerEvals = context.PopSize*epoch.Id + champion.Genotype.Id
-				epoch.Best = champion
-			} else {
-				neat.InfoLog("The non-Markov champion unable to generalize")
-				champion.Fitness = championFitness // Restore the champ's fitness
-				champion.IsWinner = false
-			}
+		if generalizationScore >= 200 {
+			// The generalization test winner
+			neat.InfoLog(
+				fmt.Sprintf("The non-Markov champion found! (Generalization Score = %d)",
+					generalizationScore))
+			champion.Fitness = float64(generalizationScore)
+			champion.IsWinner = true
+			epoch.Solved = true
+			epoch.WinnerNodes = len(champion.Genotype.Nodes)
+			epoch.WinnerGenes = champion.Genotype.Extrons()
+			epoch.WinnerEvals = context.PopSize*epoch.Id + champion.Genotype.Id
+			epoch.Best = champion
 		} else {
-			neat.InfoLog("The non-Markov champion missed the 100'000 run test")
-			champion.Fitness = championFitness // Restore the champ's fitness
+			neat.InfoLog("The non-Markov champion unable to generalize")
 			champion.IsWinner = false
 		}
+	} else {
+		neat.InfoLog("The non-Markov champion missed the 100'000 run test")
+		champion.IsWinner = false
 	}
-
-	// Fill statistics about current epoch
-	epoch.FillPopulationStatistics(pop)
-
-	// Only print to file every print_every generations
-	if epoch.Solved || epoch.Id%context.PrintEvery == 0 {
-		popPath := fmt.Sprintf("%s/gen_%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId), epoch.Id)
-		if file, err := os.Create(popPath); err != nil {
-			return err
-		} else if err = pop.WriteBySpecies(file); err != nil {
-			neat.ErrorLog(fmt.Sprintf("Failed to dump population, reason: %s\n", err))
-			return err
-		}
-	}
-
-	if epoch.Solved {
-		// print winner organism
-		for _, org := range pop.Organisms {
-			if org.IsWinner {
-				// Prints the winner organism to file!
-				orgPath := fmt.Sprintf("%s/%s_%.1f_%d-%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId),
-					"pole2_winner", org.Fitness, org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
-				if file, err := os.Create(orgPath); err != nil {
-					return err
-				} else if err = org.Genotype.Write(file); err != nil {
-					neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
-					return err
-				} else {
-					neat.InfoLog(fmt.Sprintf("Generation #%d winner %d dumped to: %s\n", epoch.Id, org.Genotype.Id, orgPath))
-				}
-				break
-			}
-		}
-	}
-
-	return err
+	return nil
 }
 
 // This methods evaluates provided organism for cart double pole-balancing task
-func (e *cartDoublePoleGenerationEvaluator) orgEvaluate(organism *genetics.Organism, cartPole *CartPole) (winner bool, err error) {
+func (e *cartDoublePoleOrganismEvaluator) orgEvaluate(organism *genetics.Organism, cartPole *CartPole) (fitness, errValue float64, winner bool, err error) {
 	// Try to balance a pole now
-	organism.Fitness, err = cartPole.evalNet(organism.Phenotype, e.ActionType)
+	fitness, err = cartPole.evalNet(organism.Phenotype, e.ActionType)
 	if err != nil {
-		return false, err
+		return 0, 0, false, err
 	}
+	errValue = organism.Error
 
 	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, organism.Fitness))
+		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, fitness))
 	}
 
 	// DEBUG CHECK if organism is damaged
@@ -270,28 +243,28 @@ func (e *cartDoublePoleGenerationEvaluator) orgEvaluate(organism *genetics.Organ
 
 	// Decide if its a winner, in Markov Case
 	if cartPole.isMarkov {
-		if organism.Fitness >= markovMaxSteps {
+		if fitness >= markovMaxSteps {
 			winner = true
-			organism.Fitness = 1.0
-			organism.Error = 0.0
+			fitness = 1.0
+			errValue = 0.0
 		} else {
 			// we use linear scale
-			organism.Error = (markovMaxSteps - organism.Fitness) / markovMaxSteps
-			organism.Fitness = 1.0 - organism.Error
+			errValue = (markovMaxSteps - fitness) / markovMaxSteps
+			fitness = 1.0 - errValue
 		}
 	} else if cartPole.nonMarkovLong {
 		// if doing the long test non-markov
-		if organism.Fitness >= nonMarkovLongMaxSteps {
+		if fitness >= nonMarkovLongMaxSteps {
 			winner = true
 		}
 	} else if cartPole.generalizationTest {
-		if organism.Fitness >= nonMarkovGeneralizationMaxSteps {
+		if fitness >= nonMarkovGeneralizationMaxSteps {
 			winner = true
 		}
 	} else {
 		winner = false
 	}
-	return winner, err
+	return fitness, errValue, winner, err
 }
 
 // If markov is false, then velocity information will be withheld from the network population (non-Markov)
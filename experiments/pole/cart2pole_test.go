@@ -170,3 +170,11 @@ func TestCartDoublePoleGenerationEvaluator_GenerationEvaluateNonMarkov(t *testin
 	}
 	t.Logf("Best Generalization Score: %.0f\n", bestGeneralizationScore)
 }
+
+func TestNewCartDoublePoleDistributedEvaluator(t *testing.T) {
+	workerURLs := []string{"http://localhost:8081", "http://localhost:8082"}
+	evaluator := NewCartDoublePoleDistributedEvaluator("pole2", workerURLs, "", false, ContinuousAction)
+	require.NotNil(t, evaluator.PostEvaluator, "the champion tests of Non-Markov version must be run locally")
+	assert.Equal(t, len(workerURLs), evaluator.Remote.LiveWorkers())
+	assert.NotNil(t, evaluator.GenomeFileName)
+}
@@ -1,6 +1,7 @@
 package pole
 
 import (
+	"context"
 	"fmt"
 	"github.com/yaricom/goNEAT/v2/experiment"
 	"github.com/yaricom/goNEAT/v2/neat"
@@ -8,14 +9,11 @@ import (
 	"github.com/yaricom/goNEAT/v2/neat/network"
 	"math"
 	"math/rand"
-	"os"
 )
 
 const twelveDegrees = 12.0 * math.Pi / 180.0
 
-type cartPoleGenerationEvaluator struct {
-	// The output path to store execution results
-	OutputPath string
+type cartPoleOrganismEvaluator struct {
 	// The flag to indicate if cart emulator should be started from random position
 	RandomStart bool
 	// The number of emulation steps to be done balancing pole to win
@@ -25,128 +23,73 @@ type cartPoleGenerationEvaluator struct {
 // NewCartPoleGenerationEvaluator is to create generations evaluator for single-pole balancing experiment.
 // This experiment performs evolution on single pole balancing task in order to produce appropriate genome.
 func NewCartPoleGenerationEvaluator(outDir string, randomStart bool, winBalanceSteps int) experiment.GenerationEvaluator {
-	return &cartPoleGenerationEvaluator{
-		OutputPath:        outDir,
+	orgEvaluator := &cartPoleOrganismEvaluator{
 		RandomStart:       randomStart,
 		WinBalancingSteps: winBalanceSteps,
 	}
-}
-
-// GenerationEvaluate This method evaluates one epoch for given population and prints results into output directory if any.
-func (e *cartPoleGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiment.Generation, context *neat.Options) (err error) {
-	// Evaluate each organism on a test
-	for _, org := range pop.Organisms {
-		res, err := e.orgEvaluate(org)
-		if err != nil {
-			return err
-		}
-
-		if res && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
-			epoch.Solved = true
-			epoch.WinnerNodes = len(org.Genotype.Nodes)
-			epoch.WinnerGenes = org.Genotype.Extrons()
-			epoch.WinnerEvals = context.PopSize*epoch.Id + org.Genotype.Id
-			epoch.Best = org
-			if epoch.WinnerNodes == 7 {
-				// You could dump out optimal genomes here if desired
-				optPath := fmt.Sprintf("%s/%s_%d-%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId),
-					"pole1_optimal", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
-				if file, err := os.Create(optPath); err != nil {
-					return err
-				} else if err = org.Genotype.Write(file); err != nil {
-					neat.ErrorLog(fmt.Sprintf("Failed to dump optimal genome, reason: %s\n", err))
-					return err
-				} else {
-					neat.InfoLog(fmt.Sprintf("Dumped optimal genome to: %s\n", optPath))
-				}
-			}
-		}
-	}
-
-	// Fill statistics about current epoch
-	epoch.FillPopulationStatistics(pop)
-
-	// Only print to file every print_every generations
-	if epoch.Solved || epoch.Id%context.PrintEvery == 0 {
-		popPath := fmt.Sprintf("%s/gen_%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId), epoch.Id)
-		if file, err := os.Create(popPath); err != nil {
-			return err
-		} else if err = pop.WriteBySpecies(file); err != nil {
-			neat.ErrorLog(fmt.Sprintf("Failed to dump population, reason: %s\n", err))
-			return err
-		}
-	}
-
-	if epoch.Solved {
-		// print winner organism
-		for _, org := range pop.Organisms {
-			if org.IsWinner {
-				// Prints the winner organism to file!
-				orgPath := fmt.Sprintf("%s/%s_%d-%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId),
-					"pole1_winner", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
-				if file, err := os.Create(orgPath); err != nil {
-					return err
-				} else if err = org.Genotype.Write(file); err != nil {
-					neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
-					return err
-				} else {
-					neat.InfoLog(fmt.Sprintf("Generation #%d winner dumped to: %s\n", epoch.Id, orgPath))
-				}
-				break
-			}
-		}
+	workers := 0
+	if randomStart {
+		// the random start state is drawn from the random numbers generator of the trial, which is not safe for
+		// concurrent use, thus organisms should be evaluated sequentially in the population order
+		workers = 1
 	}
+	evaluator := experiment.NewParallelGenerationEvaluator(orgEvaluator, outDir, "pole1", workers)
+	// The optimal single pole balancer has no hidden nodes
+	evaluator.OptimalNodesCount = 7
+	return evaluator
+}
 
-	return err
+// OrganismEvaluate This methods evaluates provided organism for cart pole balancing task
+func (e *cartPoleOrganismEvaluator) OrganismEvaluate(organism *genetics.Organism, opts *neat.Options) (fitness, errValue float64, winner bool, err error) {
+	return e.OrganismEvaluateContext(opts.NeatContext(), organism)
 }
 
-// This methods evaluates provided organism for cart pole balancing task
-func (e *cartPoleGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool, error) {
+// OrganismEvaluateContext This methods evaluates provided organism for cart pole balancing task drawing the random
+// start state from the random numbers generator stored in the context
+func (e *cartPoleOrganismEvaluator) OrganismEvaluateContext(ctx context.Context, organism *genetics.Organism) (fitness, errValue float64, winner bool, err error) {
 	// Try to balance a pole now
-	if fitness, err := e.runCart(organism.Phenotype); err != nil {
-		return false, nil
+	if steps, err := e.runCart(neat.RandFromContext(ctx), organism.Phenotype); err != nil {
+		return 0, 1.0, false, nil
 	} else {
-		organism.Fitness = float64(fitness)
+		fitness = float64(steps)
 	}
 
 	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, organism.Fitness))
+		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, fitness))
 	}
 
 	// Decide if its a winner
-	if organism.Fitness >= float64(e.WinBalancingSteps) {
-		organism.IsWinner = true
-	}
+	winner = fitness >= float64(e.WinBalancingSteps)
 
 	// adjust fitness to be in range [0;1]
-	if organism.IsWinner {
-		organism.Fitness = 1.0
-		organism.Error = 0.0
-	} else if organism.Fitness == 0 {
-		organism.Error = 1.0
+	if winner {
+		fitness = 1.0
+		errValue = 0.0
+	} else if fitness == 0 {
+		errValue = 1.0
 	} else {
 		// we use logarithmic scale because most cart runs fail to early within ~100 steps, but
 		// we test against 500'000 balancing steps
 		logSteps := math.Log(float64(e.WinBalancingSteps))
-		organism.Error = (logSteps - math.Log(organism.Fitness)) / logSteps
-		organism.Fitness = 1.0 - organism.Error
+		errValue = (logSteps - math.Log(fitness)) / logSteps
+		fitness = 1.0 - errValue
 	}
 
-	return organism.IsWinner, nil
+	return fitness, errValue, winner, nil
 }
 
 // run cart emulation and return number of emulation steps pole was balanced
-func (e *cartPoleGenerationEvaluator) runCart(net *network.Network) (steps int, err error) {
+func (e *cartPoleOrganismEvaluator) runCart(rng *rand.Rand, net *network.Network) (steps int, err error) {
 	var x float64        /* cart position, meters */
 	var xDot float64     /* cart velocity */
 	var theta float64    /* pole angle, radians */
 	var thetaDot float64 /* pole angular velocity */
 	if e.RandomStart {
 		/*set up random start state*/
-		x = float64(rand.Int31()%4800)/1000.0 - 2.4
-		xDot = float64(rand.Int31()%2000)/1000.0 - 1
-		theta = float64(rand.Int31()%400)/1000.0 - .2
-		thetaDot = float64(rand.Int31()%3000)/1000.0 - 1.5
+		x = float64(rng.Int31()%4800)/1000.0 - 2.4
+		xDot = float64(rng.Int31()%2000)/1000.0 - 1
+		theta = float64(rng.Int31()%400)/1000.0 - .2
+		thetaDot = float64(rng.Int31()%3000)/1000.0 - 1.5
 	}
 
 	in := make([]float64, 5)
@@ -190,7 +133,7 @@ cart_pole:  Takes an action (0 or 1) and the current values of the
 four state variables and updates their values by estimating the state
 TAU seconds later.
 ----------------------------------------------------------------------*/
-func (e *cartPoleGenerationEvaluator) doAction(action int, x, xDot, theta, thetaDot float64) (xRet, xDotRet, thetaRet, thetaDotRet float64) {
+func (e *cartPoleOrganismEvaluator) doAction(action int, x, xDot, theta, thetaDot float64) (xRet, xDotRet, thetaRet, thetaDotRet float64) {
 	// The cart pole configuration values
 	const Gravity = 9.8
 	const MassCart = 1.0
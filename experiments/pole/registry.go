@@ -0,0 +1,84 @@
+package pole
+
+import (
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/experiment"
+)
+
+// The names of the pole balancing experiments in the experiments registry
+const (
+	CartPoleExperimentName                = "cart_pole"
+	CartDoublePoleMarkovExperimentName    = "cart_2pole_markov"
+	CartDoublePoleNonMarkovExperimentName = "cart_2pole_non-markov"
+)
+
+// The names of the action types used as evaluator parameter
+const (
+	continuousActionName = "continuous"
+	discreteActionName   = "discrete"
+)
+
+var actionTypeParameter = experiment.ParameterSchema{
+	Name:        "action_type",
+	Type:        experiment.ParameterTypeString,
+	Default:     continuousActionName,
+	Description: "The type of action to be applied to the cart",
+	Allowed:     []string{continuousActionName, discreteActionName},
+}
+
+func init() {
+	experiment.MustRegister(experiment.Registration{
+		Name:            CartPoleExperimentName,
+		Description:     "Evolves controller balancing single pole placed on the moving cart",
+		MaxFitnessScore: 1.0, // as given by fitness function definition
+		Parameters: []experiment.ParameterSchema{
+			{Name: "random_start", Type: experiment.ParameterTypeBool, Default: true,
+				Description: "Whether to start from random initial state of the cart-pole system"},
+			{Name: "win_balance_steps", Type: experiment.ParameterTypeInt, Default: 500000,
+				Description: "The number of time steps to balance the pole to win"},
+		},
+		NewEvaluator: func(config experiment.EvaluatorConfig, outDir string) (experiment.GenerationEvaluator, error) {
+			randomStart, err := config.Bool("random_start", true)
+			if err != nil {
+				return nil, err
+			}
+			winBalanceSteps, err := config.Int("win_balance_steps", 500000)
+			if err != nil {
+				return nil, err
+			}
+			return NewCartPoleGenerationEvaluator(outDir, randomStart, winBalanceSteps), nil
+		},
+	})
+	experiment.MustRegister(experiment.Registration{
+		Name:            CartDoublePoleMarkovExperimentName,
+		Description:     "Evolves controller balancing two poles on the moving cart with velocities provided as inputs (Markovian)",
+		MaxFitnessScore: 1.0, // as given by fitness function definition
+		Parameters:      []experiment.ParameterSchema{actionTypeParameter},
+		NewEvaluator:    newCartDoublePoleEvaluatorFactory(true),
+	})
+	experiment.MustRegister(experiment.Registration{
+		Name:         CartDoublePoleNonMarkovExperimentName,
+		Description:  "Evolves controller balancing two poles on the moving cart without velocities information (non-Markovian)",
+		Parameters:   []experiment.ParameterSchema{actionTypeParameter},
+		NewEvaluator: newCartDoublePoleEvaluatorFactory(false),
+	})
+}
+
+func newCartDoublePoleEvaluatorFactory(markov bool) experiment.RegisteredEvaluatorFactory {
+	return func(config experiment.EvaluatorConfig, outDir string) (experiment.GenerationEvaluator, error) {
+		actionName, err := config.String(actionTypeParameter.Name, continuousActionName)
+		if err != nil {
+			return nil, err
+		}
+		var actionType ActionType
+		switch actionName {
+		case continuousActionName:
+			actionType = ContinuousAction
+		case discreteActionName:
+			actionType = DiscreteAction
+		default:
+			return nil, errors.Errorf("unsupported action type: [%s]", actionName)
+		}
+		return NewCartDoublePoleGenerationEvaluator(outDir, markov, actionType), nil
+	}
+}
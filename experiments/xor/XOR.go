@@ -12,16 +12,12 @@ import (
 	"github.com/yaricom/goNEAT/v2/neat"
 	"github.com/yaricom/goNEAT/v2/neat/genetics"
 	"math"
-	"os"
 )
 
 // The fitness threshold value for successful solver
 const fitnessThreshold = 15.5
 
-type xorGenerationEvaluator struct {
-	// The output path to store execution results
-	OutputPath string
-}
+type xorOrganismEvaluator struct{}
 
 // NewXORGenerationEvaluator is to create new generations evaluator to be used for the XOR experiment execution.
 // XOR is very simple and does not make a very interesting scientific experiment; however, it is a good way to
@@ -34,79 +30,14 @@ type xorGenerationEvaluator struct {
 // This method performs evolution on XOR for specified number of generations and output results into outDirPath
 // It also returns number of nodes, genes, and evaluations performed per each run (context.NumRuns)
 func NewXORGenerationEvaluator(outputPath string) experiment.GenerationEvaluator {
-	return &xorGenerationEvaluator{OutputPath: outputPath}
-}
-
-// GenerationEvaluate This method evaluates one epoch for given population and prints results into output directory if any.
-func (e *xorGenerationEvaluator) GenerationEvaluate(pop *genetics.Population, epoch *experiment.Generation, context *neat.Options) (err error) {
-	// Evaluate each organism on a test
-	for _, org := range pop.Organisms {
-		res, err := e.orgEvaluate(org)
-		if err != nil {
-			return err
-		}
-
-		if res && (epoch.Best == nil || org.Fitness > epoch.Best.Fitness) {
-			epoch.Solved = true
-			epoch.WinnerNodes = len(org.Genotype.Nodes)
-			epoch.WinnerGenes = org.Genotype.Extrons()
-			epoch.WinnerEvals = context.PopSize*epoch.Id + org.Genotype.Id
-			epoch.Best = org
-			if epoch.WinnerNodes == 5 {
-				// You could dump out optimal genomes here if desired
-				optPath := fmt.Sprintf("%s/%s_%d-%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId),
-					"xor_optimal", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
-				if file, err := os.Create(optPath); err != nil {
-					return err
-				} else if err = org.Genotype.Write(file); err != nil {
-					neat.ErrorLog(fmt.Sprintf("Failed to dump optimal genome, reason: %s\n", err))
-					return err
-				} else {
-					neat.InfoLog(fmt.Sprintf("Dumped optimal genome to: %s\n", optPath))
-				}
-			}
-		}
-	}
-
-	// Fill statistics about current epoch
-	epoch.FillPopulationStatistics(pop)
-
-	// Only print to file every print_every generations
-	if epoch.Solved || epoch.Id%context.PrintEvery == 0 {
-		popPath := fmt.Sprintf("%s/gen_%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId), epoch.Id)
-		if file, err := os.Create(popPath); err != nil {
-			return err
-		} else if err = pop.WriteBySpecies(file); err != nil {
-			neat.ErrorLog(fmt.Sprintf("Failed to dump population, reason: %s\n", err))
-			return err
-		}
-	}
-
-	if epoch.Solved {
-		// print winner organism
-		for _, org := range pop.Organisms {
-			if org.IsWinner {
-				// Prints the winner organism to file!
-				orgPath := fmt.Sprintf("%s/%s_%d-%d", experiment.OutDirForTrial(e.OutputPath, epoch.TrialId),
-					"xor_winner", org.Phenotype.NodeCount(), org.Phenotype.LinkCount())
-				if file, err := os.Create(orgPath); err != nil {
-					return err
-				} else if err = org.Genotype.Write(file); err != nil {
-					neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism genome, reason: %s\n", err))
-					return err
-				} else {
-					neat.InfoLog(fmt.Sprintf("Generation #%d winner dumped to: %s\n", epoch.Id, orgPath))
-				}
-				break
-			}
-		}
-	}
-
-	return err
+	evaluator := experiment.NewParallelGenerationEvaluator(&xorOrganismEvaluator{}, outputPath, "xor", 0)
+	// The optimal XOR solver has one hidden node
+	evaluator.OptimalNodesCount = 5
+	return evaluator
 }
 
-// This methods evaluates provided organism
-func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool, error) {
+// OrganismEvaluate This methods evaluates provided organism
+func (e *xorOrganismEvaluator) OrganismEvaluate(organism *genetics.Organism, _ *neat.Options) (fitness, errValue float64, winner bool, err error) {
 	// The four possible input combinations to xor
 	// The first number is for biasing
 	in := [][]float64{
@@ -133,14 +64,14 @@ func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool,
 	for count := 0; count < 4; count++ {
 		if err := organism.Phenotype.LoadSensors(in[count]); err != nil {
 			neat.ErrorLog("Failed to load sensors")
-			return false, err
+			return 0, 0, false, err
 		}
 
 		// Relax net and get output
 		success, err = organism.Phenotype.Activate()
 		if err != nil {
 			neat.ErrorLog("Failed to activate network")
-			return false, err
+			return 0, 0, false, err
 		}
 
 		// use depth to ensure relaxation
@@ -148,14 +79,14 @@ func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool,
 			success, err = organism.Phenotype.Activate()
 			if err != nil {
 				neat.ErrorLog("Failed to activate network")
-				return false, err
+				return 0, 0, false, err
 			}
 		}
 		out[count] = organism.Phenotype.Outputs[0].Activation
 
 		if _, err := organism.Phenotype.Flush(); err != nil {
 			neat.ErrorLog("Failed to flush network")
-			return false, err
+			return 0, 0, false, err
 		}
 	}
 
@@ -163,20 +94,17 @@ func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool,
 		// Mean Squared Error
 		errorSum := math.Abs(out[0]) + math.Abs(1.0-out[1]) + math.Abs(1.0-out[2]) + math.Abs(out[3]) // ideal == 0
 		target := 4.0 - errorSum                                                                      // ideal == 4.0
-		organism.Fitness = math.Pow(4.0-errorSum, 2.0)
-		organism.Error = math.Pow(4.0-target, 2.0)
+		fitness = math.Pow(4.0-errorSum, 2.0)
+		errValue = math.Pow(4.0-target, 2.0)
 	} else {
 		// The network is flawed (shouldn't happen) - flag as anomaly
-		organism.Error = 1.0
-		organism.Fitness = 0.0
+		errValue = 1.0
+		fitness = 0.0
 	}
 
-	if organism.Fitness > fitnessThreshold {
-		organism.IsWinner = true
+	if fitness > fitnessThreshold {
+		winner = true
 		neat.InfoLog(fmt.Sprintf(">>>> Output activations: %e\n", out))
-
-	} else {
-		organism.IsWinner = false
 	}
-	return organism.IsWinner, nil
+	return fitness, errValue, winner, nil
 }
@@ -0,0 +1,17 @@
+package xor
+
+import "github.com/yaricom/goNEAT/v2/experiment"
+
+// ExperimentName the name of the XOR experiment in the experiments registry
+const ExperimentName = "XOR"
+
+func init() {
+	experiment.MustRegister(experiment.Registration{
+		Name:            ExperimentName,
+		Description:     "Evolves network approximating the XOR function, the basic check that the network topology evolves",
+		MaxFitnessScore: 16.0, // as given by fitness function definition
+		NewEvaluator: func(_ experiment.EvaluatorConfig, outDir string) (experiment.GenerationEvaluator, error) {
+			return NewXORGenerationEvaluator(outDir), nil
+		},
+	})
+}
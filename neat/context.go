@@ -3,6 +3,8 @@ package neat
 import (
 	"context"
 	"errors"
+	"github.com/yaricom/goNEAT/v2/neat/math"
+	"math/rand"
 )
 
 var ErrNEATOptionsNotFound = errors.New("NEAT options not found in the context")
@@ -16,6 +18,12 @@ type key int
 // instead of using this key directly.
 var neatOptionsKey key
 
+// loggerKey is the key for Logger values in Contexts.
+var loggerKey key = 1
+
+// randKey is the key for the generator of random numbers in Contexts.
+var randKey key = 2
+
 // NewContext returns a new Context that carries value of NEAT options.
 func NewContext(ctx context.Context, opts *Options) context.Context {
 	return context.WithValue(ctx, neatOptionsKey, opts)
@@ -26,3 +34,31 @@ func FromContext(ctx context.Context) (*Options, bool) {
 	u, ok := ctx.Value(neatOptionsKey).(*Options)
 	return u, ok
 }
+
+// NewLoggerContext returns a new Context that carries provided logger.
+func NewLoggerContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// LoggerFromContext returns the Logger stored in ctx or the DefaultLogger if not found.
+func LoggerFromContext(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(loggerKey).(*Logger); ok && logger != nil {
+		return logger
+	}
+	return DefaultLogger()
+}
+
+// NewRandContext returns a new Context that carries provided generator of random numbers. The generator is not safe
+// for concurrent use, thus it should not be shared between goroutines.
+func NewRandContext(ctx context.Context, rng *rand.Rand) context.Context {
+	return context.WithValue(ctx, randKey, rng)
+}
+
+// RandFromContext returns the generator of random numbers stored in ctx or the generator backed by the global
+// generator of the math/rand package if not found.
+func RandFromContext(ctx context.Context) *rand.Rand {
+	if rng, ok := ctx.Value(randKey).(*rand.Rand); ok && rng != nil {
+		return rng
+	}
+	return math.GlobalRand()
+}
@@ -0,0 +1,90 @@
+package genetics
+
+import "time"
+
+// MutationCounts holds the numbers of mutations applied to the offspring during reproduction by type
+type MutationCounts struct {
+	// The number of new nodes added
+	AddNode int `json:"add_node"`
+	// The number of new links added
+	AddLink int `json:"add_link"`
+	// The number of links added to connect disconnected sensors
+	ConnectSensors int `json:"connect_sensors"`
+	// The number of link weights only mutations applied to the super champion offspring
+	LinkWeights int `json:"link_weights"`
+	// The number of non-structural mutations (traits, weights, enable flags) applied
+	NonStructural int `json:"non_structural"`
+}
+
+// Total Returns the total number of mutations
+func (c MutationCounts) Total() int {
+	return c.AddNode + c.AddLink + c.ConnectSensors + c.LinkWeights + c.NonStructural
+}
+
+func (c *MutationCounts) add(other MutationCounts) {
+	c.AddNode += other.AddNode
+	c.AddLink += other.AddLink
+	c.ConnectSensors += other.ConnectSensors
+	c.LinkWeights += other.LinkWeights
+	c.NonStructural += other.NonStructural
+}
+
+// MatingCounts holds the numbers of matings performed during reproduction by type
+type MatingCounts struct {
+	// The number of multipoint matings
+	Multipoint int `json:"multipoint"`
+	// The number of multipoint matings with averaging of the matching genes
+	MultipointAvg int `json:"multipoint_avg"`
+	// The number of single point matings
+	SinglePoint int `json:"single_point"`
+	// The number of matings with the organism from other species, counted additionally to the mating type
+	Interspecies int `json:"interspecies"`
+}
+
+// Total Returns the total number of matings
+func (c MatingCounts) Total() int {
+	return c.Multipoint + c.MultipointAvg + c.SinglePoint
+}
+
+func (c *MatingCounts) add(other MatingCounts) {
+	c.Multipoint += other.Multipoint
+	c.MultipointAvg += other.MultipointAvg
+	c.SinglePoint += other.SinglePoint
+	c.Interspecies += other.Interspecies
+}
+
+// EpochStatistics holds the timings of the phases of the population's epoch and the counters of the genetic operators
+// applied during reproduction.
+type EpochStatistics struct {
+	// The duration of the fitness adjustment and offspring assignment phase
+	FitnessAdjustment time.Duration `json:"fitness_adjustment_ns"`
+	// The duration of the reproduction phase of all species
+	Reproduction time.Duration `json:"reproduction_ns"`
+	// The duration of the speciation of the offspring
+	Speciation time.Duration `json:"speciation_ns"`
+	// The durations of reproduction of each species by species ID. When species are reproduced in parallel, the
+	// sum of durations can exceed the duration of the reproduction phase.
+	SpeciesReproduction map[int]time.Duration `json:"species_reproduction_ns,omitempty"`
+
+	// The numbers of mutations by type
+	Mutations MutationCounts `json:"mutations"`
+	// The numbers of matings by type
+	Matings MatingCounts `json:"matings"`
+}
+
+// resetEpochStats Clears statistics of the previous epoch
+func (p *Population) resetEpochStats() {
+	p.EpochStats = EpochStatistics{SpeciesReproduction: make(map[int]time.Duration)}
+}
+
+// recordSpeciesReproduction Stores statistics of the species reproduction. It is safe for concurrent use.
+func (p *Population) recordSpeciesReproduction(speciesId int, duration time.Duration, mutations MutationCounts, matings MatingCounts) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.EpochStats.SpeciesReproduction == nil {
+		p.EpochStats.SpeciesReproduction = make(map[int]time.Duration)
+	}
+	p.EpochStats.SpeciesReproduction[speciesId] += duration
+	p.EpochStats.Mutations.add(mutations)
+	p.EpochStats.Matings.add(matings)
+}
@@ -66,7 +66,7 @@ func NewModularGenome(id int, t []*neat.Trait, n []*network.NNode, g []*Gene, mi
 // This special constructor creates a Genome with in inputs, out outputs, n out of maxHidden hidden units, and random
 // connectivity.  If rec is true then recurrent connections will be included. The last input is a bias
 // link_prob is the probability of a link. The created genome is not modular.
-func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb float64) *Genome {
+func newGenomeRand(rng *rand.Rand, newId, in, out, n, maxHidden int, recurrent bool, linkProb float64) *Genome {
 	totalNodes := in + out + maxHidden
 	matrixDim := totalNodes * totalNodes
 	// The connection matrix which will be randomized
@@ -91,7 +91,7 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 
 	// Step through the connection matrix, randomly assigning bits
 	for count := 0; count < matrixDim; count++ {
-		cm[count] = rand.Float64() < linkProb
+		cm[count] = rng.Float64() < linkProb
 	}
 
 	// Build the input nodes
@@ -169,7 +169,7 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 					}
 
 					// Create the gene
-					weight := float64(math.RandSign()) * rand.Float64()
+					weight := float64(math.RandSignWith(rng)) * rng.Float64()
 					gene := NewGeneWithTrait(newTrait, weight, inNode, outNode, flagRecurrent, int64(count), weight)
 
 					//Add the gene to the genome
@@ -370,6 +370,39 @@ func (g *Genome) hasGene(gene *Gene) bool {
 
 // Genesis generates a Network phenotype from this Genome with specified id
 func (g *Genome) Genesis(netId int) (*network.Network, error) {
+	newNet, err := g.buildPhenotype(netId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Have the node specifiers point to the nodes they generated
+	for i, n := range g.Nodes {
+		n.PhenotypeAnalogue = newNet.AllNodes()[i]
+	}
+
+	// Attach genotype and phenotype together:
+	// genotype points to owner phenotype (new_net)
+	g.Phenotype = newNet
+
+	return newNet, nil
+}
+
+// GenesisPruned generates a Network phenotype from this Genome with specified id and removes dead structure from it,
+// i.e. the hidden nodes which can not reach any output and the hidden nodes with no path from any input. This Genome
+// stays unchanged: the created phenotype is not attached to it. The outputs of created network are the same when it is
+// activated by the standard network solver, use Network.PrunedFastNetworkSolver to get the pruned fast solver. Returns
+// created network and report about removed nodes and links.
+func (g *Genome) GenesisPruned(netId int) (*network.Network, *network.PruningReport, error) {
+	newNet, err := g.buildPhenotype(netId)
+	if err != nil {
+		return nil, nil, err
+	}
+	report := newNet.Prune()
+	return newNet, report, nil
+}
+
+// buildPhenotype builds the Network phenotype from this Genome without attaching it to the genome
+func (g *Genome) buildPhenotype(netId int) (*network.Network, error) {
 	// Inputs and outputs will be collected here for the network.
 	// All nodes are collected in an all_list -
 	// this is useful for network traversing routines
@@ -377,6 +410,9 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 	outList := make([]*network.NNode, 0)
 	allList := make([]*network.NNode, 0)
 
+	// The lookup table of generated nodes: node specifier id -> phenotype node
+	phenotypeNodes := make(map[int]*network.NNode, len(g.Nodes))
+
 	var newNode *network.NNode
 	// Create the network nodes
 	for _, n := range g.Nodes {
@@ -392,8 +428,8 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 		// Keep track of all nodes in one place for convenience
 		allList = append(allList, newNode)
 
-		// Have the node specifier point to the node it generated
-		n.PhenotypeAnalogue = newNode
+		// Keep track of the node specifier and the node it generated
+		phenotypeNodes[n.Id] = newNode
 	}
 
 	if len(g.Genes) == 0 {
@@ -406,13 +442,18 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 
 	var inNode, outNode *network.NNode
 	var curLink, newLink *network.Link
+	var ok bool
 	// Create the links by iterating through the genes
 	for _, gn := range g.Genes {
 		// Only create the link if the gene is enabled
 		if gn.IsEnabled {
 			curLink = gn.Link
-			inNode = curLink.InNode.PhenotypeAnalogue
-			outNode = curLink.OutNode.PhenotypeAnalogue
+			if inNode, ok = phenotypeNodes[curLink.InNode.Id]; !ok {
+				return nil, fmt.Errorf("missing input node: %d of gene: %s", curLink.InNode.Id, gn)
+			}
+			if outNode, ok = phenotypeNodes[curLink.OutNode.Id]; !ok {
+				return nil, fmt.Errorf("missing output node: %d of gene: %s", curLink.OutNode.Id, gn)
+			}
 
 			// NOTE: This line could be run through a recurrence check if desired
 			// (no need to in the current implementation of NEAT)
@@ -438,7 +479,9 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 
 				// connect inputs
 				for _, l := range cg.ControlNode.Incoming {
-					inNode = l.InNode.PhenotypeAnalogue
+					if inNode, ok = phenotypeNodes[l.InNode.Id]; !ok {
+						return nil, fmt.Errorf("missing input node: %d of control gene: %s", l.InNode.Id, cg)
+					}
 					outNode = newCopyNode
 					newLink = network.NewLink(l.Weight, inNode, outNode, false)
 					// only incoming to control node
@@ -448,7 +491,9 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 				// connect outputs
 				for _, l := range cg.ControlNode.Outgoing {
 					inNode = newCopyNode
-					outNode = l.OutNode.PhenotypeAnalogue
+					if outNode, ok = phenotypeNodes[l.OutNode.Id]; !ok {
+						return nil, fmt.Errorf("missing output node: %d of control gene: %s", l.OutNode.Id, cg)
+					}
 					newLink = network.NewLink(l.Weight, inNode, outNode, false)
 					// only outgoing from control node
 					inNode.Outgoing = append(inNode.Outgoing, newLink)
@@ -461,10 +506,6 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 		newNet = network.NewModularNetwork(inList, outList, allList, cNodes, netId)
 	}
 
-	// Attach genotype and phenotype together:
-	// genotype points to owner phenotype (new_net)
-	g.Phenotype = newNet
-
 	return newNet, nil
 }
 
@@ -0,0 +1,437 @@
+package genetics
+
+import (
+	"errors"
+	"fmt"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/math"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"math/rand"
+)
+
+// Connectivity defines how the nodes of the layer are connected with the nodes of the previous layer
+type Connectivity byte
+
+const (
+	// FullConnectivity each node of the layer is connected with each node of the previous layer and the bias
+	FullConnectivity Connectivity = iota
+	// SparseConnectivity each link of the full connectivity is created with the layer's link probability
+	SparseConnectivity
+	// NoConnectivity the nodes of the layer are not connected with the previous layer, e.g., when they are fed by the
+	// MIMO module, or the links are expected to be evolved
+	NoConnectivity
+)
+
+// WeightDistribution samples the initial weight of the connection gene using provided source of randomness
+type WeightDistribution func(rng *rand.Rand) float64
+
+// ConstantWeights Returns distribution which assigns the same weight to all connections
+func ConstantWeights(weight float64) WeightDistribution {
+	return func(_ *rand.Rand) float64 {
+		return weight
+	}
+}
+
+// UniformWeights Returns distribution which samples weights uniformly from the [min, max) range
+func UniformWeights(min, max float64) WeightDistribution {
+	return func(rng *rand.Rand) float64 {
+		return min + rng.Float64()*(max-min)
+	}
+}
+
+// NormalWeights Returns distribution which samples weights from the normal distribution with given mean and standard
+// deviation
+func NormalWeights(mean, stdDev float64) WeightDistribution {
+	return func(rng *rand.Rand) float64 {
+		return mean + rng.NormFloat64()*stdDev
+	}
+}
+
+// Layer the specification of the hidden or output layer of the genome built by GenomeBuilder
+type Layer struct {
+	// The number of nodes in the layer
+	Size int
+	// The activation function of the layer nodes. The SigmoidSteepenedActivation is used if not set.
+	Activation math.NodeActivationType
+	// The connectivity with the previous layer
+	Connectivity Connectivity
+	// The probability to create each link if SparseConnectivity used
+	LinkProbability float64
+	// The distribution of the initial weights of the incoming links. The builder's distribution is used if not set.
+	Weights WeightDistribution
+	// The ID of the trait associated with the layer nodes and their incoming links. The first trait is used if not set.
+	TraitId int
+}
+
+// Module the specification of the MIMO control gene of the genome built by GenomeBuilder
+type Module struct {
+	// The module activation function, e.g., MultiplyModuleActivation
+	Activation math.NodeActivationType
+	// The IDs of the nodes providing inputs to the module
+	Inputs []int
+	// The IDs of the nodes receiving outputs of the module
+	Outputs []int
+	// The ID of the trait associated with the control node. The first trait is used if not set.
+	TraitId int
+}
+
+// GenomeBuilder builds seed genomes from the declared layers of nodes. The nodes IDs are assigned in the order: bias
+// (if any), inputs, outputs, hidden layers in order of declaration, and the control nodes of the MIMO modules. Each
+// hidden layer is connected with the previous one (the inputs for the first), and the outputs are connected with the
+// last hidden layer. The bias is connected to every node of each connected layer.
+//
+// The innovation numbers are assigned to the connection genes by their position among all possible links of the
+// declared layers, i.e., the same link has the same innovation number in all genomes built from the same declaration,
+// even when sparse connectivity is used. The MIMO modules get the innovation numbers following all possible links.
+//
+// The errors of declaration are collected and returned by Build, which also validates the built genome:
+//
+//	genome, err := genetics.NewGenomeBuilder(1).
+//		Inputs(2).
+//		Bias().
+//		HiddenLayer(genetics.Layer{Size: 3, Activation: math.TanhActivation}).
+//		Outputs(genetics.Layer{Size: 1, Connectivity: genetics.SparseConnectivity, LinkProbability: 0.5}).
+//		Weights(genetics.UniformWeights(-1, 1)).
+//		Build()
+type GenomeBuilder struct {
+	id      int
+	inputs  int
+	bias    bool
+	outputs Layer
+	hidden  []Layer
+	modules []Module
+	traits  []*neat.Trait
+	weights WeightDistribution
+	rng     *rand.Rand
+	errs    []error
+}
+
+// NewGenomeBuilder Creates new builder of genome with given ID. The initial weights are sampled uniformly from
+// [-1, 1) range using the source of randomness seeded from the global one, unless set otherwise.
+func NewGenomeBuilder(id int) *GenomeBuilder {
+	return &GenomeBuilder{
+		id:      id,
+		weights: UniformWeights(-1, 1),
+		rng:     rand.New(rand.NewSource(rand.Int63())),
+	}
+}
+
+// Inputs Declares the number of input sensors
+func (b *GenomeBuilder) Inputs(count int) *GenomeBuilder {
+	b.inputs = count
+	return b
+}
+
+// Bias Declares the bias sensor
+func (b *GenomeBuilder) Bias() *GenomeBuilder {
+	b.bias = true
+	return b
+}
+
+// Outputs Declares the output layer
+func (b *GenomeBuilder) Outputs(layer Layer) *GenomeBuilder {
+	b.outputs = layer
+	return b
+}
+
+// HiddenLayer Declares the next hidden layer
+func (b *GenomeBuilder) HiddenLayer(layer Layer) *GenomeBuilder {
+	b.hidden = append(b.hidden, layer)
+	return b
+}
+
+// Weights Sets the default distribution of the initial weights of connections
+func (b *GenomeBuilder) Weights(weights WeightDistribution) *GenomeBuilder {
+	if weights == nil {
+		b.errs = append(b.errs, errors.New("weights distribution is not set"))
+	}
+	b.weights = weights
+	return b
+}
+
+// Rand Sets the source of randomness used to sample weights and sparse links
+func (b *GenomeBuilder) Rand(rng *rand.Rand) *GenomeBuilder {
+	if rng == nil {
+		b.errs = append(b.errs, errors.New("source of randomness is not set"))
+	}
+	b.rng = rng
+	return b
+}
+
+// Trait Declares new trait with given parameters. The traits get IDs in order of declaration starting from 1. If no
+// traits declared, the single trait with default parameters is created.
+func (b *GenomeBuilder) Trait(params ...float64) *GenomeBuilder {
+	trait := neat.NewTrait()
+	trait.Id = len(b.traits) + 1
+	if len(params) > len(trait.Params) {
+		b.errs = append(b.errs, fmt.Errorf("too many parameters of trait %d: %d, maximum: %d",
+			trait.Id, len(params), len(trait.Params)))
+	} else {
+		copy(trait.Params, params)
+	}
+	b.traits = append(b.traits, trait)
+	return b
+}
+
+// Module Declares the MIMO module connecting nodes with given IDs, see BiasId, InputIds, OutputIds, and HiddenIds
+func (b *GenomeBuilder) Module(module Module) *GenomeBuilder {
+	b.modules = append(b.modules, module)
+	return b
+}
+
+// BiasId Returns the ID of the bias node or zero if bias not declared
+func (b *GenomeBuilder) BiasId() int {
+	if b.bias {
+		return 1
+	}
+	return 0
+}
+
+// InputIds Returns the IDs of the input nodes
+func (b *GenomeBuilder) InputIds() []int {
+	return idsRange(b.BiasId()+1, b.inputs)
+}
+
+// OutputIds Returns the IDs of the output nodes
+func (b *GenomeBuilder) OutputIds() []int {
+	return idsRange(b.BiasId()+b.inputs+1, b.outputs.Size)
+}
+
+// HiddenIds Returns the IDs of the nodes of the hidden layer with given index in order of declaration
+func (b *GenomeBuilder) HiddenIds(layer int) []int {
+	if layer < 0 || layer >= len(b.hidden) {
+		return nil
+	}
+	first := b.BiasId() + b.inputs + b.outputs.Size + 1
+	for _, l := range b.hidden[:layer] {
+		first += l.Size
+	}
+	return idsRange(first, b.hidden[layer].Size)
+}
+
+// Build Builds and validates the genome according to declaration
+func (b *GenomeBuilder) Build() (*Genome, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	traits := b.traits
+	if len(traits) == 0 {
+		trait := neat.NewTrait()
+		trait.Id = 1
+		traits = []*neat.Trait{trait}
+	}
+	genome := NewGenome(b.id, traits, make([]*network.NNode, 0), make([]*Gene, 0))
+
+	// create nodes
+	var bias *network.NNode
+	if b.bias {
+		bias = b.newNode(genome, b.BiasId(), network.BiasNeuron, 0, 0)
+	}
+	inputs := make([]*network.NNode, b.inputs)
+	for i, id := range b.InputIds() {
+		inputs[i] = b.newNode(genome, id, network.InputNeuron, 0, 0)
+	}
+	outputs := make([]*network.NNode, b.outputs.Size)
+	for i, id := range b.OutputIds() {
+		outputs[i] = b.newNode(genome, id, network.OutputNeuron, b.outputs.Activation, b.outputs.TraitId)
+	}
+	hidden := make([][]*network.NNode, len(b.hidden))
+	for l, layer := range b.hidden {
+		hidden[l] = make([]*network.NNode, layer.Size)
+		for i, id := range b.HiddenIds(l) {
+			hidden[l][i] = b.newNode(genome, id, network.HiddenNeuron, layer.Activation, layer.TraitId)
+		}
+	}
+
+	// connect layers
+	var innovation int64
+	sources := inputs
+	for l, layer := range b.layers() {
+		targets := outputs
+		if l < len(hidden) {
+			targets = hidden[l]
+		}
+		layerSources := sources
+		if bias != nil {
+			layerSources = append([]*network.NNode{bias}, sources...)
+		}
+		weights := layer.Weights
+		if weights == nil {
+			weights = b.weights
+		}
+		trait := TraitWithId(traitIdOrFirst(layer.TraitId), genome.Traits)
+		for _, target := range targets {
+			for _, source := range layerSources {
+				innovation++
+				if layer.Connectivity == NoConnectivity ||
+					layer.Connectivity == SparseConnectivity && b.rng.Float64() >= layer.LinkProbability {
+					continue
+				}
+				weight := weights(b.rng)
+				genome.Genes = append(genome.Genes,
+					NewGeneWithTrait(trait, weight, source, target, false, innovation, weight))
+			}
+		}
+		sources = targets
+	}
+
+	// create MIMO modules
+	nextNodeId := b.BiasId() + b.inputs + b.outputs.Size
+	for _, layer := range b.hidden {
+		nextNodeId += layer.Size
+	}
+	for _, module := range b.modules {
+		nextNodeId++
+		innovation++
+		controlNode := network.NewNNode(nextNodeId, network.HiddenNeuron)
+		controlNode.ActivationType = module.Activation
+		controlNode.Trait = TraitWithId(traitIdOrFirst(module.TraitId), genome.Traits)
+		for _, id := range module.Inputs {
+			controlNode.Incoming = append(controlNode.Incoming,
+				network.NewLink(1.0, NodeWithId(id, genome.Nodes), controlNode, false))
+		}
+		for _, id := range module.Outputs {
+			controlNode.Outgoing = append(controlNode.Outgoing,
+				network.NewLink(1.0, controlNode, NodeWithId(id, genome.Nodes), false))
+		}
+		genome.ControlGenes = append(genome.ControlGenes, NewMIMOGene(controlNode, innovation, 0, true))
+	}
+
+	if len(genome.Genes) == 0 {
+		return nil, errors.New("genome has no connection genes, at least one layer must be connected")
+	}
+	if _, err := genome.verify(); err != nil {
+		return nil, err
+	}
+	if _, err := genome.Genesis(genome.Id); err != nil {
+		return nil, fmt.Errorf("failed to build phenotype of genome: %s", err)
+	}
+	return genome, nil
+}
+
+// validate Checks the declaration of the genome and returns all found errors
+func (b *GenomeBuilder) validate() error {
+	errs := append([]error{}, b.errs...)
+	if b.inputs <= 0 {
+		errs = append(errs, fmt.Errorf("at least one input expected, found: %d", b.inputs))
+	}
+	if b.outputs.Size <= 0 {
+		errs = append(errs, fmt.Errorf("at least one output expected, found: %d", b.outputs.Size))
+	}
+	traitsCount := len(b.traits)
+	if traitsCount == 0 {
+		traitsCount = 1
+	}
+	for l, layer := range b.layers() {
+		name := "output layer"
+		if l < len(b.hidden) {
+			name = fmt.Sprintf("hidden layer %d", l)
+			if layer.Size <= 0 {
+				errs = append(errs, fmt.Errorf("%s: at least one node expected, found: %d", name, layer.Size))
+			}
+		}
+		if layer.Connectivity > NoConnectivity {
+			errs = append(errs, fmt.Errorf("%s: unknown connectivity: %d", name, layer.Connectivity))
+		}
+		if layer.Connectivity == SparseConnectivity && (layer.LinkProbability <= 0 || layer.LinkProbability > 1) {
+			errs = append(errs, fmt.Errorf("%s: link probability must be in (0, 1] range, found: %g",
+				name, layer.LinkProbability))
+		}
+		if err := checkActivation(layer.Activation, false); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", name, err))
+		}
+		if layer.TraitId < 0 || layer.TraitId > traitsCount {
+			errs = append(errs, fmt.Errorf("%s: unknown trait ID: %d", name, layer.TraitId))
+		}
+	}
+
+	nodesCount := b.BiasId() + b.inputs + b.outputs.Size
+	for _, layer := range b.hidden {
+		nodesCount += layer.Size
+	}
+	for m, module := range b.modules {
+		if len(module.Inputs) == 0 || len(module.Outputs) == 0 {
+			errs = append(errs, fmt.Errorf("module %d: at least one input and one output expected", m))
+		}
+		if err := checkActivation(module.Activation, true); err != nil {
+			errs = append(errs, fmt.Errorf("module %d: %s", m, err))
+		}
+		if module.TraitId < 0 || module.TraitId > traitsCount {
+			errs = append(errs, fmt.Errorf("module %d: unknown trait ID: %d", m, module.TraitId))
+		}
+		for _, id := range module.Inputs {
+			if id <= 0 || id > nodesCount {
+				errs = append(errs, fmt.Errorf("module %d: unknown input node ID: %d", m, id))
+			}
+		}
+		for _, id := range module.Outputs {
+			if id <= 0 || id > nodesCount {
+				errs = append(errs, fmt.Errorf("module %d: unknown output node ID: %d", m, id))
+			} else if id <= b.BiasId()+b.inputs {
+				errs = append(errs, fmt.Errorf("module %d: sensor node ID: %d can not be output of module", m, id))
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	msg := "invalid genome declaration:"
+	for _, err := range errs {
+		msg += "\n\t" + err.Error()
+	}
+	return errors.New(msg)
+}
+
+// layers Returns the hidden layers followed by the output layer
+func (b *GenomeBuilder) layers() []Layer {
+	layers := make([]Layer, 0, len(b.hidden)+1)
+	return append(append(layers, b.hidden...), b.outputs)
+}
+
+// newNode Creates new node and adds it to the genome
+func (b *GenomeBuilder) newNode(genome *Genome, id int, neuronType network.NodeNeuronType,
+	activation math.NodeActivationType, traitId int) *network.NNode {
+	node := network.NewNNode(id, neuronType)
+	if activation != 0 {
+		node.ActivationType = activation
+	}
+	node.Trait = TraitWithId(traitIdOrFirst(traitId), genome.Traits)
+	genome.Nodes = append(genome.Nodes, node)
+	return node
+}
+
+// checkActivation Checks that activation type is either not set or known neuron (or module) activation function
+func checkActivation(activation math.NodeActivationType, module bool) error {
+	if activation == 0 {
+		if module {
+			return errors.New("activation function is not set")
+		}
+		return nil
+	}
+	var err error
+	if module {
+		_, err = math.NodeActivators.ActivateModuleByType([]float64{0}, nil, activation)
+	} else {
+		_, err = math.NodeActivators.ActivateByType(0, nil, activation)
+	}
+	return err
+}
+
+func traitIdOrFirst(traitId int) int {
+	if traitId == 0 {
+		return 1
+	}
+	return traitId
+}
+
+func idsRange(first, count int) []int {
+	if count <= 0 {
+		return nil
+	}
+	ids := make([]int, count)
+	for i := range ids {
+		ids[i] = first + i
+	}
+	return ids
+}
@@ -0,0 +1,211 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/math"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"math/rand"
+	"testing"
+)
+
+func TestGenomeBuilder_Build_xor(t *testing.T) {
+	genome, err := NewGenomeBuilder(1).
+		Inputs(2).
+		Bias().
+		Outputs(Layer{Size: 1}).
+		Weights(ConstantWeights(0.5)).
+		Build()
+	require.NoError(t, err, "failed to build genome")
+
+	// the same layout as data/xorstartgenes
+	require.Len(t, genome.Nodes, 4)
+	expectedTypes := []network.NodeNeuronType{network.BiasNeuron, network.InputNeuron, network.InputNeuron, network.OutputNeuron}
+	for i, node := range genome.Nodes {
+		assert.Equal(t, i+1, node.Id)
+		assert.Equal(t, expectedTypes[i], node.NeuronType, "wrong type of node: %d", node.Id)
+		assert.Equal(t, math.SigmoidSteepenedActivation, node.ActivationType)
+		require.NotNil(t, node.Trait)
+		assert.Equal(t, 1, node.Trait.Id)
+	}
+	require.Len(t, genome.Genes, 3)
+	for i, gene := range genome.Genes {
+		assert.EqualValues(t, i+1, gene.InnovationNum)
+		assert.Equal(t, i+1, gene.Link.InNode.Id)
+		assert.Equal(t, 4, gene.Link.OutNode.Id)
+		assert.Equal(t, 0.5, gene.Link.Weight)
+		assert.Equal(t, 0.5, gene.MutationNum)
+		assert.True(t, gene.IsEnabled)
+	}
+	require.Len(t, genome.Traits, 1)
+	assert.NotNil(t, genome.Phenotype, "phenotype expected")
+}
+
+func TestGenomeBuilder_Build_layers(t *testing.T) {
+	builder := NewGenomeBuilder(2).
+		Inputs(3).
+		HiddenLayer(Layer{Size: 4, Activation: math.TanhActivation, Weights: NormalWeights(0, 0.1), TraitId: 2}).
+		HiddenLayer(Layer{Size: 2, Activation: math.LinearActivation}).
+		Outputs(Layer{Size: 2, Activation: math.SigmoidBipolarActivation}).
+		Trait(0.1, 0.2).
+		Trait(0.3).
+		Rand(rand.New(rand.NewSource(42)))
+	assert.Equal(t, 0, builder.BiasId())
+	assert.Equal(t, []int{1, 2, 3}, builder.InputIds())
+	assert.Equal(t, []int{4, 5}, builder.OutputIds())
+	assert.Equal(t, []int{6, 7, 8, 9}, builder.HiddenIds(0))
+	assert.Equal(t, []int{10, 11}, builder.HiddenIds(1))
+	assert.Nil(t, builder.HiddenIds(2))
+
+	genome, err := builder.Build()
+	require.NoError(t, err, "failed to build genome")
+	require.Len(t, genome.Nodes, 11)
+	require.Len(t, genome.Genes, 3*4+4*2+2*2)
+	require.Len(t, genome.Traits, 2)
+	assert.Equal(t, []float64{0.1, 0.2, 0, 0, 0, 0, 0, 0}, genome.Traits[0].Params)
+
+	for _, id := range builder.HiddenIds(0) {
+		node := NodeWithId(id, genome.Nodes)
+		assert.Equal(t, math.TanhActivation, node.ActivationType)
+		assert.Equal(t, 2, node.Trait.Id)
+	}
+	assert.Equal(t, math.LinearActivation, NodeWithId(10, genome.Nodes).ActivationType)
+	assert.Equal(t, math.SigmoidBipolarActivation, NodeWithId(4, genome.Nodes).ActivationType)
+
+	// the genes are ordered by innovation numbers and connect the adjacent layers
+	for i, gene := range genome.Genes {
+		assert.EqualValues(t, i+1, gene.InnovationNum)
+		in, out := gene.Link.InNode.Id, gene.Link.OutNode.Id
+		switch {
+		case out >= 6 && out <= 9:
+			assert.True(t, in >= 1 && in <= 3, "wrong input of gene: %s", gene)
+			assert.True(t, gene.Link.Weight > -1 && gene.Link.Weight < 1, "normal weight expected: %s", gene)
+			assert.Equal(t, 2, gene.Link.Trait.Id)
+		case out >= 10:
+			assert.True(t, in >= 6 && in <= 9, "wrong input of gene: %s", gene)
+		default:
+			assert.True(t, in >= 10, "wrong input of gene: %s", gene)
+		}
+	}
+
+	// the network can be activated
+	require.NoError(t, genome.Phenotype.LoadSensors([]float64{0.1, 0.2, 0.3}))
+	_, err = genome.Phenotype.ForwardSteps(4)
+	require.NoError(t, err)
+}
+
+func TestGenomeBuilder_Build_sparse(t *testing.T) {
+	build := func(seed int64) *Genome {
+		genome, err := NewGenomeBuilder(1).
+			Inputs(5).
+			Bias().
+			HiddenLayer(Layer{Size: 5, Connectivity: SparseConnectivity, LinkProbability: 0.5}).
+			Outputs(Layer{Size: 3}).
+			Rand(rand.New(rand.NewSource(seed))).
+			Build()
+		require.NoError(t, err, "failed to build genome")
+		return genome
+	}
+	first, second := build(1), build(2)
+	full := 6*5 + 6*3
+	assert.True(t, len(first.Genes) < full, "sparse connectivity expected")
+	assert.True(t, len(first.Genes) >= 6*3, "full connectivity of outputs expected")
+
+	// the same links have the same innovation numbers
+	innovations := make(map[int64][2]int)
+	for _, genome := range []*Genome{first, second} {
+		for i, gene := range genome.Genes {
+			if i > 0 {
+				assert.True(t, gene.InnovationNum > genome.Genes[i-1].InnovationNum, "genes out of order")
+			}
+			link := [2]int{gene.Link.InNode.Id, gene.Link.OutNode.Id}
+			if prev, ok := innovations[gene.InnovationNum]; ok {
+				assert.Equal(t, prev, link, "different links with the same innovation: %d", gene.InnovationNum)
+			}
+			innovations[gene.InnovationNum] = link
+		}
+	}
+}
+
+func TestGenomeBuilder_Build_modules(t *testing.T) {
+	builder := NewGenomeBuilder(3).
+		Inputs(4).
+		Bias().
+		Outputs(Layer{Size: 2}).
+		HiddenLayer(Layer{Size: 2, Activation: math.LinearActivation}).
+		HiddenLayer(Layer{Size: 1, Activation: math.NullActivation, Connectivity: NoConnectivity})
+	builder.Module(Module{
+		Activation: math.MultiplyModuleActivation,
+		Inputs:     builder.HiddenIds(0),
+		Outputs:    builder.HiddenIds(1),
+	})
+	genome, err := builder.Build()
+	require.NoError(t, err, "failed to build genome")
+
+	require.Len(t, genome.Nodes, 10)
+	require.Len(t, genome.ControlGenes, 1)
+	module := genome.ControlGenes[0]
+	assert.Equal(t, 11, module.ControlNode.Id)
+	assert.Equal(t, math.MultiplyModuleActivation, module.ControlNode.ActivationType)
+	// follows all possible links: inputs and bias to hidden, bias and hidden to module outputs, and to outputs
+	assert.EqualValues(t, 5*2+3*1+2*2+1, module.InnovationNum)
+	require.Len(t, module.ControlNode.Incoming, 2)
+	assert.Equal(t, 8, module.ControlNode.Incoming[0].InNode.Id)
+	require.Len(t, module.ControlNode.Outgoing, 1)
+	assert.Equal(t, 10, module.ControlNode.Outgoing[0].OutNode.Id)
+	assert.Equal(t, 5*2+2*2, len(genome.Genes))
+
+	require.NotNil(t, genome.Phenotype)
+	assert.Equal(t, 11, genome.Phenotype.NodeCount())
+}
+
+func TestGenomeBuilder_Build_invalid(t *testing.T) {
+	_, err := NewGenomeBuilder(1).
+		HiddenLayer(Layer{Size: 0}).
+		HiddenLayer(Layer{Size: 1, Connectivity: SparseConnectivity}).
+		Outputs(Layer{Size: 1, Activation: math.MultiplyModuleActivation, TraitId: 2}).
+		Module(Module{Inputs: []int{1}, Outputs: []int{100}}).
+		Trait(1, 2, 3, 4, 5, 6, 7, 8, 9).
+		Build()
+	assert.EqualError(t, err, "invalid genome declaration:\n"+
+		"\ttoo many parameters of trait 1: 9, maximum: 8\n"+
+		"\tat least one input expected, found: 0\n"+
+		"\thidden layer 0: at least one node expected, found: 0\n"+
+		"\thidden layer 1: link probability must be in (0, 1] range, found: 0\n"+
+		"\toutput layer: unknown neuron activation type: 20\n"+
+		"\toutput layer: unknown trait ID: 2\n"+
+		"\tmodule 0: activation function is not set\n"+
+		"\tmodule 0: unknown output node ID: 100")
+
+	// module can not output to the sensor
+	builder := NewGenomeBuilder(1).Inputs(2).Outputs(Layer{Size: 1})
+	_, err = builder.Module(Module{Activation: math.MaxModuleActivation, Inputs: builder.OutputIds(),
+		Outputs: builder.InputIds()[:1]}).Build()
+	assert.EqualError(t, err, "invalid genome declaration:\n"+
+		"\tmodule 0: sensor node ID: 1 can not be output of module")
+
+	// no connections at all
+	_, err = NewGenomeBuilder(1).Inputs(2).Outputs(Layer{Size: 1, Connectivity: NoConnectivity}).Build()
+	assert.EqualError(t, err, "genome has no connection genes, at least one layer must be connected")
+}
+
+func TestGenomeBuilder_Build_population(t *testing.T) {
+	genome, err := NewGenomeBuilder(1).
+		Inputs(4).
+		Bias().
+		HiddenLayer(Layer{Size: 3, Connectivity: SparseConnectivity, LinkProbability: 0.7}).
+		Outputs(Layer{Size: 2}).
+		Build()
+	require.NoError(t, err, "failed to build genome")
+
+	opts := &neat.Options{PopSize: 10, CompatThreshold: 0.5}
+	pop, err := NewPopulation(genome, opts)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, opts.PopSize)
+	nextInnovNum, err := genome.getNextGeneInnovNum()
+	require.NoError(t, err)
+	assert.True(t, pop.nextInnovNum >= nextInnovNum-1, "wrong next innovation number in population")
+	ok, err := pop.Verify()
+	assert.True(t, ok, err)
+}
@@ -18,7 +18,7 @@ import (
 // 	(1) You can start minimally even in problems with many inputs and
 // 	(2) you don't need to know a priori what the important features of the domain are.
 // If all sensors already connected than do nothing.
-func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.Options) (bool, error) {
+func (g *Genome) mutateConnectSensors(rng *rand.Rand, innovations InnovationsObserver, _ *neat.Options) (bool, error) {
 
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes")
@@ -60,7 +60,7 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 	}
 
 	// pick randomly from disconnected sensors
-	sensor := disconnectedSensors[rand.Intn(len(disconnectedSensors))]
+	sensor := disconnectedSensors[rng.Intn(len(disconnectedSensors))]
 	// add new links to chosen sensor, avoiding redundancy
 	linkAdded := false
 	for _, output := range outputs {
@@ -93,9 +93,9 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 			// The innovation is totally novel
 			if !innovationFound {
 				// Choose a random trait
-				traitNum := rand.Intn(len(g.Traits))
+				traitNum := rng.Intn(len(g.Traits))
 				// Choose the new weight
-				newWeight := float64(math.RandSign()) * rand.Float64() * 10.0
+				newWeight := float64(math.RandSignWith(rng)) * rng.Float64() * 10.0
 				// read next innovation id
 				nextInnovId := innovations.NextInnovationNumber()
 
@@ -129,7 +129,7 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 
 // Mutate the genome by adding a new link between two random NNodes,
 // if NNodes are already connected, keep trying conf.NewLinkTries times
-func (g *Genome) mutateAddLink(innovations InnovationsObserver, opts *neat.Options) (bool, error) {
+func (g *Genome) mutateAddLink(rng *rand.Rand, innovations InnovationsObserver, opts *neat.Options) (bool, error) {
 	// If the phenotype does not exist, exit on false, print error
 	// Note: This should never happen - if it does there is a bug
 	if g.Phenotype == nil {
@@ -142,7 +142,7 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, opts *neat.Optio
 
 	// Decide whether to make link recurrent
 	doRecur := false
-	if rand.Float64() < opts.RecurOnlyProb {
+	if rng.Float64() < opts.RecurOnlyProb {
 		doRecur = true
 	}
 
@@ -168,22 +168,22 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, opts *neat.Optio
 			// 50% of prob to decide create a recurrent link (node X to node X)
 			// 50% of a normal link (node X to node Y)
 			loopRecur := false
-			if rand.Float64() > 0.5 {
+			if rng.Float64() > 0.5 {
 				loopRecur = true
 			}
 			if loopRecur {
-				nodeNum1 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
+				nodeNum1 = firstNonSensor + rng.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 				nodeNum2 = nodeNum1
 			} else {
 				for nodeNum1 == nodeNum2 {
-					nodeNum1 = rand.Intn(nodesLen)
-					nodeNum2 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
+					nodeNum1 = rng.Intn(nodesLen)
+					nodeNum2 = firstNonSensor + rng.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 				}
 			}
 		} else {
 			for nodeNum1 == nodeNum2 {
-				nodeNum1 = rand.Intn(nodesLen)
-				nodeNum2 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
+				nodeNum1 = rng.Intn(nodesLen)
+				nodeNum2 = firstNonSensor + rng.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 			}
 		}
 
@@ -261,9 +261,9 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, opts *neat.Optio
 		// The innovation is totally novel
 		if !innovationFound {
 			// Choose a random trait
-			traitNum := rand.Intn(len(g.Traits))
+			traitNum := rng.Intn(len(g.Traits))
 			// Choose the new weight
-			newWeight := float64(math.RandSign()) * rand.Float64() * 10.0
+			newWeight := float64(math.RandSignWith(rng)) * rng.Float64() * 10.0
 			// read next innovation id
 			nextInnovId := innovations.NextInnovationNumber()
 
@@ -305,7 +305,7 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, opts *neat.Optio
 // The innovations list from population is used to compare the innovation with other innovations in the list and see
 // whether they match. If they do, the same innovation numbers will be assigned to the new genes. If a disabled link
 // is chosen, then the method just exits with false.
-func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator network.NodeIdGenerator, opts *neat.Options) (bool, error) {
+func (g *Genome) mutateAddNode(rng *rand.Rand, innovations InnovationsObserver, nodeIdGenerator network.NodeIdGenerator, opts *neat.Options) (bool, error) {
 	if len(g.Genes) == 0 {
 		return false, nil // it's possible to have such a network without any link
 	}
@@ -319,7 +319,7 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 	if len(g.Genes) < 15 {
 		for _, gn := range g.Genes {
 			// Now randomize which gene is chosen.
-			if gn.IsEnabled && gn.Link.InNode.NeuronType != network.BiasNeuron && rand.Float32() >= 0.3 {
+			if gn.IsEnabled && gn.Link.InNode.NeuronType != network.BiasNeuron && rng.Float32() >= 0.3 {
 				gene = gn
 				found = true
 				break
@@ -329,7 +329,7 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 		tryCount := 0
 		// Alternative uniform random choice of genes. When the genome is not tiny, it is safe to choose randomly.
 		for tryCount < 20 && !found {
-			geneNum := rand.Intn(len(g.Genes))
+			geneNum := rng.Intn(len(g.Genes))
 			gene = g.Genes[geneNum]
 			if gene.IsEnabled && gene.Link.InNode.NeuronType != network.BiasNeuron {
 				found = true
@@ -399,7 +399,7 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 		// By convention, it will point to the first trait
 		node.Trait = g.Traits[0]
 		// Set node activation function as random from a list of types registered with opts
-		if activationType, err := opts.RandomNodeActivationType(); err != nil {
+		if activationType, err := opts.RandomNodeActivationTypeWith(rng); err != nil {
 			return false, err
 		} else {
 			node.ActivationType = activationType
@@ -444,14 +444,14 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 
 // Adds Gaussian noise to link weights either GAUSSIAN or COLD_GAUSSIAN (from zero).
 // The COLD_GAUSSIAN means ALL connection weights will be given completely new values
-func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType) (bool, error) {
+func (g *Genome) mutateLinkWeights(rng *rand.Rand, power, rate float64, mutationType mutatorType) (bool, error) {
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes")
 	}
 
 	// Once in a while really shake things up
 	severe := false
-	if rand.Float64() > 0.5 {
+	if rng.Float64() > 0.5 {
 		severe = true
 	}
 
@@ -475,7 +475,7 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 			coldGaussPoint = 0.3 // Mutate the rest by replacement % of the time
 		} else {
 			// Half the time don't do any cold mutations
-			if rand.Float64() > 0.5 {
+			if rng.Float64() > 0.5 {
 				gaussPoint = 1.0 - rate
 				coldGaussPoint = gaussPoint - 0.1
 			} else {
@@ -484,9 +484,9 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 			}
 		}
 
-		random := float64(math.RandSign()) * rand.Float64() * power
+		random := float64(math.RandSignWith(rng)) * rng.Float64() * power
 		if mutationType == gaussianMutator {
-			randChoice := rand.Float64()
+			randChoice := rng.Float64()
 			if randChoice > gaussPoint {
 				gene.Link.Weight += random
 			} else if randChoice > coldGaussPoint {
@@ -506,30 +506,30 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 }
 
 // Perturb params in one trait
-func (g *Genome) mutateRandomTrait(context *neat.Options) (bool, error) {
+func (g *Genome) mutateRandomTrait(rng *rand.Rand, context *neat.Options) (bool, error) {
 	if len(g.Traits) == 0 {
 		return false, errors.New("genome has no traits")
 	}
 	// Choose a random trait number
-	traitNum := rand.Intn(len(g.Traits))
+	traitNum := rng.Intn(len(g.Traits))
 
 	// Retrieve the trait and mutate it
-	g.Traits[traitNum].Mutate(context.TraitMutationPower, context.TraitParamMutProb)
+	g.Traits[traitNum].MutateWith(rng, context.TraitMutationPower, context.TraitParamMutProb)
 
 	return true, nil
 }
 
 // This chooses a random gene, extracts the link from it and re-points the link to a random trait
-func (g *Genome) mutateLinkTrait(times int) (bool, error) {
+func (g *Genome) mutateLinkTrait(rng *rand.Rand, times int) (bool, error) {
 	if len(g.Traits) == 0 || len(g.Genes) == 0 {
 		return false, errors.New("genome has either no traits od genes")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random trait number
-		traitNum := rand.Intn(len(g.Traits))
+		traitNum := rng.Intn(len(g.Traits))
 
 		// Choose a random link number
-		geneNum := rand.Intn(len(g.Genes))
+		geneNum := rng.Intn(len(g.Genes))
 
 		// set the link to point to the new trait
 		g.Genes[geneNum].Link.Trait = g.Traits[traitNum]
@@ -539,16 +539,16 @@ func (g *Genome) mutateLinkTrait(times int) (bool, error) {
 }
 
 // This chooses a random node and re-points the node to a random trait specified number of times
-func (g *Genome) mutateNodeTrait(times int) (bool, error) {
+func (g *Genome) mutateNodeTrait(rng *rand.Rand, times int) (bool, error) {
 	if len(g.Traits) == 0 || len(g.Nodes) == 0 {
 		return false, errors.New("genome has either no traits or nodes")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random trait number
-		traitNum := rand.Intn(len(g.Traits))
+		traitNum := rng.Intn(len(g.Traits))
 
 		// Choose a random node number
-		nodeNum := rand.Intn(len(g.Nodes))
+		nodeNum := rng.Intn(len(g.Nodes))
 
 		// set the node to point to the new trait
 		g.Nodes[nodeNum].Trait = g.Traits[traitNum]
@@ -557,13 +557,13 @@ func (g *Genome) mutateNodeTrait(times int) (bool, error) {
 }
 
 // Toggle genes from enable ON to enable OFF or vice versa. Do it specified number of times.
-func (g *Genome) mutateToggleEnable(times int) (bool, error) {
+func (g *Genome) mutateToggleEnable(rng *rand.Rand, times int) (bool, error) {
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes to toggle")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random gene number
-		geneNum := rand.Intn(len(g.Genes))
+		geneNum := rng.Intn(len(g.Genes))
 
 		gene := g.Genes[geneNum]
 		if gene.IsEnabled {
@@ -596,35 +596,35 @@ func (g *Genome) mutateGeneReEnable() (bool, error) {
 }
 
 // Applies all non-structural mutations to this genome
-func (g *Genome) mutateAllNonstructural(context *neat.Options) (bool, error) {
+func (g *Genome) mutateAllNonstructural(rng *rand.Rand, context *neat.Options) (bool, error) {
 	res := false
 	var err error
-	if rand.Float64() < context.MutateRandomTraitProb {
+	if rng.Float64() < context.MutateRandomTraitProb {
 		// mutate random trait
-		res, err = g.mutateRandomTrait(context)
+		res, err = g.mutateRandomTrait(rng, context)
 	}
 
-	if err == nil && rand.Float64() < context.MutateLinkTraitProb {
+	if err == nil && rng.Float64() < context.MutateLinkTraitProb {
 		// mutate link trait
-		res, err = g.mutateLinkTrait(1)
+		res, err = g.mutateLinkTrait(rng, 1)
 	}
 
-	if err == nil && rand.Float64() < context.MutateNodeTraitProb {
+	if err == nil && rng.Float64() < context.MutateNodeTraitProb {
 		// mutate node trait
-		res, err = g.mutateNodeTrait(1)
+		res, err = g.mutateNodeTrait(rng, 1)
 	}
 
-	if err == nil && rand.Float64() < context.MutateLinkWeightsProb {
+	if err == nil && rng.Float64() < context.MutateLinkWeightsProb {
 		// mutate link weight
-		res, err = g.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator)
+		res, err = g.mutateLinkWeights(rng, context.WeightMutPower, 1.0, gaussianMutator)
 	}
 
-	if err == nil && rand.Float64() < context.MutateToggleEnableProb {
+	if err == nil && rng.Float64() < context.MutateToggleEnableProb {
 		// mutate toggle enable
-		res, err = g.mutateToggleEnable(1)
+		res, err = g.mutateToggleEnable(rng, 1)
 	}
 
-	if err == nil && rand.Float64() < context.MutateGeneReenableProb {
+	if err == nil && rng.Float64() < context.MutateGeneReenableProb {
 		// mutate gene reenable
 		res, err = g.mutateGeneReEnable()
 	}
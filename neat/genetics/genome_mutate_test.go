@@ -11,7 +11,7 @@ import (
 )
 
 func TestGenome_mutateAddLink(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 	// Configuration
 	context := &neat.Options{
@@ -22,14 +22,14 @@ func TestGenome_mutateAddLink(t *testing.T) {
 	}
 	// The population with one organism
 	pop := newPopulation()
-	err := pop.spawn(gnome1, context)
+	err := pop.spawn(neat.NewRandContext(context.NeatContext(), rng), gnome1, context)
 	require.NoError(t, err, "failed to spawn population")
 
 	// Create gnome phenotype
 	_, err = gnome1.Genesis(1)
 	require.NoError(t, err, "genesis failed")
 
-	res, err := gnome1.mutateAddLink(pop, context)
+	res, err := gnome1.mutateAddLink(rng, pop, context)
 	require.NoError(t, err, "failed to add link")
 	require.True(t, res, "New link not added")
 
@@ -52,7 +52,7 @@ func TestGenome_mutateAddLink(t *testing.T) {
 	_, err = gnome1.Genesis(1) // do network genesis with new nodes added
 	require.NoError(t, err, "genesis failed")
 
-	res, err = gnome1.mutateAddLink(pop, context)
+	res, err = gnome1.mutateAddLink(rng, pop, context)
 	require.NoError(t, err, "failed to add link")
 	require.True(t, res, "New link not added")
 
@@ -68,6 +68,7 @@ func TestGenome_mutateAddLink(t *testing.T) {
 }
 
 func TestGenome_mutateConnectSensors(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	// Test mutation with all inputs connected
 	//
 	gnome1 := buildTestGenome(1)
@@ -78,10 +79,10 @@ func TestGenome_mutateConnectSensors(t *testing.T) {
 	context.PopSize = 1
 	// The population with one organism
 	pop := newPopulation()
-	err = pop.spawn(gnome1, context)
+	err = pop.spawn(neat.NewRandContext(context.NeatContext(), rng), gnome1, context)
 	require.NoError(t, err, "failed to spawn population")
 
-	res, err := gnome1.mutateConnectSensors(pop, context)
+	res, err := gnome1.mutateConnectSensors(rng, pop, context)
 	require.NoError(t, err, "failed to mutate")
 	assert.False(t, res, "All inputs already connected - no mutation expected")
 
@@ -97,7 +98,7 @@ func TestGenome_mutateConnectSensors(t *testing.T) {
 	// Create gnome phenotype
 	_, err = gnome1.Genesis(1)
 	require.NoError(t, err, "genesis failed")
-	res, err = gnome1.mutateConnectSensors(pop, context)
+	res, err = gnome1.mutateConnectSensors(rng, pop, context)
 	require.NoError(t, err, "failed to mutate")
 	assert.True(t, res, "Its expected for disconnected sensor to be connected now")
 	assert.Len(t, gnome1.Genes, 4, "wrong number of genome genes")
@@ -107,7 +108,7 @@ func TestGenome_mutateConnectSensors(t *testing.T) {
 }
 
 func TestGenome_mutateAddNode(t *testing.T) {
-	//rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 
 	// Create gnome phenotype
@@ -120,10 +121,10 @@ func TestGenome_mutateAddNode(t *testing.T) {
 	context.PopSize = 1
 	// The population with one organism
 	pop := newPopulation()
-	err = pop.spawn(gnome1, context)
+	err = pop.spawn(neat.NewRandContext(context.NeatContext(), rng), gnome1, context)
 	require.NoError(t, err, "failed to spawn population")
 
-	res, err := gnome1.mutateAddNode(pop, pop, context)
+	res, err := gnome1.mutateAddNode(rng, pop, pop, context)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -139,9 +140,9 @@ func TestGenome_mutateAddNode(t *testing.T) {
 }
 
 func TestGenome_mutateLinkWeights(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
-	res, err := gnome1.mutateLinkWeights(0.5, 1.0, gaussianMutator)
+	res, err := gnome1.mutateLinkWeights(rng, 0.5, 1.0, gaussianMutator)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -152,13 +153,14 @@ func TestGenome_mutateLinkWeights(t *testing.T) {
 }
 
 func TestGenome_mutateRandomTrait(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 	// Configuration
 	context := neat.Options{
 		TraitMutationPower: 0.3,
 		TraitParamMutProb:  0.5,
 	}
-	res, err := gnome1.mutateRandomTrait(&context)
+	res, err := gnome1.mutateRandomTrait(rng, &context)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -181,9 +183,10 @@ func TestGenome_mutateRandomTrait(t *testing.T) {
 }
 
 func TestGenome_mutateLinkTrait(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 
-	res, err := gnome1.mutateLinkTrait(10)
+	res, err := gnome1.mutateLinkTrait(rng, 10)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -198,6 +201,7 @@ func TestGenome_mutateLinkTrait(t *testing.T) {
 }
 
 func TestGenome_mutateNodeTrait(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 
 	// Add traits to nodes
@@ -208,7 +212,7 @@ func TestGenome_mutateNodeTrait(t *testing.T) {
 	}
 	gnome1.Nodes[3].Trait = &neat.Trait{Id: 4, Params: []float64{0.4, 0, 0, 0, 0, 0, 0, 0}}
 
-	res, err := gnome1.mutateNodeTrait(2)
+	res, err := gnome1.mutateNodeTrait(rng, 2)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -223,12 +227,13 @@ func TestGenome_mutateNodeTrait(t *testing.T) {
 }
 
 func TestGenome_mutateToggleEnable(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 	// add extra connection gene from BIAS to OUT
 	gene := NewConnectionGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2], gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 
-	res, err := gnome1.mutateToggleEnable(50)
+	res, err := gnome1.mutateToggleEnable(rng, 50)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -225,10 +225,6 @@ func (r *yamlGenomeReader) Read() (*Genome, error) {
 		if err != nil {
 			return nil, err
 		}
-		// check that trait ID is unique
-		if prevTrait := TraitWithId(trait.Id, gnome.Traits); prevTrait != nil {
-			return nil, fmt.Errorf("trait ID: %d is not unique", trait.Id)
-		}
 		gnome.Traits = append(gnome.Traits, trait)
 	}
 
@@ -239,10 +235,6 @@ func (r *yamlGenomeReader) Read() (*Genome, error) {
 		if err != nil {
 			return nil, err
 		}
-		// check that node ID is unique
-		if prevNode := NodeWithId(node.Id, gnome.Nodes); prevNode != nil {
-			return nil, fmt.Errorf("node ID: %d is not unique", node.Id)
-		}
 		gnome.Nodes = append(gnome.Nodes, node)
 	}
 
@@ -264,17 +256,46 @@ func (r *yamlGenomeReader) Read() (*Genome, error) {
 			if err != nil {
 				return nil, err
 			}
-			// check that control node ID is unique
-			if prevNode := NodeWithId(mGene.ControlNode.Id, gnome.Nodes); prevNode != nil {
-				return nil, fmt.Errorf("control node ID: %d is not unique", mGene.ControlNode.Id)
-			}
 			gnome.ControlGenes = append(gnome.ControlGenes, mGene)
 		}
 	}
 
+	// the hand-edited genome may have many mistakes, report all of them at once
+	issues := gnome.Validate()
+	if issues.HasErrors() {
+		return nil, &GenomeValidationError{GenomeId: gnome.Id, Issues: issues}
+	}
+	for _, issue := range issues {
+		neat.WarnLog(fmt.Sprintf("genome %d: %s", gnome.Id, issue))
+	}
+
 	return gnome, nil
 }
 
+// The references to the traits and nodes missing in the genome are kept as detached placeholders with referenced IDs,
+// which are reported by the genome validation.
+
+// traitReference Returns trait with given ID or its placeholder if not found. Returns nil if trait ID is zero.
+func traitReference(traitId int, traits []*neat.Trait) *neat.Trait {
+	if traitId == 0 {
+		return nil
+	}
+	if trait := TraitWithId(traitId, traits); trait != nil {
+		return trait
+	}
+	trait := neat.NewTrait()
+	trait.Id = traitId
+	return trait
+}
+
+// nodeReference Returns node with given ID or its placeholder if not found
+func nodeReference(nodeId int, nodes []*network.NNode) *network.NNode {
+	if node := NodeWithId(nodeId, nodes); node != nil {
+		return node
+	}
+	return network.NewNNode(nodeId, network.HiddenNeuron)
+}
+
 // Reads gene configuration
 func readGene(conf map[string]interface{}, traits []*neat.Trait, nodes []*network.NNode) (*Gene, error) {
 	traitId := conf["trait_id"].(int)
@@ -301,16 +322,8 @@ func readGene(conf map[string]interface{}, traits []*neat.Trait, nodes []*networ
 		return nil, err
 	}
 
-	trait := TraitWithId(traitId, traits)
-	var inNode, outNode *network.NNode
-	for _, np := range nodes {
-		if np.Id == inNodeId {
-			inNode = np
-		}
-		if np.Id == outNodeId {
-			outNode = np
-		}
-	}
+	trait := traitReference(traitId, traits)
+	inNode, outNode := nodeReference(inNodeId, nodes), nodeReference(outNodeId, nodes)
 	if trait != nil {
 		return NewConnectionGene(network.NewLinkWithTrait(trait, weight, inNode, outNode, recurrent), innovationNum, mutNum, enabled), nil
 	} else {
@@ -332,8 +345,7 @@ func readMIMOControlGene(conf map[string]interface{}, traits []*neat.Trait, node
 	}
 	// set associated Trait
 	traitId := conf["trait_id"].(int)
-	trait := TraitWithId(traitId, traits)
-	controlNode.Trait = trait
+	controlNode.Trait = traitReference(traitId, traits)
 
 	// read MIMO gene parameters
 	innovationNum, err := cast.ToInt64E(conf["innov_num"])
@@ -361,13 +373,8 @@ func readMIMOControlGene(conf map[string]interface{}, traits []*neat.Trait, node
 		if err != nil {
 			return nil, err
 		}
-		node := NodeWithId(nodeId, nodes)
-		if node != nil {
-			controlNode.Incoming[i] = network.NewLink(1.0, node, controlNode, false)
-		} else {
-			return nil, fmt.Errorf("no MIMO input node with id: %d can be found for module: %d",
-				nodeId, controlNode.Id)
-		}
+		node := nodeReference(nodeId, nodes)
+		controlNode.Incoming[i] = network.NewLink(1.0, node, controlNode, false)
 	}
 
 	// read output links
@@ -382,13 +389,8 @@ func readMIMOControlGene(conf map[string]interface{}, traits []*neat.Trait, node
 		if err != nil {
 			return nil, err
 		}
-		node := NodeWithId(nodeId, nodes)
-		if node != nil {
-			controlNode.Outgoing[i] = network.NewLink(1.0, controlNode, node, false)
-		} else {
-			return nil, fmt.Errorf("no MIMO output node with id: %d can be found for module: %d",
-				nodeId, controlNode.Id)
-		}
+		node := nodeReference(nodeId, nodes)
+		controlNode.Outgoing[i] = network.NewLink(1.0, controlNode, node, false)
 	}
 
 	// build gene
@@ -402,7 +404,7 @@ func readNNode(conf map[string]interface{}, traits []*neat.Trait) (*network.NNod
 	nd := network.NewNetworkNode()
 	nd.Id = conf["id"].(int)
 	traitId := conf["trait_id"].(int)
-	nd.Trait = TraitWithId(traitId, traits)
+	nd.Trait = traitReference(traitId, traits)
 	typeName := conf["type"].(string)
 	var err error
 	nd.NeuronType, err = network.NeuronTypeByName(typeName)
@@ -295,3 +295,51 @@ func TestYAMLGenomeReader_Read(t *testing.T) {
 		idCount++
 	}
 }
+
+func TestYAMLGenomeReader_Read_invalid(t *testing.T) {
+	// the hand-edited genome with many mistakes
+	genomeStr := `genome:
+  id: 7
+  traits:
+    - {id: 1, params: [0.1, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0]}
+  nodes:
+    - {id: 1, trait_id: 0, type: BIAS, activation: NullActivation}
+    - {id: 2, trait_id: 0, type: INPT, activation: NullActivation}
+    - {id: 3, trait_id: 0, type: OUTP, activation: SigmoidSteepenedActivation}
+    - {id: 4, trait_id: 0, type: OUTP, activation: SigmoidSteepenedActivation}
+    - {id: 5, trait_id: 0, type: HIDN, activation: LinearActivation}
+    - {id: 6, trait_id: 0, type: HIDN, activation: NullActivation}
+  modules:
+    - id: 10
+      trait_id: 0
+      activation: MultiplyModuleActivation
+      innov_num: 20
+      mut_num: 0
+      enabled: true
+      inputs:
+        - {id: 5,  order: 0}
+        - {id: 50, order: 1}
+      outputs:
+        - {id: 6, order: 0}
+  genes:
+    - {src_id: 1, tgt_id: 3,  weight: 1.0, trait_id: 1, innov_num: 1, mut_num: 0, recurrent: false, enabled: true}
+    - {src_id: 2, tgt_id: 5,  weight: 1.0, trait_id: 9, innov_num: 1, mut_num: 0, recurrent: false, enabled: true}
+    - {src_id: 1, tgt_id: 3,  weight: 0.5, trait_id: 1, innov_num: 3, mut_num: 0, recurrent: false, enabled: true}
+    - {src_id: 2, tgt_id: 42, weight: 1.0, trait_id: 1, innov_num: 4, mut_num: 0, recurrent: false, enabled: true}
+    - {src_id: 3, tgt_id: 2,  weight: 1.0, trait_id: 1, innov_num: 5, mut_num: 0, recurrent: true,  enabled: true}
+`
+	r, err := NewGenomeReader(strings.NewReader(genomeStr), YAMLGenomeEncoding)
+	require.NoError(t, err, "failed to create reader")
+	gnome, err := r.Read()
+	assert.Nil(t, gnome)
+	require.IsType(t, &GenomeValidationError{}, err)
+	assert.Equal(t, 7, err.(*GenomeValidationError).GenomeId)
+	assert.EqualError(t, err, "invalid genome 7, 7 issue(s) found:\n"+
+		"\terror: [gene: 1] innovation number is not unique\n"+
+		"\terror: [gene: 1, trait: 9] gene refers to the trait missing in the genome\n"+
+		"\terror: [gene: 3] duplicate link [1 -> 3] of gene: 1\n"+
+		"\terror: [gene: 4, node: 42] output node of gene is missing in the genome\n"+
+		"\terror: [gene: 5, node: 2] gene links into the sensor node from node: 3\n"+
+		"\terror: [module: 10, node: 50] MIMO input node is missing in the genome\n"+
+		"\twarning: [node: 4] output node can not be reached from sensors")
+}
@@ -13,7 +13,7 @@ import (
 // the innovation number, the Gene is chosen randomly from either parent.  If one parent has an innovation absent in
 // the other, the baby may inherit the innovation if it is from the more fit parent.
 // The new Genome is given the id in the genomeId argument.
-func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
+func (g *Genome) mateMultipoint(rng *rand.Rand, og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -85,14 +85,14 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 			p2innov := p2gene.InnovationNum
 
 			if p1innov == p2innov {
-				if rand.Float64() < 0.5 {
+				if rng.Float64() < 0.5 {
 					chosenGene = p1gene
 				} else {
 					chosenGene = p2gene
 				}
 
 				// If one is disabled, the corresponding gene in the offspring will likely be disabled
-				if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
+				if !p1gene.IsEnabled || !p2gene.IsEnabled && rng.Float64() < 0.75 {
 					disable = true
 				}
 				i1++
@@ -202,7 +202,7 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 
 // This method mates like multipoint but instead of selecting one or the other when the innovation numbers match,
 // it averages their weights.
-func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
+func (g *Genome) mateMultipointAvg(rng *rand.Rand, og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -279,24 +279,24 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 
 			if p1innov == p2innov {
 				// Average them into the avg_gene
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.Trait = p1gene.Link.Trait
 				} else {
 					avgGene.Link.Trait = p2gene.Link.Trait
 				}
 				avgGene.Link.Weight = (p1gene.Link.Weight + p2gene.Link.Weight) / 2.0 // WEIGHTS AVERAGED HERE
 
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.InNode = p1gene.Link.InNode
 				} else {
 					avgGene.Link.InNode = p2gene.Link.InNode
 				}
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.OutNode = p1gene.Link.OutNode
 				} else {
 					avgGene.Link.OutNode = p2gene.Link.OutNode
 				}
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.IsRecurrent = p1gene.Link.IsRecurrent
 				} else {
 					avgGene.Link.IsRecurrent = p2gene.Link.IsRecurrent
@@ -304,7 +304,7 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 
 				avgGene.InnovationNum = p1innov
 				avgGene.MutationNum = (p1gene.MutationNum + p2gene.MutationNum) / 2.0
-				if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
+				if !p1gene.IsEnabled || !p2gene.IsEnabled && rng.Float64() < 0.75 {
 					avgGene.IsEnabled = false
 				}
 
@@ -414,7 +414,7 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 // This method is similar to a standard single point CROSSOVER operator. Traits are averaged as in the previous two
 // mating methods. A Gene is chosen in the smaller Genome for splitting. When the Gene is reached, it is averaged with
 // the matching Gene from the larger Genome, if one exists. Then every other Gene is taken from the larger Genome.
-func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
+func (g *Genome) mateSinglePoint(rng *rand.Rand, og *Genome, genomeId int) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -457,14 +457,14 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 	var p1genes, p2genes []*Gene
 	size1, size2 := len(g.Genes), len(og.Genes)
 	if size1 < size2 {
-		crossPoint = rand.Intn(size1)
+		crossPoint = rng.Intn(size1)
 		p1stop = size1
 		p2stop = size2
 		stopper = size2
 		p1genes = g.Genes
 		p2genes = og.Genes
 	} else {
-		crossPoint = rand.Intn(size2)
+		crossPoint = rng.Intn(size2)
 		p1stop = size2
 		p2stop = size1
 		stopper = size1
@@ -500,24 +500,24 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 					chosenGene = p2gene
 				} else {
 					// We are at the crossPoint here - average genes into the avgene
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.Trait = p1gene.Link.Trait
 					} else {
 						avgGene.Link.Trait = p2gene.Link.Trait
 					}
 					avgGene.Link.Weight = (p1gene.Link.Weight + p2gene.Link.Weight) / 2.0 // WEIGHTS AVERAGED HERE
 
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.InNode = p1gene.Link.InNode
 					} else {
 						avgGene.Link.InNode = p2gene.Link.InNode
 					}
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.OutNode = p1gene.Link.OutNode
 					} else {
 						avgGene.Link.OutNode = p2gene.Link.OutNode
 					}
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.IsRecurrent = p1gene.Link.IsRecurrent
 					} else {
 						avgGene.Link.IsRecurrent = p2gene.Link.IsRecurrent
@@ -525,7 +525,7 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 
 					avgGene.InnovationNum = p1innov
 					avgGene.MutationNum = (p1gene.MutationNum + p2gene.MutationNum) / 2.0
-					if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
+					if !p1gene.IsEnabled || !p2gene.IsEnabled && rng.Float64() < 0.75 {
 						avgGene.IsEnabled = false
 					}
 
@@ -9,14 +9,14 @@ import (
 )
 
 func TestGenome_mateMultipoint(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipoint(rng, gnome2, genomeId, fitness1, fitness2)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -30,7 +30,7 @@ func TestGenome_mateMultipoint(t *testing.T) {
 		gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 	fitness1, fitness2 = 15.0, 2.3
-	genomeChild, err = gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err = gnome1.mateMultipoint(rng, gnome2, genomeId, fitness1, fitness2)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -40,14 +40,14 @@ func TestGenome_mateMultipoint(t *testing.T) {
 }
 
 func TestGenome_mateMultipointModular(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipoint(rng, gnome2, genomeId, fitness1, fitness2)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -58,14 +58,14 @@ func TestGenome_mateMultipointModular(t *testing.T) {
 }
 
 func TestGenome_mateMultipointAvg(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipointAvg(rng, gnome2, genomeId, fitness1, fitness2)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -83,7 +83,7 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 	gnome2.Genes = append(gnome2.Genes, gene2)
 
 	fitness1, fitness2 = 15.0, 2.3
-	genomeChild, err = gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err = gnome1.mateMultipointAvg(rng, gnome2, genomeId, fitness1, fitness2)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -93,14 +93,14 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 }
 
 func TestGenome_mateMultipointAvgModular(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipointAvg(rng, gnome2, genomeId, fitness1, fitness2)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -111,13 +111,13 @@ func TestGenome_mateMultipointAvgModular(t *testing.T) {
 }
 
 func TestGenome_mateSinglePoint(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
-	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err := gnome1.mateSinglePoint(rng, gnome2, genomeId)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -130,7 +130,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	gene := NewConnectionGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2],
 		gnome1.Nodes[3], false), 4, 0, false)
 	gnome1.Genes = append(gnome1.Genes, gene)
-	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err = gnome1.mateSinglePoint(rng, gnome2, genomeId)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -144,7 +144,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	// append additional gene
 	gnome2.Genes = append(gnome2.Genes, NewConnectionGene(network.NewLinkWithTrait(gnome2.Traits[2], 5.5, gnome2.Nodes[1],
 		gnome2.Nodes[3], true), 4, 0, false))
-	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err = gnome1.mateSinglePoint(rng, gnome2, genomeId)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -154,14 +154,14 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 }
 
 func TestGenome_mateSinglePointModular(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 
-	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err := gnome1.mateSinglePoint(rng, gnome2, genomeId)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
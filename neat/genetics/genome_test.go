@@ -7,6 +7,7 @@ import (
 	"github.com/yaricom/goNEAT/v2/neat/math"
 	"github.com/yaricom/goNEAT/v2/neat/network"
 	"math/rand"
+	"os"
 	"testing"
 )
 
@@ -83,10 +84,10 @@ func buildTestModularGenome(id int) *Genome {
 
 // Test create random genome
 func TestGenome_NewGenomeRand(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	newId, in, out, n := 1, 3, 2, 2
 
-	gnome := newGenomeRand(newId, in, out, n, 5, false, 0.5)
+	gnome := newGenomeRand(rng, newId, in, out, n, 5, false, 0.5)
 	require.NotNil(t, gnome, "Failed to create random genome")
 	assert.Len(t, gnome.Nodes, in+n+out, "failed to create nodes")
 	assert.True(t, len(gnome.Genes) >= in+n+out, "Failed to create genes")
@@ -129,6 +130,121 @@ func TestGenome_GenesisModular(t *testing.T) {
 	assert.Equal(t, connGenesCount, net.LinkCount(), "wrong links count")
 }
 
+func TestGenome_GenesisPruned(t *testing.T) {
+	gnome := buildTestGenome(1)
+	// add hidden node without path to outputs
+	hidden := &network.NNode{Id: 5, NeuronType: network.HiddenNeuron, ActivationType: math.SigmoidSteepenedActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)}
+	gnome.Nodes = append(gnome.Nodes, hidden)
+	gnome.Genes = append(gnome.Genes, NewConnectionGene(network.NewLinkWithTrait(gnome.Traits[0], 1.5, gnome.Nodes[0], hidden, false), 4, 0, true))
+	netId := 10
+
+	net, report, err := gnome.GenesisPruned(netId)
+	require.NoError(t, err, "genesis failed")
+	require.NotNil(t, net, "network expected")
+	require.NotNil(t, report, "pruning report expected")
+	assert.Equal(t, netId, net.Id, "wrong network ID")
+	assert.Equal(t, []int{5}, report.NoPathToOutputs)
+	assert.Len(t, report.RemovedLinks, 1)
+	assert.Equal(t, len(gnome.Nodes)-1, net.NodeCount(), "wrong nodes count")
+	assert.Equal(t, len(gnome.Genes)-1, net.LinkCount(), "wrong links count")
+
+	// the genome must stay unchanged
+	assert.Nil(t, gnome.Phenotype, "phenotype should not be attached")
+	assert.Len(t, gnome.Nodes, 5)
+	assert.Len(t, gnome.Genes, 4)
+	for _, n := range gnome.Nodes {
+		assert.Nil(t, n.PhenotypeAnalogue, "phenotype analogue should not be set at: %d", n.Id)
+	}
+
+	// the complete phenotype still can be built
+	net, err = gnome.Genesis(netId)
+	require.NoError(t, err, "genesis failed")
+	assert.Equal(t, len(gnome.Nodes), net.NodeCount(), "wrong nodes count")
+}
+
+// Tests that the standard network solver and the fast network solvers produce the same results for phenotypes
+func TestGenome_Genesis_SolversEquivalence(t *testing.T) {
+	genomeFile, err := os.Open("../../data/test_seed_genome.yml")
+	require.NoError(t, err, "failed to open genome file")
+	r, err := NewGenomeReader(genomeFile, YAMLGenomeEncoding)
+	require.NoError(t, err, "failed to create genome reader")
+	seedGenome, err := r.Read()
+	require.NoError(t, err, "failed to read genome")
+	// set non-zero weights to propagate signals
+	for i, gene := range seedGenome.Genes {
+		gene.Link.Weight = 0.1*float64(i+1) - 0.7
+	}
+
+	// the recurrent genome with self-loop at output
+	recurrentGenome := buildTestGenome(2)
+	recurrentGenome.Genes = append(recurrentGenome.Genes, NewConnectionGene(
+		network.NewLinkWithTrait(recurrentGenome.Traits[0], -0.5, recurrentGenome.Nodes[3], recurrentGenome.Nodes[3], true), 4, 0, true))
+
+	testCases := []struct {
+		genome *Genome
+		inputs []float64
+	}{
+		{genome: seedGenome, inputs: []float64{0.5, -1.0, 1.5, 2.0}},
+		{genome: recurrentGenome, inputs: []float64{0.5, -1.0}},
+		{genome: buildTestModularGenome(3), inputs: []float64{1.5, 2.0}},
+	}
+	steps, delta := 10, 1e-6
+	for _, tc := range testCases {
+		// forward steps
+		net, err := tc.genome.Genesis(tc.genome.Id)
+		require.NoError(t, err, "genesis failed")
+		fmm, err := net.FastModularSolver()
+		require.NoError(t, err, "failed to create fast network solver")
+		require.NoError(t, net.LoadSensors(tc.inputs))
+		require.NoError(t, fmm.LoadSensors(tc.inputs))
+
+		res, err := net.ForwardSteps(steps)
+		require.NoError(t, err, "failed forward steps of network: %d", tc.genome.Id)
+		require.True(t, res)
+		res, err = fmm.ForwardSteps(steps)
+		require.NoError(t, err, "failed forward steps of fast solver: %d", tc.genome.Id)
+		require.True(t, res)
+		assert.InDeltaSlice(t, fmm.ReadOutputs(), net.ReadOutputs(), 1e-9, "wrong outputs of genome: %d", tc.genome.Id)
+
+		// relaxation
+		net, err = tc.genome.Genesis(tc.genome.Id)
+		require.NoError(t, err, "genesis failed")
+		fmm, err = net.FastModularSolver()
+		require.NoError(t, err, "failed to create fast network solver")
+		require.NoError(t, net.LoadSensors(tc.inputs))
+		require.NoError(t, fmm.LoadSensors(tc.inputs))
+
+		netRelaxed, err := net.Relax(steps, delta)
+		require.NoError(t, err, "failed to relax network: %d", tc.genome.Id)
+		fmmRelaxed, err := fmm.Relax(steps, delta)
+		require.NoError(t, err, "failed to relax fast solver: %d", tc.genome.Id)
+		assert.Equal(t, fmmRelaxed, netRelaxed, "wrong relaxation of genome: %d", tc.genome.Id)
+		assert.InDeltaSlice(t, fmm.ReadOutputs(), net.ReadOutputs(), 1e-9, "wrong outputs of genome: %d", tc.genome.Id)
+	}
+
+	// recursive steps
+	net, err := seedGenome.Genesis(1)
+	require.NoError(t, err, "genesis failed")
+	_, err = net.RecursiveSteps()
+	assert.EqualError(t, err, network.NetErrNetworkHasCycles.Error(), "seed genome has cycles through the modules")
+
+	modularGenome := buildTestModularGenome(4)
+	net, err = modularGenome.Genesis(4)
+	require.NoError(t, err, "genesis failed")
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	inputs := []float64{1.5, 2.0}
+	require.NoError(t, net.LoadSensors(inputs))
+	require.NoError(t, ffs.LoadSensors(inputs))
+	res, err := net.RecursiveSteps()
+	require.NoError(t, err, "failed recursive steps of network")
+	require.True(t, res)
+	res, err = ffs.RecursiveSteps()
+	require.NoError(t, err, "failed recursive steps of feed-forward solver")
+	require.True(t, res)
+	assert.InDeltaSlice(t, ffs.ReadOutputs(), net.ReadOutputs(), 1e-9)
+}
+
 // Test duplicate
 func TestGenome_Duplicate(t *testing.T) {
 	gnome := buildTestGenome(1)
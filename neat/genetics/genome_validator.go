@@ -0,0 +1,317 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"strings"
+)
+
+// IssueSeverity Defines how severe is the issue found during genome validation
+type IssueSeverity byte
+
+const (
+	// IssueWarning The genome can be used, but the issue is likely a mistake, e.g., the output can not be reached
+	IssueWarning IssueSeverity = iota + 1
+	// IssueError The genome is malformed and can not be used
+	IssueError
+)
+
+func (s IssueSeverity) String() string {
+	switch s {
+	case IssueWarning:
+		return "warning"
+	case IssueError:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown severity: %d", s)
+	}
+}
+
+// GenomeIssue The issue found during genome validation. The offending entities are referenced by their IDs, which
+// are zero if not related to the issue.
+type GenomeIssue struct {
+	// The severity of the issue
+	Severity IssueSeverity
+	// The description of the issue
+	Message string
+	// The ID of the offending node
+	NodeId int
+	// The innovation number of the offending connection gene
+	GeneInnovation int64
+	// The ID of the control node of the offending MIMO module
+	ModuleId int
+	// The ID of the offending trait
+	TraitId int
+}
+
+func (i *GenomeIssue) String() string {
+	var refs []string
+	if i.ModuleId != 0 {
+		refs = append(refs, fmt.Sprintf("module: %d", i.ModuleId))
+	}
+	if i.GeneInnovation != 0 {
+		refs = append(refs, fmt.Sprintf("gene: %d", i.GeneInnovation))
+	}
+	if i.NodeId != 0 {
+		refs = append(refs, fmt.Sprintf("node: %d", i.NodeId))
+	}
+	if i.TraitId != 0 {
+		refs = append(refs, fmt.Sprintf("trait: %d", i.TraitId))
+	}
+	if len(refs) == 0 {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: [%s] %s", i.Severity, strings.Join(refs, ", "), i.Message)
+}
+
+// GenomeIssues The list of issues found during genome validation
+type GenomeIssues []*GenomeIssue
+
+// HasErrors Returns true if there is at least one issue with IssueError severity
+func (gi GenomeIssues) HasErrors() bool {
+	for _, issue := range gi {
+		if issue.Severity == IssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// GenomeValidationError The error holding all issues found during validation of the genome
+type GenomeValidationError struct {
+	// The ID of invalid genome
+	GenomeId int
+	// The list of found issues, including warnings
+	Issues GenomeIssues
+}
+
+func (e *GenomeValidationError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = issue.String()
+	}
+	return fmt.Sprintf("invalid genome %d, %d issue(s) found:\n\t%s", e.GenomeId, len(e.Issues),
+		strings.Join(lines, "\n\t"))
+}
+
+// Validate Checks the genome and returns all found issues in order of the genome parts: traits, nodes, genes and
+// MIMO modules, followed by the reachability of outputs. The following issues are reported as errors: missing genes,
+// nodes or traits, duplicate IDs of traits and nodes, duplicate innovation numbers, duplicate links, references to
+// the nodes and traits missing in the genome, links into the sensors, and MIMO modules with missing I/O nodes. The
+// nodes out of order and the outputs unreachable from sensors are reported as warnings.
+func (g *Genome) Validate() GenomeIssues {
+	v := genomeValidator{genome: g}
+	v.checkTraits()
+	v.checkNodes()
+	v.checkGenes()
+	v.checkModules()
+	v.checkOutputsReachable()
+	return v.issues
+}
+
+// genomeValidator accumulates issues found in the genome
+type genomeValidator struct {
+	genome *Genome
+	issues GenomeIssues
+	// the innovation numbers of genes and modules seen so far
+	innovations map[int64]bool
+}
+
+func (v *genomeValidator) report(issue *GenomeIssue) {
+	v.issues = append(v.issues, issue)
+}
+
+func (v *genomeValidator) checkTraits() {
+	if len(v.genome.Traits) == 0 {
+		v.report(&GenomeIssue{Severity: IssueError, Message: "genome has no traits"})
+	}
+	seen := make(map[int]bool, len(v.genome.Traits))
+	for _, trait := range v.genome.Traits {
+		if seen[trait.Id] {
+			v.report(&GenomeIssue{Severity: IssueError, TraitId: trait.Id, Message: "trait ID is not unique"})
+		}
+		seen[trait.Id] = true
+	}
+}
+
+func (v *genomeValidator) checkNodes() {
+	if len(v.genome.Nodes) == 0 {
+		v.report(&GenomeIssue{Severity: IssueError, Message: "genome has no nodes"})
+	}
+	seen := make(map[int]bool, len(v.genome.Nodes))
+	lastId := 0
+	for _, node := range v.genome.Nodes {
+		if seen[node.Id] {
+			v.report(&GenomeIssue{Severity: IssueError, NodeId: node.Id, Message: "node ID is not unique"})
+		} else if node.Id < lastId {
+			v.report(&GenomeIssue{Severity: IssueWarning, NodeId: node.Id,
+				Message: fmt.Sprintf("node is out of order, it follows node: %d", lastId)})
+		}
+		seen[node.Id] = true
+		lastId = node.Id
+		if v.isMissingTrait(node.Trait) {
+			v.report(&GenomeIssue{Severity: IssueError, NodeId: node.Id, TraitId: node.Trait.Id,
+				Message: "node refers to the trait missing in the genome"})
+		}
+	}
+}
+
+func (v *genomeValidator) checkGenes() {
+	if len(v.genome.Genes) == 0 {
+		v.report(&GenomeIssue{Severity: IssueError, Message: "genome has no genes"})
+	}
+	v.innovations = make(map[int64]bool, len(v.genome.Genes)+len(v.genome.ControlGenes))
+	type linkKey struct {
+		in, out   int
+		recurrent bool
+	}
+	links := make(map[linkKey]int64, len(v.genome.Genes))
+	for _, gene := range v.genome.Genes {
+		innov := gene.InnovationNum
+		if v.innovations[innov] {
+			v.report(&GenomeIssue{Severity: IssueError, GeneInnovation: innov, Message: "innovation number is not unique"})
+		}
+		v.innovations[innov] = true
+
+		link := gene.Link
+		if link == nil {
+			v.report(&GenomeIssue{Severity: IssueError, GeneInnovation: innov, Message: "gene has no link"})
+			continue
+		}
+		if v.isMissingTrait(link.Trait) {
+			v.report(&GenomeIssue{Severity: IssueError, GeneInnovation: innov, TraitId: link.Trait.Id,
+				Message: "gene refers to the trait missing in the genome"})
+		}
+		inOk := v.checkNodeRef(link.InNode, &GenomeIssue{GeneInnovation: innov}, "input node of gene")
+		outOk := v.checkNodeRef(link.OutNode, &GenomeIssue{GeneInnovation: innov}, "output node of gene")
+		if !inOk || !outOk {
+			continue
+		}
+		if link.OutNode.IsSensor() {
+			v.report(&GenomeIssue{Severity: IssueError, GeneInnovation: innov, NodeId: link.OutNode.Id,
+				Message: fmt.Sprintf("gene links into the sensor node from node: %d", link.InNode.Id)})
+		}
+		key := linkKey{in: link.InNode.Id, out: link.OutNode.Id, recurrent: link.IsRecurrent}
+		if prev, ok := links[key]; ok {
+			v.report(&GenomeIssue{Severity: IssueError, GeneInnovation: innov,
+				Message: fmt.Sprintf("duplicate link [%d -> %d] of gene: %d", key.in, key.out, prev)})
+		} else {
+			links[key] = innov
+		}
+	}
+}
+
+func (v *genomeValidator) checkModules() {
+	modules := make(map[int]bool, len(v.genome.ControlGenes))
+	for _, module := range v.genome.ControlGenes {
+		control := module.ControlNode
+		if control == nil {
+			v.report(&GenomeIssue{Severity: IssueError, GeneInnovation: module.InnovationNum,
+				Message: "MIMO module has no control node"})
+			continue
+		}
+		if NodeWithId(control.Id, v.genome.Nodes) != nil || modules[control.Id] {
+			v.report(&GenomeIssue{Severity: IssueError, ModuleId: control.Id, Message: "control node ID is not unique"})
+		}
+		modules[control.Id] = true
+		if v.innovations[module.InnovationNum] {
+			v.report(&GenomeIssue{Severity: IssueError, ModuleId: control.Id,
+				Message: fmt.Sprintf("innovation number is not unique: %d", module.InnovationNum)})
+		}
+		v.innovations[module.InnovationNum] = true
+		if v.isMissingTrait(control.Trait) {
+			v.report(&GenomeIssue{Severity: IssueError, ModuleId: control.Id, TraitId: control.Trait.Id,
+				Message: "MIMO module refers to the trait missing in the genome"})
+		}
+		if len(control.Incoming) == 0 {
+			v.report(&GenomeIssue{Severity: IssueError, ModuleId: control.Id, Message: "MIMO module has no inputs"})
+		}
+		for _, link := range control.Incoming {
+			v.checkNodeRef(link.InNode, &GenomeIssue{ModuleId: control.Id}, "MIMO input node")
+		}
+		if len(control.Outgoing) == 0 {
+			v.report(&GenomeIssue{Severity: IssueError, ModuleId: control.Id, Message: "MIMO module has no outputs"})
+		}
+		for _, link := range control.Outgoing {
+			if v.checkNodeRef(link.OutNode, &GenomeIssue{ModuleId: control.Id}, "MIMO output node") &&
+				link.OutNode.IsSensor() {
+				v.report(&GenomeIssue{Severity: IssueError, ModuleId: control.Id, NodeId: link.OutNode.Id,
+					Message: "MIMO module outputs into the sensor node"})
+			}
+		}
+	}
+}
+
+// checkOutputsReachable Reports the output nodes which can not be reached from sensors through the enabled genes and
+// MIMO modules
+func (v *genomeValidator) checkOutputsReachable() {
+	reached := make(map[int]bool, len(v.genome.Nodes))
+	for _, node := range v.genome.Nodes {
+		if node.IsSensor() {
+			reached[node.Id] = true
+		}
+	}
+	// propagate until nothing changes, every pass reaches at least one new node
+	for changed := true; changed; {
+		changed = false
+		for _, gene := range v.genome.Genes {
+			if !gene.IsEnabled || gene.Link == nil || gene.Link.InNode == nil || gene.Link.OutNode == nil {
+				continue
+			}
+			if reached[gene.Link.InNode.Id] && !reached[gene.Link.OutNode.Id] {
+				reached[gene.Link.OutNode.Id] = true
+				changed = true
+			}
+		}
+		for _, module := range v.genome.ControlGenes {
+			if !module.IsEnabled || module.ControlNode == nil || !moduleInputReached(module.ControlNode, reached) {
+				continue
+			}
+			for _, link := range module.ControlNode.Outgoing {
+				if link.OutNode != nil && !reached[link.OutNode.Id] {
+					reached[link.OutNode.Id] = true
+					changed = true
+				}
+			}
+		}
+	}
+	for _, node := range v.genome.Nodes {
+		if node.NeuronType == network.OutputNeuron && !reached[node.Id] {
+			v.report(&GenomeIssue{Severity: IssueWarning, NodeId: node.Id,
+				Message: "output node can not be reached from sensors"})
+		}
+	}
+}
+
+// checkNodeRef Reports the issue if referenced node is nil or missing in the genome. The issue is pre-filled with
+// references to the entity holding the node reference. Returns true if node is found.
+func (v *genomeValidator) checkNodeRef(node *network.NNode, issue *GenomeIssue, what string) bool {
+	issue.Severity = IssueError
+	if node == nil {
+		issue.Message = fmt.Sprintf("%s is not set", what)
+		v.report(issue)
+		return false
+	}
+	if NodeWithId(node.Id, v.genome.Nodes) == nil {
+		issue.NodeId = node.Id
+		issue.Message = fmt.Sprintf("%s is missing in the genome", what)
+		v.report(issue)
+		return false
+	}
+	return true
+}
+
+// isMissingTrait Returns true if trait is set, but missing in the genome
+func (v *genomeValidator) isMissingTrait(trait *neat.Trait) bool {
+	return trait != nil && TraitWithId(trait.Id, v.genome.Traits) == nil
+}
+
+func moduleInputReached(control *network.NNode, reached map[int]bool) bool {
+	for _, link := range control.Incoming {
+		if link.InNode != nil && reached[link.InNode.Id] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/network"
+	"testing"
+)
+
+func TestGenome_Validate(t *testing.T) {
+	assert.Empty(t, buildTestGenome(1).Validate())
+	assert.Empty(t, buildTestModularGenome(1).Validate())
+
+	// links into sensor, missing nodes and trait, duplicates
+	gnome := buildTestGenome(1)
+	gnome.Genes = append(gnome.Genes,
+		NewConnectionGene(network.NewLink(1.0, gnome.Nodes[3], gnome.Nodes[0], true), 4, 0, true),
+		NewConnectionGene(network.NewLinkWithTrait(&neat.Trait{Id: 10}, 1.0, gnome.Nodes[0], gnome.Nodes[3], false), 5, 0, true),
+		NewGene(1.0, gnome.Nodes[0], network.NewNNode(100, network.OutputNeuron), false, 1, 0),
+		NewConnectionGene(network.NewLink(1.0, nil, gnome.Nodes[3], false), 6, 0, true),
+	)
+	issues := gnome.Validate()
+	expected := GenomeIssues{
+		{Severity: IssueError, GeneInnovation: 4, NodeId: 1, Message: "gene links into the sensor node from node: 4"},
+		{Severity: IssueError, GeneInnovation: 5, TraitId: 10, Message: "gene refers to the trait missing in the genome"},
+		{Severity: IssueError, GeneInnovation: 5, Message: "duplicate link [1 -> 4] of gene: 1"},
+		{Severity: IssueError, GeneInnovation: 1, Message: "innovation number is not unique"},
+		{Severity: IssueError, GeneInnovation: 1, NodeId: 100, Message: "output node of gene is missing in the genome"},
+		{Severity: IssueError, GeneInnovation: 6, Message: "input node of gene is not set"},
+	}
+	assert.Equal(t, expected, issues)
+	assert.True(t, issues.HasErrors())
+
+	// nodes out of order and unreachable output are just warnings
+	gnome = buildTestGenome(1)
+	gnome.Nodes[0], gnome.Nodes[1] = gnome.Nodes[1], gnome.Nodes[0]
+	gnome.Nodes = append(gnome.Nodes, network.NewNNode(5, network.OutputNeuron))
+	issues = gnome.Validate()
+	expected = GenomeIssues{
+		{Severity: IssueWarning, NodeId: 1, Message: "node is out of order, it follows node: 2"},
+		{Severity: IssueWarning, NodeId: 5, Message: "output node can not be reached from sensors"},
+	}
+	assert.Equal(t, expected, issues)
+	assert.False(t, issues.HasErrors())
+
+	// empty genome
+	issues = (&Genome{Id: 1}).Validate()
+	require.Len(t, issues, 3)
+	assert.Equal(t, "error: genome has no traits", issues[0].String())
+	assert.Equal(t, "error: genome has no nodes", issues[1].String())
+	assert.Equal(t, "error: genome has no genes", issues[2].String())
+}
+
+func TestGenome_Validate_modules(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	module := gnome.ControlGenes[0]
+	control := module.ControlNode
+	// the module reads from missing node, outputs to sensor, and has the ID of existing node
+	control.Incoming[0].InNode = network.NewNNode(50, network.HiddenNeuron)
+	control.Outgoing[0].OutNode = gnome.Nodes[0]
+	control.Id = 5
+	module.InnovationNum = 2
+
+	issues := gnome.Validate()
+	expected := GenomeIssues{
+		{Severity: IssueError, ModuleId: 5, Message: "control node ID is not unique"},
+		{Severity: IssueError, ModuleId: 5, Message: "innovation number is not unique: 2"},
+		{Severity: IssueError, ModuleId: 5, NodeId: 50, Message: "MIMO input node is missing in the genome"},
+		{Severity: IssueError, ModuleId: 5, NodeId: 1, Message: "MIMO module outputs into the sensor node"},
+	}
+	assert.Equal(t, expected, issues)
+	assert.Equal(t, "error: [module: 5, node: 50] MIMO input node is missing in the genome", issues[2].String())
+}
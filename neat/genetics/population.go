@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"github.com/yaricom/goNEAT/v2/neat"
 	"math"
-	"math/rand"
 	"sync"
 	"sync/atomic"
 )
@@ -35,6 +34,9 @@ type Population struct {
 	Variance    float64
 	StandardDev float64
 
+	// The timings and reproduction counters of the most recent epoch
+	EpochStats EpochStatistics
+
 	// For holding the genetic innovations of the newest generation
 	innovations []Innovation
 	// The next innovation number for population
@@ -44,6 +46,8 @@ type Population struct {
 
 	// The mutex to guard against concurrent modifications
 	mutex *sync.Mutex
+	// The listener of the population events
+	listener PopulationListener
 }
 
 // The auxiliary data type to hold results of parallel reproduction sent over the wires
@@ -56,12 +60,22 @@ type reproductionResult struct {
 
 // NewPopulation constructs off of a single spawning Genome
 func NewPopulation(g *Genome, opts *neat.Options) (*Population, error) {
+	return NewPopulationContext(opts.NeatContext(), g)
+}
+
+// NewPopulationContext constructs off of a single spawning Genome using NEAT options and the generator of random
+// numbers stored in the provided context. See neat.NewRandContext.
+func NewPopulationContext(ctx context.Context, g *Genome) (*Population, error) {
+	opts, found := neat.FromContext(ctx)
+	if !found {
+		return nil, neat.ErrNEATOptionsNotFound
+	}
 	if opts.PopSize <= 0 {
 		return nil, fmt.Errorf("wrong population size in the context: %d", opts.PopSize)
 	}
 
 	pop := newPopulation()
-	err := pop.spawn(g, opts)
+	err := pop.spawn(ctx, g, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -76,9 +90,11 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 		return nil, fmt.Errorf("wrong population size in the context: %d", opts.PopSize)
 	}
 
+	ctx := opts.NeatContext()
+	rng := neat.RandFromContext(ctx)
 	pop := newPopulation()
 	for count := 0; count < opts.PopSize; count++ {
-		gen := newGenomeRand(count, in, out, rand.Intn(maxHidden), maxHidden, recurrent, linkProb)
+		gen := newGenomeRand(rng, count, in, out, rng.Intn(maxHidden), maxHidden, recurrent, linkProb)
 		org, err := NewOrganism(0.0, gen, 1)
 		if err != nil {
 			return nil, err
@@ -88,7 +104,7 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 	pop.nextNodeId = int32(in + out + maxHidden + 1)
 	pop.nextInnovNum = int64((in+out+maxHidden)*(in+out+maxHidden) + 1)
 
-	err := pop.speciate(opts.NeatContext(), pop.Organisms)
+	err := pop.speciate(ctx, pop.Organisms)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +112,46 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 	return pop, nil
 }
 
+// NewPopulationFromGenomes Creates the population of organisms with provided genomes. The created organisms are
+// speciated according to the options and follow the order of the genomes.
+func NewPopulationFromGenomes(genomes []*Genome, opts *neat.Options) (*Population, error) {
+	if len(genomes) == 0 {
+		return nil, errors.New("no genomes provided to create population")
+	}
+	pop := newPopulation()
+	for _, g := range genomes {
+		if err := pop.addGenome(g); err != nil {
+			return nil, err
+		}
+	}
+	if err := pop.speciate(opts.NeatContext(), pop.Organisms); err != nil {
+		return nil, err
+	}
+	return pop, nil
+}
+
+// UpdateFitnessStatistics Calculates MeanFitness, Variance, and StandardDev of fitness scores of all organisms
+// in this population. It should be invoked after organisms were evaluated.
+func (p *Population) UpdateFitnessStatistics() {
+	if len(p.Organisms) == 0 {
+		p.MeanFitness, p.Variance, p.StandardDev = 0, 0, 0
+		return
+	}
+	total := 0.0
+	for _, org := range p.Organisms {
+		total += org.Fitness
+	}
+	p.MeanFitness = total / float64(len(p.Organisms))
+
+	sumSq := 0.0
+	for _, org := range p.Organisms {
+		diff := org.Fitness - p.MeanFitness
+		sumSq += diff * diff
+	}
+	p.Variance = sumSq / float64(len(p.Organisms))
+	p.StandardDev = math.Sqrt(p.Variance)
+}
+
 // Verify is to run verification on all Genomes in this Population (Debugging)
 func (p *Population) Verify() (bool, error) {
 	res := true
@@ -109,6 +165,33 @@ func (p *Population) Verify() (bool, error) {
 	return res, nil
 }
 
+// addGenome Adds new organism with given genome to the population and updates the next node ID and the next
+// innovation number of the population to not collide with ones of the genome
+func (p *Population) addGenome(g *Genome) error {
+	org, err := NewOrganism(0.0, g, 1)
+	if err != nil {
+		return err
+	}
+	p.Organisms = append(p.Organisms, org)
+
+	lastNodeId, err := g.getLastNodeId()
+	if err != nil {
+		return err
+	}
+	if p.nextNodeId < int32(lastNodeId) {
+		p.nextNodeId = int32(lastNodeId + 1)
+	}
+
+	lastGeneInnovNum, err := g.getNextGeneInnovNum()
+	if err != nil {
+		return err
+	}
+	if p.nextInnovNum < lastGeneInnovNum {
+		p.nextInnovNum = lastGeneInnovNum
+	}
+	return nil
+}
+
 // Default private constructor
 func newPopulation() *Population {
 	return &Population{
@@ -141,7 +224,8 @@ func (p *Population) Innovations() []Innovation {
 
 // Create a population from Genome g. The new Population will have the same topology as g
 // with link weights slightly perturbed from g's
-func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
+func (p *Population) spawn(ctx context.Context, g *Genome, opts *neat.Options) (err error) {
+	rng := neat.RandFromContext(ctx)
 	for count := 0; count < opts.PopSize; count++ {
 		// make genome duplicate for new organism
 		newGenome, err := g.duplicate(count)
@@ -149,7 +233,7 @@ func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
 			return err
 		}
 		// introduce initial mutations
-		if _, err = newGenome.mutateLinkWeights(1.0, 1.0, gaussianMutator); err != nil {
+		if _, err = newGenome.mutateLinkWeights(rng, 1.0, 1.0, gaussianMutator); err != nil {
 			return err
 		}
 		// create organism for new genome
@@ -173,7 +257,7 @@ func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
 	}
 
 	// Separate the new Population into species
-	err = p.speciate(opts.NeatContext(), p.Organisms)
+	err = p.speciate(ctx, p.Organisms)
 
 	return err
 }
@@ -182,12 +266,13 @@ func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
 // N.B. the mutated offspring of best species may be added to other more compatible species and as result
 // the best species from previous generation will be removed, but their offspring still be alive.
 // Returns error if best species died.
-func (p *Population) checkBestSpeciesAlive(bestSpeciesId int, bestSpeciesReproduced bool) error {
+func (p *Population) checkBestSpeciesAlive(ctx context.Context, bestSpeciesId int, bestSpeciesReproduced bool) error {
+	logger := neat.LoggerFromContext(ctx)
 	bestOk := false
 	var bestSpMaxFitness float64
 	for _, currSpecies := range p.Species {
-		if neat.LogLevel == neat.LogLevelDebug {
-			neat.DebugLog(fmt.Sprintf("POPULATION: %d <> %d\n", currSpecies.Id, bestSpeciesId))
+		if logger.Enabled(neat.LevelDebug) {
+			logger.Debug(fmt.Sprintf("POPULATION: %d <> %d", currSpecies.Id, bestSpeciesId), neat.LogKeySpecies, currSpecies.Id)
 		}
 
 		if currSpecies.Id == bestSpeciesId {
@@ -198,9 +283,9 @@ func (p *Population) checkBestSpeciesAlive(bestSpeciesId int, bestSpeciesReprodu
 	}
 	if !bestOk && !bestSpeciesReproduced {
 		return errors.New("best species died without offspring")
-	} else if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("POPULATION: The best survived species Id: %d, max fitness ever: %f",
-			bestSpeciesId, bestSpMaxFitness))
+	} else if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("POPULATION: The best survived species Id: %d, max fitness ever: %f",
+			bestSpeciesId, bestSpMaxFitness), neat.LogKeySpecies, bestSpeciesId)
 	}
 	return nil
 }
@@ -216,6 +301,7 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 	if !found {
 		return neat.ErrNEATOptionsNotFound
 	}
+	logger := neat.LoggerFromContext(ctx)
 
 	// Step through all given organisms and speciate them within the population
 	for _, currOrg := range organisms {
@@ -228,7 +314,7 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 
 		if len(p.Species) == 0 {
 			// Create the first species
-			createFirstSpecies(p, currOrg)
+			createFirstSpecies(ctx, p, currOrg)
 		} else {
 			if opts.CompatThreshold == 0 {
 				return errors.New("compatibility threshold is set to ZERO - will not find any compatible species")
@@ -250,9 +336,10 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 				}
 			}
 			if bestCompatible != nil && done {
-				if neat.LogLevel == neat.LogLevelDebug {
-					neat.DebugLog(fmt.Sprintf("POPULATION: Compatible species [%d] found for baby organism [%d]",
-						bestCompatible.Id, currOrg.Genotype.Id))
+				if logger.Enabled(neat.LevelDebug) {
+					logger.Debug(fmt.Sprintf("POPULATION: Compatible species [%d] found for baby organism [%d]",
+						bestCompatible.Id, currOrg.Genotype.Id),
+						neat.LogKeySpecies, bestCompatible.Id, neat.LogKeyOrganism, currOrg.Genotype.Id)
 				}
 				// Found compatible species, so add current organism to it
 				bestCompatible.addOrganism(currOrg)
@@ -260,7 +347,7 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 				currOrg.Species = bestCompatible
 			} else {
 				// If we didn't find a match, create a new species
-				createFirstSpecies(p, currOrg)
+				createFirstSpecies(ctx, p, currOrg)
 			}
 		}
 	}
@@ -270,7 +357,8 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 
 // Removes zero offspring species from this population, i.e. species which will not have any offspring organism belonging to it
 // after reproduction cycle due to its fitness stagnation
-func (p *Population) purgeZeroOffspringSpecies(generation int) {
+func (p *Population) purgeZeroOffspringSpecies(ctx context.Context, generation int) {
+	logger := neat.LoggerFromContext(ctx)
 	// Used to compute average fitness over all Organisms
 	total := 0.0
 	totalOrganisms := len(p.Organisms)
@@ -281,9 +369,9 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 	}
 	// The average modified fitness among ALL organisms
 	overallAverage := total / float64(totalOrganisms)
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf(
-			"POPULATION: Generation %d: overall average fitness = %.3f, # of organisms: %d, # of species: %d\n",
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf(
+			"POPULATION: Generation %d: overall average fitness = %.3f, # of organisms: %d, # of species: %d",
 			generation, overallAverage, len(p.Organisms), len(p.Species)))
 	}
 
@@ -305,8 +393,8 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 		sp.ExpectedOffspring, skim = sp.countOffspring(skim)
 		totalExpected += sp.ExpectedOffspring
 	}
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("POPULATION: Total expected offspring count: %d", totalExpected))
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("POPULATION: Total expected offspring count: %d", totalExpected))
 	}
 
 	// Need to make up for lost floating point precision in offspring assignment.
@@ -334,8 +422,8 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 		// fitness. If the average fitness is allowed to hit 0, then we no longer have an average we can use to
 		// assign offspring.
 		if finalExpected < totalOrganisms {
-			if neat.LogLevel == neat.LogLevelDebug {
-				neat.DebugLog(fmt.Sprintf("POPULATION: Population died !!! (expected/total) %d/%d", finalExpected, totalOrganisms))
+			if logger.Enabled(neat.LevelDebug) {
+				logger.Debug(fmt.Sprintf("POPULATION: Population died !!! (expected/total) %d/%d", finalExpected, totalOrganisms))
 			}
 			for _, sp := range p.Species {
 				sp.ExpectedOffspring = 0
@@ -351,20 +439,22 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 	for _, sp := range p.Species {
 		if sp.ExpectedOffspring > 0 {
 			speciesToKeep = append(speciesToKeep, sp)
+		} else {
+			p.emit(SpeciesExtinctEvent, sp.Id, len(sp.Organisms))
 		}
 	}
 	p.Species = speciesToKeep
 }
 
 // When population stagnation detected the delta coding will be performed in attempt to fix this
-func (p *Population) deltaCoding(sortedSpecies []*Species, opts *neat.Options) {
-	neat.DebugLog("POPULATION: PERFORMING DELTA CODING TO FIX STAGNATION")
+func (p *Population) deltaCoding(ctx context.Context, sortedSpecies []*Species, opts *neat.Options) {
+	logger := neat.LoggerFromContext(ctx)
+	logger.Debug("POPULATION: PERFORMING DELTA CODING TO FIX STAGNATION")
 	p.EpochsHighestLastChanged = 0
 	halfPop := opts.PopSize / 2
 
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("half_pop: [%d] (pop_size - halfpop): [%d]\n",
-			halfPop, opts.PopSize-halfPop))
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("half_pop: [%d] (pop_size - halfpop): [%d]", halfPop, opts.PopSize-halfPop))
 	}
 
 	currSpecies := sortedSpecies[0]
@@ -385,17 +475,21 @@ func (p *Population) deltaCoding(sortedSpecies []*Species, opts *neat.Options) {
 		for i := 2; i < len(sortedSpecies); i++ {
 			sortedSpecies[i].ExpectedOffspring = 0
 		}
+		p.emit(DeltaCodingEvent, sortedSpecies[0].Id, 2)
 	} else {
 		currSpecies = sortedSpecies[0]
 		currSpecies.Organisms[0].superChampOffspring = opts.PopSize
 		currSpecies.ExpectedOffspring = opts.PopSize
 		currSpecies.AgeOfLastImprovement = currSpecies.Age
+		p.emit(DeltaCodingEvent, currSpecies.Id, 1)
 	}
 }
 
 // The system can take expected offspring away from worse species and give them
 // to superior species depending on the system parameter BabiesStolen (when BabiesStolen > 0)
-func (p *Population) giveBabiesToTheBest(sortedSpecies []*Species, opts *neat.Options) {
+func (p *Population) giveBabiesToTheBest(ctx context.Context, sortedSpecies []*Species, opts *neat.Options) {
+	logger := neat.LoggerFromContext(ctx)
+	rng := neat.RandFromContext(ctx)
 	stolenBabies := 0 // Babies taken from the bad species and given to the champs
 
 	// Take away a constant number of expected offspring from the worst few species
@@ -414,8 +508,11 @@ func (p *Population) giveBabiesToTheBest(sortedSpecies []*Species, opts *neat.Op
 		}
 	}
 
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("POPULATION: STOLEN BABIES: %d\n", stolenBabies))
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("POPULATION: STOLEN BABIES: %d", stolenBabies))
+	}
+	if stolenBabies > 0 {
+		p.emit(BabiesStolenEvent, sortedSpecies[0].Id, stolenBabies)
 	}
 
 	// Mark the best champions of the top species to be the super champs who will take on the extra
@@ -437,7 +534,7 @@ func (p *Population) giveBabiesToTheBest(sortedSpecies []*Species, opts *neat.Op
 			stolenBabies -= stolenBlocks[blockIndex]
 		} else if blockIndex >= 3 {
 			// Give stolen to the rest in random ratios
-			if rand.Float64() > 0.1 {
+			if rng.Float64() > 0.1 {
 				// Randomize a little which species get boosted by a super champ
 				if stolenBabies > 3 {
 					currSpecies.Organisms[0].superChampOffspring = 3
@@ -486,7 +583,8 @@ func (p *Population) purgeOrganisms() error {
 }
 
 // Destroy and remove the old generation of the organisms and of the species
-func (p *Population) purgeOldGeneration(bestSpeciesId int) error {
+func (p *Population) purgeOldGeneration(ctx context.Context, bestSpeciesId int) error {
+	logger := neat.LoggerFromContext(ctx)
 	for _, org := range p.Organisms {
 		// Remove the organism from its Species
 		_, err := org.Species.removeOrganism(org)
@@ -494,9 +592,10 @@ func (p *Population) purgeOldGeneration(bestSpeciesId int) error {
 			return err
 		}
 
-		if neat.LogLevel == neat.LogLevelDebug && org.Species.Id == bestSpeciesId {
-			neat.DebugLog(fmt.Sprintf("POPULATION: Removed organism [%d] from best species [%d] - %d organisms remained",
-				org.Genotype.Id, bestSpeciesId, len(org.Species.Organisms)))
+		if org.Species.Id == bestSpeciesId && logger.Enabled(neat.LevelDebug) {
+			logger.Debug(fmt.Sprintf("POPULATION: Removed organism [%d] from best species [%d] - %d organisms remained",
+				org.Genotype.Id, bestSpeciesId, len(org.Species.Organisms)),
+				neat.LogKeySpecies, bestSpeciesId, neat.LogKeyOrganism, org.Genotype.Id)
 		}
 	}
 	p.Organisms = make([]*Organism, 0)
@@ -506,7 +605,8 @@ func (p *Population) purgeOldGeneration(bestSpeciesId int) error {
 
 // Removes all empty Species and age ones that survive.
 // As this happens, create master organism list for the new generation.
-func (p *Population) purgeOrAgeSpecies() {
+func (p *Population) purgeOrAgeSpecies(ctx context.Context) {
+	logger := neat.LoggerFromContext(ctx)
 	orgCount := 0
 	speciesToKeep := make([]*Species, 0)
 	for _, currSpecies := range p.Species {
@@ -525,16 +625,17 @@ func (p *Population) purgeOrAgeSpecies() {
 			}
 			// keep this species
 			speciesToKeep = append(speciesToKeep, currSpecies)
-		} else if neat.LogLevel == neat.LogLevelDebug {
-			neat.DebugLog(fmt.Sprintf("POPULATION: >> Species [%d] have not survived reproduction!",
-				currSpecies.Id))
+		} else {
+			logger.Debug(fmt.Sprintf("POPULATION: >> Species [%d] have not survived reproduction!", currSpecies.Id),
+				neat.LogKeySpecies, currSpecies.Id)
+			p.emit(SpeciesExtinctEvent, currSpecies.Id, len(currSpecies.Organisms))
 		}
 	}
 	// Keep only survived species
 	p.Species = speciesToKeep
 
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("POPULATION: # of species survived: %d, # of organisms survived: %d\n",
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("POPULATION: # of species survived: %d, # of organisms survived: %d",
 			len(p.Species), len(p.Organisms)))
 	}
 }
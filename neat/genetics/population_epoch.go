@@ -6,8 +6,10 @@ import (
 	"encoding/gob"
 	"fmt"
 	"github.com/yaricom/goNEAT/v2/neat"
+	"math/rand"
 	"sort"
 	"sync"
+	"time"
 )
 
 // PopulationEpochExecutor Executes epoch's turnover for a population of the organisms
@@ -24,27 +26,38 @@ type SequentialPopulationEpochExecutor struct {
 }
 
 func (s *SequentialPopulationEpochExecutor) NextEpoch(ctx context.Context, generation int, population *Population) error {
+	ctx = epochLoggerContext(ctx, generation)
+	population.resetEpochStats()
+	startTime := time.Now()
 	err := s.prepareForReproduction(ctx, generation, population)
 	if err != nil {
 		return err
 	}
+	population.EpochStats.FitnessAdjustment = time.Since(startTime)
+
 	err = s.reproduce(ctx, generation, population)
 	if err != nil {
 		return err
 	}
 	err = s.finalizeReproduction(ctx, population)
 
-	neat.DebugLog(fmt.Sprintf("POPULATION: >>>>> Epoch %d complete\n", generation))
+	neat.LoggerFromContext(ctx).Debug(fmt.Sprintf("POPULATION: >>>>> Epoch %d complete", generation))
 
 	return err
 }
 
+// epochLoggerContext returns the context which logger includes the generation ID into each record of the epoch
+func epochLoggerContext(ctx context.Context, generation int) context.Context {
+	return neat.NewLoggerContext(ctx, neat.LoggerFromContext(ctx).With(neat.LogKeyGeneration, generation))
+}
+
 // prepareForReproduction is to prepareForReproduction population for reproduction
 func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.Context, generation int, p *Population) error {
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return neat.ErrNEATOptionsNotFound
 	}
+	logger := neat.LoggerFromContext(ctx)
 
 	// clear executor state from previous run
 	s.sortedSpecies = nil
@@ -58,7 +71,7 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 	}
 
 	// find and remove species unable to produce offspring due to fitness stagnation
-	p.purgeZeroOffspringSpecies(generation)
+	p.purgeZeroOffspringSpecies(ctx, generation)
 
 	// Stick the Species pointers into a new Species list for sorting
 	s.sortedSpecies = make([]*Species, len(p.Species))
@@ -70,16 +83,16 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 	// Used in debugging to see why (if) best species dies
 	s.bestSpeciesId = s.sortedSpecies[0].Id
 
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog("POPULATION: >> Sorted Species START <<")
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug("POPULATION: >> Sorted Species START <<")
 		for _, sp := range s.sortedSpecies {
 			// Print out for Debugging/viewing what's going on
-			neat.DebugLog(
-				fmt.Sprintf("POPULATION: >> Orig. fitness of Species %d (Size %d): %f, current fitness: %f, expected offspring: %d, last improved %d \n",
+			logger.Debug(
+				fmt.Sprintf("POPULATION: >> Orig. fitness of Species %d (Size %d): %f, current fitness: %f, expected offspring: %d, last improved %d",
 					sp.Id, len(sp.Organisms), sp.Organisms[0].originalFitness, sp.Organisms[0].Fitness, sp.ExpectedOffspring,
-					sp.Age-sp.AgeOfLastImprovement))
+					sp.Age-sp.AgeOfLastImprovement), neat.LogKeySpecies, sp.Id)
 		}
-		neat.DebugLog("POPULATION: >> Sorted Species END <<\n")
+		logger.Debug("POPULATION: >> Sorted Species END <<")
 	}
 
 	// Check for Population-level stagnation
@@ -88,24 +101,26 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 	if currSpecies.Organisms[0].originalFitness > p.HighestFitness {
 		p.HighestFitness = currSpecies.Organisms[0].originalFitness
 		p.EpochsHighestLastChanged = 0
-		if neat.LogLevel == neat.LogLevelDebug {
-			neat.DebugLog(fmt.Sprintf("POPULATION: NEW POPULATION RECORD FITNESS: %f of SPECIES with ID: %d\n", p.HighestFitness, s.bestSpeciesId))
+		if logger.Enabled(neat.LevelDebug) {
+			logger.Debug(fmt.Sprintf("POPULATION: NEW POPULATION RECORD FITNESS: %f of SPECIES with ID: %d", p.HighestFitness, s.bestSpeciesId),
+				neat.LogKeySpecies, s.bestSpeciesId)
 		}
 	} else {
 		p.EpochsHighestLastChanged += 1
-		if neat.LogLevel == neat.LogLevelDebug {
-			neat.DebugLog(fmt.Sprintf(" generations since last population fitness record: %f\n", p.HighestFitness))
+		if logger.Enabled(neat.LevelDebug) {
+			logger.Debug(fmt.Sprintf("POPULATION: generations since last population fitness record: %d, record fitness: %f",
+				p.EpochsHighestLastChanged, p.HighestFitness))
 		}
 	}
 
 	// Check for stagnation - if there is stagnation, perform delta-coding
 	if p.EpochsHighestLastChanged >= opts.DropOffAge+5 {
 		// Population stagnated - trying to fix it by delta coding
-		p.deltaCoding(s.sortedSpecies, opts)
+		p.deltaCoding(ctx, s.sortedSpecies, opts)
 	} else if opts.BabiesStolen > 0 {
 		// STOLEN BABIES: The system can take expected offspring away from worse species and give them
 		// to superior species depending on the system parameter BabiesStolen (when BabiesStolen > 0)
-		p.giveBabiesToTheBest(s.sortedSpecies, opts)
+		p.giveBabiesToTheBest(ctx, s.sortedSpecies, opts)
 	}
 
 	// Kill off all Organisms marked for death. The remainder will be allowed to reproduce.
@@ -115,13 +130,15 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 
 // reproduce is to run the reproduction cycle
 func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, generation int, p *Population) error {
-	neat.DebugLog("POPULATION: Start Sequential Reproduction Cycle >>>>>")
+	logger := neat.LoggerFromContext(ctx)
+	logger.Debug("POPULATION: Start Sequential Reproduction Cycle >>>>>")
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return neat.ErrNEATOptionsNotFound
 	}
 
 	// Perform reproduction. Reproduction is done on a per-Species basis
+	startTime := time.Now()
 	babies := make([]*Organism, 0)
 
 	for _, sp := range p.Species {
@@ -139,6 +156,8 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 		babies = append(babies, repBabies...)
 	}
 
+	p.EpochStats.Reproduction = time.Since(startTime)
+
 	// sanity check - make sure that population size keep the same
 	if len(babies) != opts.PopSize {
 		return fmt.Errorf("progeny size after reproduction cycle dimished, expected: [%d], but got: [%d]",
@@ -146,38 +165,40 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 	}
 
 	// speciate fresh progeny
+	startTime = time.Now()
 	err := p.speciate(ctx, babies)
+	p.EpochStats.Speciation = time.Since(startTime)
 
-	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
+	logger.Debug("POPULATION: >>>>> Reproduction Complete")
 
 	return err
 }
 
 // finalizeReproduction is to finalizeReproduction reproduction cycle
-func (s *SequentialPopulationEpochExecutor) finalizeReproduction(_ context.Context, pop *Population) error {
+func (s *SequentialPopulationEpochExecutor) finalizeReproduction(ctx context.Context, pop *Population) error {
 	// Destroy and remove the old generation from the organisms and species
-	err := pop.purgeOldGeneration(s.bestSpeciesId)
+	err := pop.purgeOldGeneration(ctx, s.bestSpeciesId)
 	if err != nil {
 		return err
 	}
 
 	// Removes all empty Species and age ones that survive.
 	// As this happens, create master organism list for the new generation.
-	pop.purgeOrAgeSpecies()
+	pop.purgeOrAgeSpecies(ctx)
 
 	// Remove the innovations of the current generation
 	pop.innovations = make([]Innovation, 0)
 
 	// Check to see if the best species died somehow. We don't want this to happen!!!
-	err = pop.checkBestSpeciesAlive(s.bestSpeciesId, s.bestSpeciesReproduced)
+	err = pop.checkBestSpeciesAlive(ctx, s.bestSpeciesId, s.bestSpeciesReproduced)
 
 	// DEBUG: Checking the top organism's duplicate in the next gen
 	// This prints the champ's child to the screen
-	if neat.LogLevel == neat.LogLevelDebug && err != nil {
+	if logger := neat.LoggerFromContext(ctx); err != nil && logger.Enabled(neat.LevelDebug) {
 		for _, org := range pop.Organisms {
 			if org.isPopulationChampionChild {
-				neat.DebugLog(fmt.Sprintf("POPULATION: At end of reproduction cycle, the child of the pop champ is: %s",
-					org.Genotype))
+				logger.Debug(fmt.Sprintf("POPULATION: At end of reproduction cycle, the child of the pop champ is: %s",
+					org.Genotype), neat.LogKeyOrganism, org.Genotype.Id)
 			}
 		}
 	}
@@ -190,11 +211,15 @@ type ParallelPopulationEpochExecutor struct {
 }
 
 func (p *ParallelPopulationEpochExecutor) NextEpoch(ctx context.Context, generation int, population *Population) error {
+	ctx = epochLoggerContext(ctx, generation)
 	p.sequential = &SequentialPopulationEpochExecutor{}
+	population.resetEpochStats()
+	startTime := time.Now()
 	err := p.sequential.prepareForReproduction(ctx, generation, population)
 	if err != nil {
 		return err
 	}
+	population.EpochStats.FitnessAdjustment = time.Since(startTime)
 
 	// Do parallel reproduction
 	err = p.reproduce(ctx, generation, population)
@@ -204,27 +229,32 @@ func (p *ParallelPopulationEpochExecutor) NextEpoch(ctx context.Context, generat
 
 	err = p.sequential.finalizeReproduction(ctx, population)
 
-	neat.DebugLog(fmt.Sprintf("POPULATION: >>>>> Epoch %d complete\n", generation))
+	neat.LoggerFromContext(ctx).Debug(fmt.Sprintf("POPULATION: >>>>> Epoch %d complete", generation))
 
 	return err
 }
 
 // Do parallel reproduction cycle
 func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generation int, pop *Population) error {
-	neat.DebugLog("POPULATION: Start Parallel Reproduction Cycle >>>>>")
+	logger := neat.LoggerFromContext(ctx)
+	logger.Debug("POPULATION: Start Parallel Reproduction Cycle >>>>>")
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return neat.ErrNEATOptionsNotFound
 	}
 
 	// Perform reproduction. Reproduction is done on a per-Species basis
+	startTime := time.Now()
 	spNum := len(pop.Species)
 	resChan := make(chan reproductionResult, spNum)
 	// The wait group to wait for all GO routines
 	var wg sync.WaitGroup
 
+	// the generator of random numbers is not safe for concurrent use, thus each species gets its own one
+	rng := neat.RandFromContext(ctx)
 	for _, species := range pop.Species {
 		wg.Add(1)
+		spCtx := neat.NewRandContext(ctx, rand.New(rand.NewSource(rng.Int63())))
 		// run in separate GO thread
 		go func(ctx context.Context, sp *Species, generation int, p *Population, sortedSpecies []*Species, resChan chan<- reproductionResult, wg *sync.WaitGroup) {
 			defer wg.Done()
@@ -252,7 +282,7 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 			// write result to channel and signal to wait group
 			resChan <- res
 
-		}(ctx, species, generation, pop, p.sequential.sortedSpecies, resChan, &wg)
+		}(spCtx, species, generation, pop, p.sequential.sortedSpecies, resChan, &wg)
 	}
 
 	// wait for reproduction results
@@ -281,6 +311,7 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 			p.sequential.bestSpeciesReproduced = babies != nil
 		}
 	}
+	pop.EpochStats.Reproduction = time.Since(startTime)
 
 	// sanity check - make sure that population size keep the same
 	if len(babies) != opts.PopSize {
@@ -289,9 +320,11 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 	}
 
 	// speciate fresh progeny
+	startTime = time.Now()
 	err := pop.speciate(ctx, babies)
+	pop.EpochStats.Speciation = time.Since(startTime)
 
-	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
+	logger.Debug("POPULATION: >>>>> Reproduction Complete")
 
 	return err
 }
@@ -1,11 +1,15 @@
 package genetics
 
 import (
+	"bytes"
+	"encoding/json"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/math"
 	"math/rand"
+	"strings"
 	"testing"
 )
 
@@ -46,7 +50,7 @@ func TestPopulationEpochExecutor_NextEpoch(t *testing.T) {
 		RecurOnlyProb:   0.2,
 	}
 	neat.LogLevel = neat.LogLevelInfo
-	gen := newGenomeRand(1, in, out, n, nmax, false, linkProb)
+	gen := newGenomeRand(math.GlobalRand(), 1, in, out, n, nmax, false, linkProb)
 	pop, err := NewPopulation(gen, &conf)
 	require.NoError(t, err, "failed to create population")
 	require.NotNil(t, pop, "population expected")
@@ -59,3 +63,79 @@ func TestPopulationEpochExecutor_NextEpoch(t *testing.T) {
 	err = parallelExecutorNextEpoch(pop, &conf)
 	assert.NoError(t, err, "failed to run parallel epoch executor")
 }
+
+func TestPopulationEpochExecutor_EpochStats(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.Options{
+		CompatThreshold:       0.5,
+		DropOffAge:            15,
+		PopSize:               30,
+		SurvivalThresh:        0.5,
+		MutateOnlyProb:        0.5,
+		MutateAddNodeProb:     0.1,
+		MutateAddLinkProb:     0.2,
+		MutateLinkWeightsProb: 0.9,
+		MateMultipointProb:    0.6,
+		MateMultipointAvgProb: 0.4,
+		NodeActivators:        []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb:    []float64{1.0},
+	}
+	executors := map[string]PopulationEpochExecutor{
+		"sequential": &SequentialPopulationEpochExecutor{},
+		"parallel":   &ParallelPopulationEpochExecutor{},
+	}
+	for name, executor := range executors {
+		t.Run(name, func(t *testing.T) {
+			gen := newGenomeRand(math.GlobalRand(), 1, 3, 2, 3, 5, false, 0.8)
+			pop, err := NewPopulation(gen, &conf)
+			require.NoError(t, err, "failed to create population")
+			for _, org := range pop.Organisms {
+				org.Fitness = rand.Float64()
+			}
+
+			err = executor.NextEpoch(conf.NeatContext(), 1, pop)
+			require.NoError(t, err, "failed to execute epoch")
+
+			stats := pop.EpochStats
+			assert.True(t, stats.Reproduction > 0, "reproduction duration expected")
+			assert.True(t, stats.Speciation > 0, "speciation duration expected")
+			assert.NotEmpty(t, stats.SpeciesReproduction, "species reproduction durations expected")
+			assert.True(t, stats.Mutations.Total() > 0, "mutations expected")
+			assert.True(t, stats.Matings.Total() > 0, "matings expected")
+			assert.True(t, stats.Mutations.Total()+stats.Matings.Total() <= 2*conf.PopSize)
+		})
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_Logger(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.Options{
+		CompatThreshold: 0.5,
+		DropOffAge:      1,
+		PopSize:         30,
+		BabiesStolen:    10,
+		RecurOnlyProb:   0.2,
+	}
+	gen := newGenomeRand(math.GlobalRand(), 1, 3, 2, 3, 15, false, 0.8)
+	pop, err := NewPopulation(gen, &conf)
+	require.NoError(t, err, "failed to create population")
+
+	var buf bytes.Buffer
+	ctx := neat.NewLoggerContext(conf.NeatContext(), neat.NewLogger(neat.NewJSONHandler(&buf, neat.LevelDebug)))
+	ex := SequentialPopulationEpochExecutor{}
+	err = ex.NextEpoch(ctx, 7, pop)
+	require.NoError(t, err, "failed to run epoch")
+
+	speciesRecords := 0
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		record := make(map[string]interface{})
+		require.NoError(t, decoder.Decode(&record), "failed to decode log record")
+		assert.EqualValues(t, 7, record[neat.LogKeyGeneration], "no generation in record: %v", record)
+		if strings.HasPrefix(record["msg"].(string), "SPECIES:") {
+			assert.Contains(t, record, neat.LogKeySpecies, "no species in record: %v", record)
+			speciesRecords++
+		}
+	}
+	assert.True(t, speciesRecords > 0, "records of species reproduction expected")
+}
@@ -0,0 +1,43 @@
+package genetics
+
+// PopulationEventType the type of the event emitted by the Population during its evolution
+type PopulationEventType string
+
+// The types of events emitted by the Population
+const (
+	// SpeciesCreatedEvent emitted when new species created during speciation
+	SpeciesCreatedEvent PopulationEventType = "species_created"
+	// SpeciesExtinctEvent emitted when species removed from population due to stagnation or lack of offspring
+	SpeciesExtinctEvent PopulationEventType = "species_extinct"
+	// DeltaCodingEvent emitted when delta coding applied to fix population stagnation
+	DeltaCodingEvent PopulationEventType = "delta_coding"
+	// BabiesStolenEvent emitted when expected offspring taken away from worse species and given to the best ones
+	BabiesStolenEvent PopulationEventType = "babies_stolen"
+)
+
+// PopulationEvent the event emitted by the Population during its evolution
+type PopulationEvent struct {
+	// The type of the event
+	Type PopulationEventType
+	// The ID of the species related to the event if any
+	SpeciesId int
+	// The event specific count: the number of species' organisms for species events, the number of
+	// species left for delta coding, and the number of stolen babies for babies stolen event.
+	Count int
+}
+
+// PopulationListener the listener of events emitted by the Population. It is invoked synchronously from the goroutine
+// executing the population's epoch.
+type PopulationListener func(event PopulationEvent)
+
+// SetListener Sets the listener to be notified about events of this population. Use nil to remove listener.
+func (p *Population) SetListener(listener PopulationListener) {
+	p.listener = listener
+}
+
+// emit Notifies the population's listener about event if any listener set
+func (p *Population) emit(eventType PopulationEventType, speciesId, count int) {
+	if p.listener != nil {
+		p.listener(PopulationEvent{Type: eventType, SpeciesId: speciesId, Count: count})
+	}
+}
@@ -41,25 +41,7 @@ func ReadPopulation(ir io.Reader, options *neat.Options) (pop *Population, err e
 				return nil, err
 			}
 			// add new organism for read genome
-			if newOrganism, err := NewOrganism(0.0, newGenome, 1); err != nil {
-				return nil, err
-			} else {
-				pop.Organisms = append(pop.Organisms, newOrganism)
-			}
-
-			if lastNodeId, err := newGenome.getLastNodeId(); err == nil {
-				if pop.nextNodeId < int32(lastNodeId) {
-					pop.nextNodeId = int32(lastNodeId + 1)
-				}
-			} else {
-				return nil, err
-			}
-
-			if lastGeneInnovNum, err := newGenome.getNextGeneInnovNum(); err == nil {
-				if pop.nextInnovNum < lastGeneInnovNum {
-					pop.nextInnovNum = lastGeneInnovNum
-				}
-			} else {
+			if err = pop.addGenome(newGenome); err != nil {
 				return nil, err
 			}
 			// clear buffer
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/math"
 	"math/rand"
 	"strings"
 	"testing"
@@ -41,7 +42,7 @@ func TestNewPopulation(t *testing.T) {
 		CompatThreshold: 0.5,
 		PopSize:         10,
 	}
-	gen := newGenomeRand(1, in, out, n, nmax, false, linkProb)
+	gen := newGenomeRand(math.GlobalRand(), 1, in, out, n, nmax, false, linkProb)
 
 	pop, err := NewPopulation(gen, &conf)
 	require.NoError(t, err, "failed to create population")
@@ -64,6 +65,31 @@ func TestNewPopulation(t *testing.T) {
 	}
 }
 
+func TestNewPopulationFromGenomes(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.Options{CompatThreshold: 0.5}
+	genomes := []*Genome{
+		newGenomeRand(math.GlobalRand(), 1, 3, 2, 3, 5, false, 0.5),
+		newGenomeRand(math.GlobalRand(), 2, 3, 2, 5, 5, false, 0.5),
+	}
+
+	pop, err := NewPopulationFromGenomes(genomes, &conf)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, len(genomes), "wrong population size")
+	for i, org := range pop.Organisms {
+		assert.Equal(t, genomes[i], org.Genotype, "wrong genome at: %d", i)
+		assert.NotNil(t, org.Phenotype, "organism has no phenotype at: %d", i)
+	}
+	assert.True(t, len(pop.Species) > 0, "population has no species")
+
+	lastNodeId, err := genomes[1].getLastNodeId()
+	require.NoError(t, err, "failed to get last node ID")
+	assert.True(t, pop.nextNodeId > int32(lastNodeId), "wrong next node ID")
+
+	_, err = NewPopulationFromGenomes(nil, &conf)
+	assert.Error(t, err, "error expected for empty genomes")
+}
+
 func TestPopulation_verify(t *testing.T) {
 	// first create population
 	popStr := "genomestart 1\n" +
@@ -102,3 +128,64 @@ func TestPopulation_verify(t *testing.T) {
 	require.NoError(t, err, "failed to verify population")
 	assert.True(t, res, "Population verification failed, but must not")
 }
+
+func TestPopulation_SetListener(t *testing.T) {
+	rand.Seed(42)
+	conf := neat.Options{
+		CompatThreshold: 0.5,
+		DisjointCoeff:   1.0,
+		ExcessCoeff:     1.0,
+		PopSize:         10,
+	}
+	var events []PopulationEvent
+	listener := func(event PopulationEvent) {
+		events = append(events, event)
+	}
+
+	pop, err := NewPopulationRandom(3, 2, 5, false, 0.5, &conf)
+	require.NoError(t, err, "failed to create population")
+	require.True(t, len(pop.Species) > 1, "at least two species expected")
+	pop.SetListener(listener)
+
+	// delta coding
+	pop.deltaCoding(conf.NeatContext(), pop.Species, &conf)
+	require.Len(t, events, 1)
+	assert.Equal(t, PopulationEvent{Type: DeltaCodingEvent, SpeciesId: pop.Species[0].Id, Count: 2}, events[0])
+
+	// species extinction
+	events = nil
+	extinct := pop.Species[len(pop.Species)-1]
+	extinct.Organisms = nil
+	pop.Organisms = nil
+	pop.purgeOrAgeSpecies(conf.NeatContext())
+	require.Len(t, events, 1)
+	assert.Equal(t, PopulationEvent{Type: SpeciesExtinctEvent, SpeciesId: extinct.Id}, events[0])
+
+	// species creation
+	events = nil
+	createFirstSpecies(conf.NeatContext(), pop, pop.Organisms[0])
+	require.Len(t, events, 1)
+	assert.Equal(t, PopulationEvent{Type: SpeciesCreatedEvent, SpeciesId: pop.LastSpecies, Count: 1}, events[0])
+
+	// no events without listener
+	events = nil
+	pop.SetListener(nil)
+	pop.deltaCoding(conf.NeatContext(), pop.Species, &conf)
+	assert.Len(t, events, 0)
+}
+
+func TestPopulation_UpdateFitnessStatistics(t *testing.T) {
+	pop := newPopulation()
+	for _, fitness := range []float64{2.0, 4.0, 4.0, 4.0, 5.0, 5.0, 7.0, 9.0} {
+		pop.Organisms = append(pop.Organisms, &Organism{Fitness: fitness})
+	}
+	pop.UpdateFitnessStatistics()
+	assert.Equal(t, 5.0, pop.MeanFitness)
+	assert.Equal(t, 4.0, pop.Variance)
+	assert.Equal(t, 2.0, pop.StandardDev)
+
+	pop.Organisms = nil
+	pop.UpdateFitnessStatistics()
+	assert.Zero(t, pop.MeanFitness)
+	assert.Zero(t, pop.StandardDev)
+}
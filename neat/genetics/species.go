@@ -7,8 +7,8 @@ import (
 	"github.com/yaricom/goNEAT/v2/neat"
 	"io"
 	"math"
-	"math/rand"
 	"sort"
+	"time"
 )
 
 // A Species is a group of similar Organisms.
@@ -254,6 +254,8 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 	if !found {
 		return nil, neat.ErrNEATOptionsNotFound
 	}
+	rng := neat.RandFromContext(ctx)
+	logger := neat.LoggerFromContext(ctx).With(neat.LogKeySpecies, s.Id)
 	//Check for a mistake
 	if s.ExpectedOffspring > 0 && len(s.Organisms) == 0 {
 		return nil, errors.New("attempt to reproduce out of empty species")
@@ -270,6 +272,11 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 	// Flag the preservation of the champion
 	champCloneDone := false
 
+	// The statistics of reproduction
+	startTime := time.Now()
+	var mutations MutationCounts
+	var matings MatingCounts
+
 	// Create the designated number of offspring for the Species one at a time
 	for count := 0; count < s.ExpectedOffspring; count++ {
 		// check if execution was canceled and exit
@@ -279,21 +286,20 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 		default:
 		}
 
-		if neat.LogLevel == neat.LogLevelDebug {
-			neat.DebugLog(fmt.Sprintf("SPECIES: Offspring #%d from %d, (species: %d)",
-				count, s.ExpectedOffspring, s.Id))
+		if logger.Enabled(neat.LevelDebug) {
+			logger.Debug(fmt.Sprintf("SPECIES: Offspring #%d from %d", count, s.ExpectedOffspring))
 		}
 		mutStructBaby, mateBaby := false, false
 
 		// Debug Trap
 		if s.ExpectedOffspring > opts.PopSize {
-			neat.WarnLog(fmt.Sprintf("SPECIES: Species [%d] expected offspring: %d exceeds population size limit: %d\n",
+			logger.Warn(fmt.Sprintf("SPECIES: Species [%d] expected offspring: %d exceeds population size limit: %d",
 				s.Id, s.ExpectedOffspring, opts.PopSize))
 		}
 
 		var baby *Organism
 		if theChamp.superChampOffspring > 0 {
-			neat.DebugLog("SPECIES: Reproduce super champion")
+			logger.Debug("SPECIES: Reproduce super champion")
 
 			// If we have a super_champ (Population champion), finish off some special clones
 			mom := theChamp
@@ -307,18 +313,21 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			// Note: Superchamp offspring only occur with stolen babies!
 			//      Settings used for published experiments did not use this
 			if theChamp.superChampOffspring > 1 {
-				if rand.Float64() < 0.8 || opts.MutateAddLinkProb == 0.0 {
+				if rng.Float64() < 0.8 || opts.MutateAddLinkProb == 0.0 {
 					// Make sure no links get added when the system has link adding disabled
-					if _, err = newGenome.mutateLinkWeights(opts.WeightMutPower, 1.0, gaussianMutator); err != nil {
+					if _, err = newGenome.mutateLinkWeights(rng, opts.WeightMutPower, 1.0, gaussianMutator); err != nil {
 						return nil, err
 					}
+					mutations.LinkWeights++
 				} else {
 					// Sometimes we add a link to a superchamp
 					if _, err = newGenome.Genesis(generation); err != nil {
 						return nil, err
 					}
-					if _, err = newGenome.mutateAddLink(pop, opts); err != nil {
+					if linkAdded, err := newGenome.mutateAddLink(rng, pop, opts); err != nil {
 						return nil, err
+					} else if linkAdded {
+						mutations.AddLink++
 					}
 					mutStructBaby = true
 				}
@@ -339,7 +348,7 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 
 			theChamp.superChampOffspring--
 		} else if !champCloneDone && s.ExpectedOffspring > 5 {
-			neat.DebugLog("SPECIES: Clone species champion")
+			logger.Debug("SPECIES: Clone species champion")
 
 			// If we have a Species champion, just clone it
 			mom := theChamp // Mom is the champ
@@ -356,11 +365,11 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				return nil, err
 			}
 
-		} else if rand.Float64() < opts.MutateOnlyProb || poolSize == 1 {
-			neat.DebugLog("SPECIES: Reproduce by applying random mutation:")
+		} else if rng.Float64() < opts.MutateOnlyProb || poolSize == 1 {
+			logger.Debug("SPECIES: Reproduce by applying random mutation:")
 
 			// Apply mutations
-			orgNum := rand.Int31n(int32(poolSize)) // select random mom
+			orgNum := rng.Int31n(int32(poolSize)) // select random mom
 			mom := s.Organisms[orgNum]
 			newGenome, err := mom.Genotype.duplicate(count)
 			if err != nil {
@@ -368,41 +377,49 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			}
 
 			// Do the mutation depending on probabilities of various mutations
-			if rand.Float64() < opts.MutateAddNodeProb {
-				neat.DebugLog("SPECIES: ---> mutateAddNode")
+			if rng.Float64() < opts.MutateAddNodeProb {
+				logger.Debug("SPECIES: ---> mutateAddNode")
 
 				// Mutate add node
-				if _, err = newGenome.mutateAddNode(pop, pop, opts); err != nil {
+				if nodeAdded, err := newGenome.mutateAddNode(rng, pop, pop, opts); err != nil {
 					return nil, err
+				} else if nodeAdded {
+					mutations.AddNode++
 				}
 				mutStructBaby = true
-			} else if rand.Float64() < opts.MutateAddLinkProb {
-				neat.DebugLog("SPECIES: ---> mutateAddLink")
+			} else if rng.Float64() < opts.MutateAddLinkProb {
+				logger.Debug("SPECIES: ---> mutateAddLink")
 
 				// Mutate add link
 				if _, err = newGenome.Genesis(generation); err != nil {
 					return nil, err
 				}
-				if _, err = newGenome.mutateAddLink(pop, opts); err != nil {
+				if linkAdded, err := newGenome.mutateAddLink(rng, pop, opts); err != nil {
 					return nil, err
+				} else if linkAdded {
+					mutations.AddLink++
 				}
 				mutStructBaby = true
-			} else if rand.Float64() < opts.MutateConnectSensors {
-				neat.DebugLog("SPECIES: ---> mutateConnectSensors")
-				if linkAdded, err := newGenome.mutateConnectSensors(pop, opts); err != nil {
+			} else if rng.Float64() < opts.MutateConnectSensors {
+				logger.Debug("SPECIES: ---> mutateConnectSensors")
+				if linkAdded, err := newGenome.mutateConnectSensors(rng, pop, opts); err != nil {
 					return nil, err
 				} else {
 					mutStructBaby = linkAdded
+					if linkAdded {
+						mutations.ConnectSensors++
+					}
 				}
 			}
 
 			if !mutStructBaby {
-				neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
+				logger.Debug("SPECIES: ---> mutateAllNonstructural")
 
 				// If we didn't do a structural mutation, we do the other kinds
-				if _, err = newGenome.mutateAllNonstructural(opts); err != nil {
+				if _, err = newGenome.mutateAllNonstructural(rng, opts); err != nil {
 					return nil, err
 				}
+				mutations.NonStructural++
 			}
 
 			// Create the new baby organism
@@ -411,22 +428,22 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				return nil, err
 			}
 		} else {
-			neat.DebugLog("SPECIES: Reproduce by mating:")
+			logger.Debug("SPECIES: Reproduce by mating:")
 
 			// Otherwise we should mate
-			orgNum := rand.Int31n(int32(poolSize)) // select random mom
+			orgNum := rng.Int31n(int32(poolSize)) // select random mom
 			mom := s.Organisms[orgNum]
 
 			// Choose random dad
 			var dad *Organism
-			if rand.Float64() > opts.InterspeciesMateRate {
-				neat.DebugLog("SPECIES: ---> mate within species")
+			if rng.Float64() > opts.InterspeciesMateRate {
+				logger.Debug("SPECIES: ---> mate within species")
 
 				// Mate within Species
-				orgNum = rand.Int31n(int32(poolSize))
+				orgNum = rng.Int31n(int32(poolSize))
 				dad = s.Organisms[orgNum]
 			} else {
-				neat.DebugLog("SPECIES: ---> mate outside species")
+				logger.Debug("SPECIES: ---> mate outside species")
 
 				// Mate outside Species
 				randSpecies := s
@@ -435,7 +452,7 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				giveup := 0
 				for randSpecies.Id == s.Id && giveup < 5 {
 					// Choose a random species tending towards better species
-					randMult := rand.Float64() / 4.0
+					randMult := rng.Float64() / 4.0
 					// This tends to select better species
 					randSpeciesNum := int(math.Floor(randMult * float64(len(sortedSpecies))))
 					randSpecies = sortedSpecies[randSpeciesNum]
@@ -443,79 +460,92 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 					giveup++
 				}
 				dad = randSpecies.Organisms[0]
+				if randSpecies.Id != s.Id {
+					matings.Interspecies++
+				}
 			}
 
 			// Perform mating based on probabilities of different mating types
 			var newGenome *Genome
 			var err error
-			if rand.Float64() < opts.MateMultipointProb {
-				neat.DebugLog("SPECIES: ------> mateMultipoint")
+			if rng.Float64() < opts.MateMultipointProb {
+				logger.Debug("SPECIES: ------> mateMultipoint")
 
 				// mate multipoint baby
-				newGenome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				newGenome, err = mom.Genotype.mateMultipoint(rng, dad.Genotype, count, mom.originalFitness, dad.originalFitness)
 				if err != nil {
 					return nil, err
 				}
-			} else if rand.Float64() < opts.MateMultipointAvgProb/(opts.MateMultipointAvgProb+opts.MateSinglepointProb) {
-				neat.DebugLog("SPECIES: ------> mateMultipointAvg")
+				matings.Multipoint++
+			} else if rng.Float64() < opts.MateMultipointAvgProb/(opts.MateMultipointAvgProb+opts.MateSinglepointProb) {
+				logger.Debug("SPECIES: ------> mateMultipointAvg")
 
 				// mate multipoint_avg baby
-				newGenome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				newGenome, err = mom.Genotype.mateMultipointAvg(rng, dad.Genotype, count, mom.originalFitness, dad.originalFitness)
 				if err != nil {
 					return nil, err
 				}
+				matings.MultipointAvg++
 			} else {
-				neat.DebugLog("SPECIES: ------> mateSinglePoint")
+				logger.Debug("SPECIES: ------> mateSinglePoint")
 
-				newGenome, err = mom.Genotype.mateSinglePoint(dad.Genotype, count)
+				newGenome, err = mom.Genotype.mateSinglePoint(rng, dad.Genotype, count)
 				if err != nil {
 					return nil, err
 				}
+				matings.SinglePoint++
 			}
 
 			mateBaby = true
 
 			// Determine whether to mutate the baby's Genome
 			// This is done randomly or if the mom and dad are the same organism
-			if rand.Float64() > opts.MateOnlyProb ||
+			if rng.Float64() > opts.MateOnlyProb ||
 				dad.Genotype.Id == mom.Genotype.Id ||
 				dad.Genotype.compatibility(mom.Genotype, opts) == 0.0 {
-				neat.DebugLog("SPECIES: ------> Mutatte baby genome:")
+				logger.Debug("SPECIES: ------> Mutatte baby genome:")
 
 				// Do the mutation depending on probabilities of  various mutations
-				if rand.Float64() < opts.MutateAddNodeProb {
-					neat.DebugLog("SPECIES: ---------> mutateAddNode")
+				if rng.Float64() < opts.MutateAddNodeProb {
+					logger.Debug("SPECIES: ---------> mutateAddNode")
 
 					// mutate_add_node
-					if _, err = newGenome.mutateAddNode(pop, pop, opts); err != nil {
+					if nodeAdded, err := newGenome.mutateAddNode(rng, pop, pop, opts); err != nil {
 						return nil, err
+					} else if nodeAdded {
+						mutations.AddNode++
 					}
 					mutStructBaby = true
-				} else if rand.Float64() < opts.MutateAddLinkProb {
-					neat.DebugLog("SPECIES: ---------> mutateAddLink")
+				} else if rng.Float64() < opts.MutateAddLinkProb {
+					logger.Debug("SPECIES: ---------> mutateAddLink")
 
 					// mutate_add_link
 					if _, err = newGenome.Genesis(generation); err != nil {
 						return nil, err
 					}
-					if _, err = newGenome.mutateAddLink(pop, opts); err != nil {
+					if linkAdded, err := newGenome.mutateAddLink(rng, pop, opts); err != nil {
 						return nil, err
+					} else if linkAdded {
+						mutations.AddLink++
 					}
 					mutStructBaby = true
-				} else if rand.Float64() < opts.MutateConnectSensors {
-					neat.DebugLog("SPECIES: ---> mutateConnectSensors")
-					if mutStructBaby, err = newGenome.mutateConnectSensors(pop, opts); err != nil {
+				} else if rng.Float64() < opts.MutateConnectSensors {
+					logger.Debug("SPECIES: ---> mutateConnectSensors")
+					if mutStructBaby, err = newGenome.mutateConnectSensors(rng, pop, opts); err != nil {
 						return nil, err
+					} else if mutStructBaby {
+						mutations.ConnectSensors++
 					}
 				}
 
 				if !mutStructBaby {
-					neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
+					logger.Debug("SPECIES: ---> mutateAllNonstructural")
 
 					// If we didn't do a structural mutation, we do the other kinds
-					if _, err = newGenome.mutateAllNonstructural(opts); err != nil {
+					if _, err = newGenome.mutateAllNonstructural(rng, opts); err != nil {
 						return nil, err
 					}
+					mutations.NonStructural++
 				}
 			}
 			// Create the new baby organism
@@ -531,12 +561,16 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 		babies = append(babies, baby)
 
 	} // end for count := 0
+
+	pop.recordSpeciesReproduction(s.Id, time.Since(startTime), mutations, matings)
 	return babies, nil
 }
 
-func createFirstSpecies(pop *Population, baby *Organism) {
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("SPECIES: Create first species for baby organism [%d]", baby.Genotype.Id))
+func createFirstSpecies(ctx context.Context, pop *Population, baby *Organism) {
+	logger := neat.LoggerFromContext(ctx)
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("SPECIES: Create first species for baby organism [%d]", baby.Genotype.Id),
+			neat.LogKeyOrganism, baby.Genotype.Id)
 	}
 
 	pop.LastSpecies++
@@ -544,10 +578,11 @@ func createFirstSpecies(pop *Population, baby *Organism) {
 	pop.Species = append(pop.Species, species)
 	species.addOrganism(baby) // Add the baby
 	baby.Species = species    // Point baby to its species
+	pop.emit(SpeciesCreatedEvent, species.Id, 1)
 
-	if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("SPECIES: # of species in population: %d, new species id: %d",
-			len(pop.Species), species.Id))
+	if logger.Enabled(neat.LevelDebug) {
+		logger.Debug(fmt.Sprintf("SPECIES: # of species in population: %d, new species id: %d",
+			len(pop.Species), species.Id), neat.LogKeySpecies, species.Id)
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v2/neat"
+	"github.com/yaricom/goNEAT/v2/neat/math"
 	"math/rand"
 	"sort"
 	"testing"
@@ -209,7 +210,7 @@ func TestSpecies_reproduce(t *testing.T) {
 	}
 	neat.LogLevel = neat.LogLevelInfo
 
-	gen := newGenomeRand(1, in, out, n, nmax, false, linkProb)
+	gen := newGenomeRand(math.GlobalRand(), 1, in, out, n, nmax, false, linkProb)
 	pop, err := NewPopulation(gen, &opts)
 	require.NoError(t, err, "failed to create population")
 	require.NotNil(t, pop, "population expected")
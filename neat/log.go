@@ -2,8 +2,6 @@ package neat
 
 import (
 	"github.com/pkg/errors"
-	"log"
-	"os"
 )
 
 // LoggerLevel type to specify logger output level
@@ -24,34 +22,21 @@ var (
 	// LogLevel The current log level of the context
 	LogLevel LoggerLevel
 
-	loggerDebug = log.New(os.Stdout, "DEBUG: ", log.Ltime|log.Lshortfile)
-	loggerInfo  = log.New(os.Stdout, "INFO: ", log.Ltime|log.Lshortfile)
-	loggerWarn  = log.New(os.Stdout, "ALERT: ", log.Ltime|log.Lshortfile)
-	loggerError = log.New(os.Stderr, "ERROR: ", log.Ltime|log.Lshortfile)
-
 	// DebugLog The logger to output all messages
 	DebugLog = func(message string) {
-		if LogLevel <= LogLevelDebug {
-			_ = loggerDebug.Output(2, message)
-		}
+		DefaultLogger().log(2, LevelDebug, message, nil)
 	}
 	// InfoLog The logger to output messages with Info and up level
 	InfoLog = func(message string) {
-		if LogLevel <= LogLevelInfo {
-			_ = loggerInfo.Output(2, message)
-		}
+		DefaultLogger().log(2, LevelInfo, message, nil)
 	}
 	// WarnLog The logger to output messages with Warn and up level
 	WarnLog = func(message string) {
-		if LogLevel <= LogLevelWarning {
-			_ = loggerWarn.Output(2, message)
-		}
+		DefaultLogger().log(2, LevelWarn, message, nil)
 	}
 	// ErrorLog The logger to output messages with Error and up level
 	ErrorLog = func(message string) {
-		if LogLevel <= LogLevelError {
-			_ = loggerError.Output(2, message)
-		}
+		DefaultLogger().log(2, LevelError, message, nil)
 	}
 )
 
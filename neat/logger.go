@@ -0,0 +1,473 @@
+package neat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The standard keys of the structured log records
+const (
+	// LogKeyTrial The key of the trial ID
+	LogKeyTrial = "trial"
+	// LogKeyGeneration The key of the generation ID
+	LogKeyGeneration = "generation"
+	// LogKeySpecies The key of the species ID
+	LogKeySpecies = "species"
+	// LogKeyOrganism The key of the organism's genome ID
+	LogKeyOrganism = "organism"
+)
+
+// Level the severity of the log record. The values are the same as of levels of the log/slog package.
+type Level int
+
+const (
+	// LevelDebug The Debug level
+	LevelDebug Level = -4
+	// LevelInfo The Info level
+	LevelInfo Level = 0
+	// LevelWarn The Warning level
+	LevelWarn Level = 4
+	// LevelError The Error level
+	LevelError Level = 8
+)
+
+// String Returns the name of the level
+func (l Level) String() string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// Level Returns the structured log Level corresponding to this logger level. The unknown level is treated as debug.
+func (l LoggerLevel) Level() Level {
+	switch l {
+	case LogLevelInfo:
+		return LevelInfo
+	case LogLevelWarning:
+		return LevelWarn
+	case LogLevelError:
+		return LevelError
+	default:
+		return LevelDebug
+	}
+}
+
+// Attr the key/value pair of the structured log record
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+// Record the structured log record
+type Record struct {
+	// The time when record was created
+	Time time.Time
+	// The severity level
+	Level Level
+	// The log message
+	Message string
+	// The attributes of the record in order of addition
+	Attrs []Attr
+	// The source file and line of the log call site if known
+	File string
+	Line int
+}
+
+// Handler the handler of structured log records. The implementations must be safe for concurrent use.
+type Handler interface {
+	// Enabled Returns true if records with given level should be handled
+	Enabled(level Level) bool
+	// Handle Handles provided log record
+	Handle(record Record) error
+}
+
+// Logger the structured logger which passes records with attached attributes to the handler. The attributes are
+// provided as alternating keys and values, or as Attr values, similar to the log/slog package.
+type Logger struct {
+	handler Handler
+	attrs   []Attr
+}
+
+// NewLogger Creates new logger with provided handler
+func NewLogger(handler Handler) *Logger {
+	return &Logger{handler: handler}
+}
+
+// Handler Returns the handler of this logger
+func (l *Logger) Handler() Handler {
+	return l.handler
+}
+
+// With Returns the logger which includes provided attributes into each record
+func (l *Logger) With(args ...interface{}) *Logger {
+	attrs := make([]Attr, 0, len(l.attrs)+len(args)/2)
+	attrs = append(attrs, l.attrs...)
+	return &Logger{handler: l.handler, attrs: append(attrs, argsToAttrs(args)...)}
+}
+
+// Enabled Returns true if records with given level are handled by this logger
+func (l *Logger) Enabled(level Level) bool {
+	return l.handler.Enabled(level)
+}
+
+// Debug Logs message at the Debug level with provided attributes
+func (l *Logger) Debug(msg string, args ...interface{}) {
+	l.log(2, LevelDebug, msg, args)
+}
+
+// Info Logs message at the Info level with provided attributes
+func (l *Logger) Info(msg string, args ...interface{}) {
+	l.log(2, LevelInfo, msg, args)
+}
+
+// Warn Logs message at the Warn level with provided attributes
+func (l *Logger) Warn(msg string, args ...interface{}) {
+	l.log(2, LevelWarn, msg, args)
+}
+
+// Error Logs message at the Error level with provided attributes
+func (l *Logger) Error(msg string, args ...interface{}) {
+	l.log(2, LevelError, msg, args)
+}
+
+// Log Logs message at the given level with provided attributes
+func (l *Logger) Log(level Level, msg string, args ...interface{}) {
+	l.log(2, level, msg, args)
+}
+
+// log Creates record and passes it to the handler. The callDepth is the number of stack frames to skip to find the
+// call site of the logger.
+func (l *Logger) log(callDepth int, level Level, msg string, args []interface{}) {
+	if !l.handler.Enabled(level) {
+		return
+	}
+	record := Record{
+		Time:    time.Now(),
+		Level:   level,
+		Message: msg,
+		Attrs:   append(append(make([]Attr, 0, len(l.attrs)+len(args)/2), l.attrs...), argsToAttrs(args)...),
+	}
+	if _, file, line, ok := runtime.Caller(callDepth); ok {
+		record.File, record.Line = file, line
+	}
+	if err := l.handler.Handle(record); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to handle log record, reason: %s\n", err)
+	}
+}
+
+// argsToAttrs Converts alternating keys and values into attributes
+func argsToAttrs(args []interface{}) []Attr {
+	attrs := make([]Attr, 0, len(args)/2)
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i].(type) {
+		case Attr:
+			attrs = append(attrs, arg)
+		case string:
+			if i+1 < len(args) {
+				attrs = append(attrs, Attr{Key: arg, Value: args[i+1]})
+				i++
+			} else {
+				attrs = append(attrs, Attr{Key: "!BADKEY", Value: arg})
+			}
+		default:
+			attrs = append(attrs, Attr{Key: "!BADKEY", Value: arg})
+		}
+	}
+	return attrs
+}
+
+// writerHandler the base of handlers writing formatted records into the writer
+type writerHandler struct {
+	w     io.Writer
+	level Level
+	mutex sync.Mutex
+}
+
+func (h *writerHandler) Enabled(level Level) bool {
+	return level >= h.level
+}
+
+func (h *writerHandler) write(data []byte) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	_, err := h.w.Write(data)
+	return err
+}
+
+// textHandler the Handler writing records as a sequence of key=value pairs on a line
+type textHandler struct {
+	writerHandler
+}
+
+// NewTextHandler Creates new handler which writes records with level not less than provided into the writer as
+// a sequence of key=value pairs on a line
+func NewTextHandler(w io.Writer, level Level) Handler {
+	return &textHandler{writerHandler{w: w, level: level}}
+}
+
+func (h *textHandler) Handle(r Record) error {
+	var buf bytes.Buffer
+	buf.WriteString("time=")
+	buf.WriteString(r.Time.Format(time.RFC3339Nano))
+	buf.WriteString(" level=")
+	buf.WriteString(r.Level.String())
+	buf.WriteString(" msg=")
+	buf.WriteString(quoteIfNeeded(r.Message))
+	for _, a := range r.Attrs {
+		buf.WriteByte(' ')
+		buf.WriteString(a.Key)
+		buf.WriteByte('=')
+		buf.WriteString(quoteIfNeeded(fmt.Sprint(a.Value)))
+	}
+	buf.WriteByte('\n')
+	return h.write(buf.Bytes())
+}
+
+// jsonHandler the Handler writing records as JSON objects on separate lines
+type jsonHandler struct {
+	writerHandler
+}
+
+// NewJSONHandler Creates new handler which writes records with level not less than provided into the writer as
+// JSON objects on separate lines
+func NewJSONHandler(w io.Writer, level Level) Handler {
+	return &jsonHandler{writerHandler{w: w, level: level}}
+}
+
+func (h *jsonHandler) Handle(r Record) error {
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, r.Time)
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, r.Level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, r.Message)
+	for _, a := range r.Attrs {
+		buf.WriteByte(',')
+		writeJSON(&buf, a.Key)
+		buf.WriteByte(':')
+		writeJSON(&buf, a.Value)
+	}
+	buf.WriteString("}\n")
+	return h.write(buf.Bytes())
+}
+
+// writeJSON Writes JSON encoding of the value into the buffer falling back to its string form if not encodable
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(data)
+}
+
+// quoteIfNeeded Quotes the string if it is empty or contains spaces, quotes, or equal signs
+func quoteIfNeeded(s string) string {
+	if len(s) == 0 || strings.ContainsAny(s, " \t\n\r\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// MultiHandler the Handler which passes records to all underlying handlers
+type MultiHandler struct {
+	handlers []Handler
+}
+
+// NewMultiHandler Creates new handler passing records to all provided handlers
+func NewMultiHandler(handlers ...Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// Enabled Implements Handler
+func (h *MultiHandler) Enabled(level Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle Implements Handler. Returns the first error encountered if any.
+func (h *MultiHandler) Handle(r Record) error {
+	var firstErr error
+	for _, handler := range h.handlers {
+		if handler.Enabled(r.Level) {
+			if err := handler.Handle(r); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// TrialLogFileName The name of the log file written by TrialFileHandler
+const TrialLogFileName = "neat.log"
+
+// TrialFileHandler the Handler which writes records of each trial into the separate file. The records with trial
+// attribute are written into the TrialLogFileName file in the trial's subdirectory of the output directory, and all
+// others into the file in the output directory itself.
+type TrialFileHandler struct {
+	outDir     string
+	level      Level
+	newHandler func(w io.Writer) Handler
+	handlers   map[string]Handler
+	files      []*os.File
+	mutex      sync.Mutex
+}
+
+// NewTrialFileHandler Creates new handler writing records with level not less than provided into the per trial files
+// in the output directory. The records are formatted by handlers created with provided factory, e.g. NewJSONHandler.
+func NewTrialFileHandler(outDir string, level Level, newHandler func(w io.Writer, level Level) Handler) *TrialFileHandler {
+	return &TrialFileHandler{
+		outDir: outDir,
+		level:  level,
+		newHandler: func(w io.Writer) Handler {
+			return newHandler(w, level)
+		},
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Enabled Implements Handler
+func (h *TrialFileHandler) Enabled(level Level) bool {
+	return level >= h.level
+}
+
+// Handle Implements Handler
+func (h *TrialFileHandler) Handle(r Record) error {
+	dir := h.outDir
+	for _, a := range r.Attrs {
+		if a.Key == LogKeyTrial {
+			dir = filepath.Join(h.outDir, fmt.Sprint(a.Value))
+		}
+	}
+	handler, err := h.handlerForDir(dir)
+	if err != nil {
+		return err
+	}
+	return handler.Handle(r)
+}
+
+// Close Closes all opened log files
+func (h *TrialFileHandler) Close() error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	var firstErr error
+	for _, f := range h.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	h.files = nil
+	h.handlers = make(map[string]Handler)
+	return firstErr
+}
+
+func (h *TrialFileHandler) handlerForDir(dir string) (Handler, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if handler, ok := h.handlers[dir]; ok {
+		return handler, nil
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, TrialLogFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	h.files = append(h.files, file)
+	handler := h.newHandler(file)
+	h.handlers[dir] = handler
+	return handler, nil
+}
+
+// legacyHandler the Handler writing records in the format of the package-global log functions. It respects the
+// global LogLevel.
+type legacyHandler struct {
+	mutex sync.Mutex
+}
+
+var legacyPrefixes = map[Level]string{
+	LevelDebug: "DEBUG: ",
+	LevelInfo:  "INFO: ",
+	LevelWarn:  "ALERT: ",
+	LevelError: "ERROR: ",
+}
+
+func (h *legacyHandler) Enabled(level Level) bool {
+	return level >= LogLevel.Level()
+}
+
+func (h *legacyHandler) Handle(r Record) error {
+	var buf bytes.Buffer
+	prefix, ok := legacyPrefixes[r.Level]
+	if !ok {
+		prefix = r.Level.String() + ": "
+	}
+	buf.WriteString(prefix)
+	buf.WriteString(r.Time.Format("15:04:05 "))
+	if len(r.File) > 0 {
+		buf.WriteString(fmt.Sprintf("%s:%d: ", filepath.Base(r.File), r.Line))
+	}
+	if len(r.Attrs) > 0 {
+		buf.WriteString(strings.TrimRight(r.Message, "\n"))
+		for _, a := range r.Attrs {
+			buf.WriteString(fmt.Sprintf(" %s=%s", a.Key, quoteIfNeeded(fmt.Sprint(a.Value))))
+		}
+	} else {
+		buf.WriteString(r.Message)
+	}
+	if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	out := os.Stdout
+	if r.Level >= LevelError {
+		out = os.Stderr
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+// defaultLogger holds the logger used by the package-global log functions and when no logger found in the context
+var defaultLogger atomic.Value
+
+func init() {
+	defaultLogger.Store(NewLogger(&legacyHandler{}))
+}
+
+// DefaultLogger Returns the default logger used by DebugLog, InfoLog, WarnLog, ErrorLog and when no logger found in
+// the context. Unless replaced, it writes into the standard output respecting the global LogLevel.
+func DefaultLogger() *Logger {
+	return defaultLogger.Load().(*Logger)
+}
+
+// SetDefaultLogger Sets the default logger
+func SetDefaultLogger(logger *Logger) {
+	defaultLogger.Store(logger)
+}
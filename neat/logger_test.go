@@ -0,0 +1,95 @@
+package neat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogger_TextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewTextHandler(&buf, LevelInfo)).With(LogKeyTrial, 1)
+
+	logger.Debug("hidden")
+	logger.With(LogKeyGeneration, 2).Info("generation evaluated", "best fitness", 15.5, Attr{Key: LogKeySpecies, Value: 3})
+	logger.Warn("odd", 42)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], ` level=INFO msg="generation evaluated" trial=1 generation=2 best fitness=15.5 species=3`)
+	assert.True(t, strings.HasPrefix(lines[0], "time="))
+	assert.Contains(t, lines[1], " level=WARN msg=odd trial=1 !BADKEY=42")
+}
+
+func TestLogger_JSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewJSONHandler(&buf, LevelDebug))
+
+	logger.With(LogKeyOrganism, 7).Error("evaluation failed", "error", os.ErrNotExist)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "evaluation failed", record["msg"])
+	assert.EqualValues(t, 7, record[LogKeyOrganism])
+	assert.Equal(t, os.ErrNotExist.Error(), record["error"])
+	assert.NotEmpty(t, record["time"])
+}
+
+func TestMultiHandler(t *testing.T) {
+	var debug, errs bytes.Buffer
+	logger := NewLogger(NewMultiHandler(NewTextHandler(&debug, LevelDebug), NewTextHandler(&errs, LevelError)))
+	assert.True(t, logger.Enabled(LevelDebug))
+
+	logger.Info("info")
+	logger.Error("error")
+	assert.Equal(t, 2, strings.Count(debug.String(), "\n"))
+	assert.Equal(t, 1, strings.Count(errs.String(), "\n"))
+}
+
+func TestTrialFileHandler(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "trial_logs")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(outDir)
+	}()
+
+	handler := NewTrialFileHandler(outDir, LevelInfo, NewTextHandler)
+	logger := NewLogger(handler)
+	logger.Info("experiment started")
+	logger.With(LogKeyTrial, 0).Info("trial started")
+	logger.With(LogKeyTrial, 1).Info("trial started")
+	logger.With(LogKeyTrial, 1).Debug("hidden")
+	require.NoError(t, handler.Close())
+
+	for _, dir := range []string{outDir, filepath.Join(outDir, "0"), filepath.Join(outDir, "1")} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, TrialLogFileName))
+		require.NoError(t, err, "log file expected in: %s", dir)
+		assert.Equal(t, 1, strings.Count(string(data), "\n"), "one record expected in: %s", dir)
+	}
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	assert.Equal(t, DefaultLogger(), LoggerFromContext(context.Background()))
+
+	logger := NewLogger(NewTextHandler(ioutil.Discard, LevelInfo))
+	ctx := NewLoggerContext(NewContext(context.Background(), &Options{}), logger)
+	assert.Equal(t, logger, LoggerFromContext(ctx))
+	_, found := FromContext(ctx)
+	assert.True(t, found, "options expected to stay in the context")
+}
+
+func TestLoggerLevel_Level(t *testing.T) {
+	assert.Equal(t, LevelDebug, LogLevelDebug.Level())
+	assert.Equal(t, LevelInfo, LogLevelInfo.Level())
+	assert.Equal(t, LevelWarn, LogLevelWarning.Level())
+	assert.Equal(t, LevelError, LogLevelError.Level())
+	assert.Equal(t, LevelDebug, LoggerLevel("").Level())
+}
@@ -6,11 +6,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/pkg/errors"
-	"github.com/spf13/cast"
 	"github.com/yaricom/goNEAT/v2/neat/math"
 	"gopkg.in/yaml.v3"
 	"io"
 	"io/ioutil"
+	"math/rand"
 	"strconv"
 	"strings"
 )
@@ -141,12 +141,18 @@ type Options struct {
 
 // RandomNodeActivationType Returns next random node activation type among registered with this context
 func (c *Options) RandomNodeActivationType() (math.NodeActivationType, error) {
+	return c.RandomNodeActivationTypeWith(math.GlobalRand())
+}
+
+// RandomNodeActivationTypeWith Returns next random node activation type among registered with this context using
+// provided generator of random numbers
+func (c *Options) RandomNodeActivationTypeWith(rng *rand.Rand) (math.NodeActivationType, error) {
 	// quick check for the most cases
 	if len(c.NodeActivators) == 1 {
 		return c.NodeActivators[0], nil
 	}
 	// find next random
-	index := math.SingleRouletteThrow(c.NodeActivatorsProb)
+	index := math.SingleRouletteThrowWith(rng, c.NodeActivatorsProb)
 	if index < 0 || index >= len(c.NodeActivators) {
 		return 0, fmt.Errorf("unexpected error when trying to find random node activator, activator index: %d", index)
 	}
@@ -166,6 +172,9 @@ func (c *Options) initNodeActivators() (err error) {
 	c.NodeActivatorsProb = make([]float64, len(actFns))
 	for i, line := range actFns {
 		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return errors.Errorf("node activator must be defined as name followed by probability: [%s]", line)
+		}
 		if c.NodeActivators[i], err = math.NodeActivators.ActivationTypeFromName(fields[0]); err != nil {
 			return err
 		}
@@ -178,18 +187,6 @@ func (c *Options) initNodeActivators() (err error) {
 	return nil
 }
 
-// Validate is to validate that this options has valid values
-func (c *Options) Validate() error {
-	if err := c.EpochExecutorType.Validate(); err != nil {
-		return err
-	}
-
-	if err := c.GenCompatMethod.Validate(); err != nil {
-		return err
-	}
-	return nil
-}
-
 // NeatContext is to get Context which carries NEAT options inside to be propagated
 func (c *Options) NeatContext() context.Context {
 	return NewContext(context.Background(), c)
@@ -202,8 +199,8 @@ func LoadYAMLOptions(r io.Reader) (*Options, error) {
 		return nil, err
 	}
 	// read options
-	var opts Options
-	if err = yaml.Unmarshal(content, &opts); err != nil {
+	opts := DefaultOptions()
+	if err = yaml.Unmarshal(content, opts); err != nil {
 		return nil, errors.Wrap(err, "failed to decode NEAT options from YAML")
 	}
 
@@ -218,109 +215,17 @@ func LoadYAMLOptions(r io.Reader) (*Options, error) {
 	}
 
 	if err = opts.Validate(); err != nil {
-		return nil, errors.Wrap(err, "invalid NEAT options")
+		return nil, err
 	}
 
-	return &opts, nil
+	return opts, nil
 }
 
-// LoadNeatOptions Loads NEAT options configuration from provided reader encode in plain text format (.neat)
-func LoadNeatOptions(r io.Reader) (*Options, error) {
-	c := &Options{}
-	// read configuration
-	var name string
-	var param string
-	for {
-		_, err := fmt.Fscanf(r, "%s %v", &name, &param)
-		if err == io.EOF {
-			break
-		}
-		switch name {
-		case "trait_param_mut_prob":
-			c.TraitParamMutProb = cast.ToFloat64(param)
-		case "trait_mutation_power":
-			c.TraitMutationPower = cast.ToFloat64(param)
-		case "weight_mut_power":
-			c.WeightMutPower = cast.ToFloat64(param)
-		case "disjoint_coeff":
-			c.DisjointCoeff = cast.ToFloat64(param)
-		case "excess_coeff":
-			c.ExcessCoeff = cast.ToFloat64(param)
-		case "mutdiff_coeff":
-			c.MutdiffCoeff = cast.ToFloat64(param)
-		case "compat_threshold":
-			c.CompatThreshold = cast.ToFloat64(param)
-		case "age_significance":
-			c.AgeSignificance = cast.ToFloat64(param)
-		case "survival_thresh":
-			c.SurvivalThresh = cast.ToFloat64(param)
-		case "mutate_only_prob":
-			c.MutateOnlyProb = cast.ToFloat64(param)
-		case "mutate_random_trait_prob":
-			c.MutateRandomTraitProb = cast.ToFloat64(param)
-		case "mutate_link_trait_prob":
-			c.MutateLinkTraitProb = cast.ToFloat64(param)
-		case "mutate_node_trait_prob":
-			c.MutateNodeTraitProb = cast.ToFloat64(param)
-		case "mutate_link_weights_prob":
-			c.MutateLinkWeightsProb = cast.ToFloat64(param)
-		case "mutate_toggle_enable_prob":
-			c.MutateToggleEnableProb = cast.ToFloat64(param)
-		case "mutate_gene_reenable_prob":
-			c.MutateGeneReenableProb = cast.ToFloat64(param)
-		case "mutate_add_node_prob":
-			c.MutateAddNodeProb = cast.ToFloat64(param)
-		case "mutate_add_link_prob":
-			c.MutateAddLinkProb = cast.ToFloat64(param)
-		case "mutate_connect_sensors":
-			c.MutateConnectSensors = cast.ToFloat64(param)
-		case "interspecies_mate_rate":
-			c.InterspeciesMateRate = cast.ToFloat64(param)
-		case "mate_multipoint_prob":
-			c.MateMultipointProb = cast.ToFloat64(param)
-		case "mate_multipoint_avg_prob":
-			c.MateMultipointAvgProb = cast.ToFloat64(param)
-		case "mate_singlepoint_prob":
-			c.MateSinglepointProb = cast.ToFloat64(param)
-		case "mate_only_prob":
-			c.MateOnlyProb = cast.ToFloat64(param)
-		case "recur_only_prob":
-			c.RecurOnlyProb = cast.ToFloat64(param)
-		case "pop_size":
-			c.PopSize = cast.ToInt(param)
-		case "dropoff_age":
-			c.DropOffAge = cast.ToInt(param)
-		case "newlink_tries":
-			c.NewLinkTries = cast.ToInt(param)
-		case "print_every":
-			c.PrintEvery = cast.ToInt(param)
-		case "babies_stolen":
-			c.BabiesStolen = cast.ToInt(param)
-		case "num_runs":
-			c.NumRuns = cast.ToInt(param)
-		case "num_generations":
-			c.NumGenerations = cast.ToInt(param)
-		case "epoch_executor":
-			c.EpochExecutorType = EpochExecutorType(param)
-		case "genome_compat_method":
-			c.GenCompatMethod = GenomeCompatibilityMethod(param)
-		case "log_level":
-			c.LogLevel = param
-		default:
-			return nil, errors.Errorf("unknown configuration parameter found: %s = %s", name, param)
-		}
-	}
-	// initialize logger
-	if err := InitLogger(c.LogLevel); err != nil {
-		return nil, errors.Wrap(err, "failed to initialize logger")
-	}
-
-	if err := c.initNodeActivators(); err != nil {
-		return nil, err
-	}
-	if err := c.Validate(); err != nil {
-		return nil, err
+// WriteYAML Writes this options into provided writer encoded as YAML, which can be loaded with LoadYAMLOptions
+func (c *Options) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	if err := enc.Encode(c); err != nil {
+		return err
 	}
-
-	return c, nil
+	return enc.Close()
 }
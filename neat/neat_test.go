@@ -1,6 +1,7 @@
 package neat
 
 import (
+	"bytes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v2/neat/math"
@@ -56,6 +57,36 @@ func TestOptions_NeatContext(t *testing.T) {
 	assert.NotNil(t, nOpts)
 }
 
+func TestOptions_Write(t *testing.T) {
+	config, err := os.Open("../data/xor_test.neat")
+	require.NoError(t, err)
+	opts, err := LoadNeatOptions(config)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = opts.Write(&buf)
+	require.NoError(t, err, "failed to write options")
+
+	written, err := LoadNeatOptions(&buf)
+	require.NoError(t, err, "failed to load written options")
+	assert.Equal(t, opts, written)
+}
+
+func TestOptions_WriteYAML(t *testing.T) {
+	config, err := os.Open("../data/xor_test.neat.yml")
+	require.NoError(t, err)
+	opts, err := LoadYAMLOptions(config)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = opts.WriteYAML(&buf)
+	require.NoError(t, err, "failed to write options")
+
+	written, err := LoadYAMLOptions(&buf)
+	require.NoError(t, err, "failed to load written options")
+	assert.Equal(t, opts, written)
+}
+
 func checkNeatOptions(nc *Options, t *testing.T) {
 	assert.Equal(t, 0.5, nc.TraitParamMutProb)
 	assert.Equal(t, 1.0, nc.TraitMutationPower)
@@ -0,0 +1,552 @@
+package network
+
+import (
+	"fmt"
+)
+
+// MaxListedCycles The maximal number of elementary cycles to be listed during structural analysis of the network
+const MaxListedCycles = 1000
+
+// NodeDegree holds the number of incoming and outgoing connections of particular network node
+type NodeDegree struct {
+	// The network node
+	Node *NNode
+	// The number of incoming connections
+	FanIn int
+	// The number of outgoing connections
+	FanOut int
+}
+
+// StructuralAnalysis holds the summary of structural analysis of the network
+type StructuralAnalysis struct {
+	// The total number of nodes including control nodes of modules
+	Nodes int `json:"nodes"`
+	// The total number of links
+	Links int `json:"links"`
+	// The number of nodes which are reachable from inputs and can reach outputs (including sensors and outputs)
+	EffectiveNodes int `json:"effective_nodes"`
+	// The number of links between effective nodes
+	EffectiveLinks int `json:"effective_links"`
+	// The number of strongly connected components with more than one node or with self-loop, i.e. recurrent parts
+	RecurrentComponents int `json:"recurrent_components"`
+	// The number of elementary cycles, limited by MaxListedCycles
+	Cycles int `json:"cycles"`
+	// The flag to indicate that network has more elementary cycles than MaxListedCycles
+	CyclesTruncated bool `json:"cycles_truncated"`
+	// The number of feed-forward layers or zero if network has cycles not marked as recurrent
+	Layers int `json:"layers"`
+	// The total number of distinct paths from inputs to outputs or -1 if network has cycles not marked as recurrent
+	InputOutputPaths int `json:"input_output_paths"`
+	// The maximal number of incoming connections per node
+	MaxFanIn int `json:"max_fan_in"`
+	// The maximal number of outgoing connections per node
+	MaxFanOut int `json:"max_fan_out"`
+	// The Newman modularity score of the best found division of the network into communities
+	Modularity float64 `json:"modularity"`
+	// The number of communities in the best found division of the network
+	Communities int `json:"communities"`
+}
+
+func (a *StructuralAnalysis) String() string {
+	cycles := fmt.Sprintf("%d", a.Cycles)
+	if a.CyclesTruncated {
+		cycles += "+"
+	}
+	return fmt.Sprintf("nodes: %d (effective: %d), links: %d (effective: %d), recurrent components: %d, cycles: %s, layers: %d, paths: %d, max fan-in: %d, max fan-out: %d, modularity: %.4f, communities: %d",
+		a.Nodes, a.EffectiveNodes, a.Links, a.EffectiveLinks, a.RecurrentComponents, cycles, a.Layers,
+		a.InputOutputPaths, a.MaxFanIn, a.MaxFanOut, a.Modularity, a.Communities)
+}
+
+// Analyze Performs structural analysis of this network and returns its summary
+func (n *Network) Analyze() *StructuralAnalysis {
+	g := n.buildGraph()
+	a := &StructuralAnalysis{
+		Nodes: n.NodeCount(),
+		Links: n.LinkCount(),
+	}
+	a.EffectiveNodes, a.EffectiveLinks = n.EffectiveSize()
+
+	for _, component := range g.stronglyConnectedComponents() {
+		if len(component) > 1 || g.hasSelfLoop(component[0]) {
+			a.RecurrentComponents++
+		}
+	}
+	cycles, truncated := g.cycles(MaxListedCycles)
+	a.Cycles, a.CyclesTruncated = len(cycles), truncated
+
+	if layers, err := g.layers(); err == nil {
+		a.Layers = len(layers)
+	}
+	if paths, err := n.PathCounts(); err == nil {
+		for _, row := range paths {
+			for _, count := range row {
+				a.InputOutputPaths += count
+			}
+		}
+	} else {
+		a.InputOutputPaths = -1
+	}
+
+	for _, degree := range n.NodeDegrees() {
+		if degree.FanIn > a.MaxFanIn {
+			a.MaxFanIn = degree.FanIn
+		}
+		if degree.FanOut > a.MaxFanOut {
+			a.MaxFanOut = degree.FanOut
+		}
+	}
+
+	var communities [][]*NNode
+	a.Modularity, communities = n.Modularity()
+	a.Communities = len(communities)
+
+	return a
+}
+
+// StronglyConnectedComponents Returns strongly connected components of this network graph including control nodes.
+// The components are listed in reverse topological order, i.e. the components with outputs go first.
+func (n *Network) StronglyConnectedComponents() [][]*NNode {
+	g := n.buildGraph()
+	return g.nodesOf(g.stronglyConnectedComponents())
+}
+
+// Cycles Returns the elementary cycles of this network graph including control nodes and links marked as recurrent.
+// Each cycle is listed starting from its node which goes first in the network nodes list. The number of listed cycles
+// is limited by maxCycles, if it is positive. The second returned value is true if the list was truncated, i.e. the
+// network has more cycles than listed.
+func (n *Network) Cycles(maxCycles int) ([][]*NNode, bool) {
+	g := n.buildGraph()
+	cycles, truncated := g.cycles(maxCycles)
+	return g.nodesOf(cycles), truncated
+}
+
+// Layers Returns the feed-forward layers of this network. Each node is placed into layer which index equals to the
+// length of the longest path to it from any node without incoming links (sensors, usually). The links marked as
+// recurrent or time delayed are ignored. Returns NetErrNetworkHasCycles if network has cycles formed by other links.
+func (n *Network) Layers() ([][]*NNode, error) {
+	g := n.buildGraph()
+	layers, err := g.layers()
+	if err != nil {
+		return nil, err
+	}
+	return g.nodesOf(layers), nil
+}
+
+// PathCounts Returns the number of distinct paths from each input node to each output node of this network. The rows
+// of returned matrix are in order of input nodes (including bias) and the columns are in order of output nodes. The
+// links marked as recurrent or time delayed are ignored. Returns NetErrNetworkHasCycles if network has cycles formed by
+// other links.
+func (n *Network) PathCounts() ([][]int, error) {
+	g := n.buildGraph()
+	layers, err := g.layers()
+	if err != nil {
+		return nil, err
+	}
+	counts := make([][]int, len(n.inputs))
+	for i, in := range n.inputs {
+		paths := make([]int, len(g.units))
+		paths[g.index[in]] = 1
+		// propagate paths counts in topological order
+		for _, layer := range layers {
+			for _, unit := range layer {
+				for _, e := range g.outgoing[unit] {
+					if !g.edges[e].recurrent {
+						paths[g.edges[e].to] += paths[unit]
+					}
+				}
+			}
+		}
+		counts[i] = make([]int, len(n.Outputs))
+		for j, out := range n.Outputs {
+			counts[i][j] = paths[g.index[out]]
+		}
+	}
+	return counts, nil
+}
+
+// NodeDegrees Returns the number of incoming and outgoing connections (fan-in and fan-out) of each node of this network
+// including control nodes. The nodes are listed in the same order as AllNodes followed by control nodes.
+func (n *Network) NodeDegrees() []NodeDegree {
+	g := n.buildGraph()
+	degrees := make([]NodeDegree, len(g.units))
+	for i, node := range g.units {
+		degrees[i] = NodeDegree{Node: node, FanIn: len(g.incoming[i]), FanOut: len(g.outgoing[i])}
+	}
+	return degrees
+}
+
+// EffectiveSize Returns the number of nodes and links of this network which are reachable from inputs and able to
+// reach outputs, i.e. the size of network after Prune.
+func (n *Network) EffectiveSize() (nodes, links int) {
+	_, report := n.findDeadStructure(false)
+	return n.NodeCount() - report.RemovedNodesCount(), n.LinkCount() - len(report.RemovedLinks)
+}
+
+// Modularity Finds division of this network into communities using greedy agglomerative algorithm of Newman and
+// returns the modularity score of this division along with the found communities. The network is treated as undirected
+// unweighted graph, the self-loops are ignored. Returns zero modularity and single community per node if network has
+// no links.
+func (n *Network) Modularity() (float64, [][]*NNode) {
+	g := n.buildGraph()
+	size := len(g.units)
+
+	// the number of edges between communities
+	between := make([]map[int]float64, size)
+	for i := range between {
+		between[i] = make(map[int]float64)
+	}
+	totalEdges := 0.0
+	for _, e := range g.edges {
+		if e.from != e.to {
+			between[e.from][e.to]++
+			between[e.to][e.from]++
+			totalEdges++
+		}
+	}
+	communities := make([][]int, size)
+	for i := range communities {
+		communities[i] = []int{i}
+	}
+	if totalEdges == 0 {
+		return 0, g.nodesOf(communities)
+	}
+
+	// the fractions of edges ends in each community (a_i) and the initial modularity score
+	ends := make([]float64, size)
+	q := 0.0
+	for i := range between {
+		degree := 0.0
+		for _, count := range between[i] {
+			degree += count
+		}
+		ends[i] = degree / (2 * totalEdges)
+		q -= ends[i] * ends[i]
+	}
+
+	alive := make([]bool, size)
+	for i := range alive {
+		alive[i] = true
+	}
+	for {
+		// find the pair of connected communities which merge gives the largest increase of modularity
+		bestDelta, bestI, bestJ := 0.0, -1, -1
+		for i := 0; i < size; i++ {
+			if !alive[i] {
+				continue
+			}
+			for j, count := range between[i] {
+				if j <= i {
+					continue
+				}
+				delta := 2 * (count/(2*totalEdges) - ends[i]*ends[j])
+				if delta > bestDelta || delta == bestDelta && i == bestI && j < bestJ {
+					bestDelta, bestI, bestJ = delta, i, j
+				}
+			}
+		}
+		if bestI < 0 || bestDelta <= 0 {
+			break
+		}
+
+		// merge community j into community i
+		for k, count := range between[bestJ] {
+			delete(between[k], bestJ)
+			if k != bestI {
+				between[bestI][k] += count
+				between[k][bestI] += count
+			}
+		}
+		delete(between[bestI], bestJ)
+		between[bestJ] = nil
+		ends[bestI] += ends[bestJ]
+		communities[bestI] = append(communities[bestI], communities[bestJ]...)
+		communities[bestJ] = nil
+		alive[bestJ] = false
+		q += bestDelta
+	}
+
+	found := make([][]int, 0)
+	for i, community := range communities {
+		if alive[i] {
+			found = append(found, community)
+		}
+	}
+	return q, g.nodesOf(found)
+}
+
+// graphEdge is the directed edge of the network graph
+type graphEdge struct {
+	// The indexes of source and target units
+	from, to int
+	// The flag to indicate whether this edge is marked as recurrent or time delayed
+	recurrent bool
+}
+
+// networkGraph is the representation of the network as directed graph with all nodes (including control nodes of
+// modules) as units indexed in the order of the network nodes list followed by control nodes.
+type networkGraph struct {
+	units    []*NNode
+	index    map[*NNode]int
+	edges    []graphEdge
+	outgoing [][]int
+	incoming [][]int
+}
+
+// buildGraph Builds directed graph representation of this network
+func (n *Network) buildGraph() *networkGraph {
+	g := &networkGraph{
+		units: make([]*NNode, 0, len(n.allNodes)+len(n.controlNodes)),
+		index: make(map[*NNode]int),
+		edges: make([]graphEdge, 0),
+	}
+	g.units = append(g.units, n.allNodes...)
+	g.units = append(g.units, n.controlNodes...)
+	for i, node := range g.units {
+		g.index[node] = i
+	}
+	g.outgoing = make([][]int, len(g.units))
+	g.incoming = make([][]int, len(g.units))
+
+	addEdge := func(link *Link, from, to *NNode) {
+		fromIndex, ok := g.index[from]
+		if !ok {
+			return
+		}
+		toIndex, ok := g.index[to]
+		if !ok {
+			return
+		}
+		g.outgoing[fromIndex] = append(g.outgoing[fromIndex], len(g.edges))
+		g.incoming[toIndex] = append(g.incoming[toIndex], len(g.edges))
+		g.edges = append(g.edges, graphEdge{
+			from: fromIndex, to: toIndex, recurrent: link.IsRecurrent || link.IsTimeDelayed,
+		})
+	}
+	for _, node := range n.allNodes {
+		for _, link := range node.Incoming {
+			addEdge(link, link.InNode, node)
+		}
+	}
+	for _, cn := range n.controlNodes {
+		for _, link := range cn.Incoming {
+			addEdge(link, link.InNode, cn)
+		}
+		for _, link := range cn.Outgoing {
+			addEdge(link, cn, link.OutNode)
+		}
+	}
+	return g
+}
+
+// nodesOf Converts lists of units indexes into lists of network nodes
+func (g *networkGraph) nodesOf(lists [][]int) [][]*NNode {
+	res := make([][]*NNode, len(lists))
+	for i, list := range lists {
+		res[i] = make([]*NNode, len(list))
+		for j, unit := range list {
+			res[i][j] = g.units[unit]
+		}
+	}
+	return res
+}
+
+// hasSelfLoop Returns true if given unit is connected to itself
+func (g *networkGraph) hasSelfLoop(unit int) bool {
+	for _, e := range g.outgoing[unit] {
+		if g.edges[e].to == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// stronglyConnectedComponents Finds strongly connected components using Tarjan's algorithm
+func (g *networkGraph) stronglyConnectedComponents() [][]int {
+	index := 0
+	indexes := make([]int, len(g.units))
+	lowLinks := make([]int, len(g.units))
+	onStack := make([]bool, len(g.units))
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	stack := make([]int, 0)
+	components := make([][]int, 0)
+
+	var connect func(unit int)
+	connect = func(unit int) {
+		indexes[unit], lowLinks[unit] = index, index
+		index++
+		stack = append(stack, unit)
+		onStack[unit] = true
+
+		for _, e := range g.outgoing[unit] {
+			next := g.edges[e].to
+			if indexes[next] < 0 {
+				connect(next)
+				if lowLinks[next] < lowLinks[unit] {
+					lowLinks[unit] = lowLinks[next]
+				}
+			} else if onStack[next] && indexes[next] < lowLinks[unit] {
+				lowLinks[unit] = indexes[next]
+			}
+		}
+
+		if lowLinks[unit] == indexes[unit] {
+			// the unit is the root of component - pop it from the stack
+			component := make([]int, 0)
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == unit {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for i := range g.units {
+		if indexes[i] < 0 {
+			connect(i)
+		}
+	}
+	return components
+}
+
+// cycles Lists elementary cycles of the graph using Johnson's algorithm, which spends polynomial time between
+// subsequent cycles found. Each cycle starts from its unit with the smallest index. The number of listed cycles is
+// limited by maxCycles, if it is positive. Returns true as the second value if there are more cycles than listed.
+func (g *networkGraph) cycles(maxCycles int) ([][]int, bool) {
+	componentOf := make([]int, len(g.units))
+	for i, component := range g.stronglyConnectedComponents() {
+		for _, unit := range component {
+			componentOf[unit] = i
+		}
+	}
+
+	cycles := make([][]int, 0)
+	path := make([]int, 0)
+	// the units which can not lead back to the start unit until one of their successors is unblocked
+	blocked := make([]bool, len(g.units))
+	blockedBy := make([][]int, len(g.units))
+	var unblock func(unit int)
+	unblock = func(unit int) {
+		blocked[unit] = false
+		for len(blockedBy[unit]) > 0 {
+			last := len(blockedBy[unit]) - 1
+			next := blockedBy[unit][last]
+			blockedBy[unit] = blockedBy[unit][:last]
+			if blocked[next] {
+				unblock(next)
+			}
+		}
+	}
+	// the cycles starting from the unit are searched among units with greater index in the same component
+	inSearch := func(start, unit int) bool {
+		return unit >= start && componentOf[unit] == componentOf[start]
+	}
+	// search Returns whether any cycle found through the unit and whether the search should be stopped
+	var search func(start, unit int) (bool, bool)
+	search = func(start, unit int) (bool, bool) {
+		found := false
+		path = append(path, unit)
+		blocked[unit] = true
+		for _, e := range g.outgoing[unit] {
+			next := g.edges[e].to
+			if !inSearch(start, next) {
+				continue
+			}
+			if next == start {
+				if maxCycles > 0 && len(cycles) == maxCycles {
+					// one more cycle exists
+					return true, true
+				}
+				cycle := make([]int, len(path))
+				copy(cycle, path)
+				cycles = append(cycles, cycle)
+				found = true
+			} else if !blocked[next] {
+				nextFound, stop := search(start, next)
+				if stop {
+					return true, true
+				}
+				found = found || nextFound
+			}
+		}
+		if found {
+			unblock(unit)
+		} else {
+			for _, e := range g.outgoing[unit] {
+				if next := g.edges[e].to; inSearch(start, next) && !containsUnit(blockedBy[next], unit) {
+					blockedBy[next] = append(blockedBy[next], unit)
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return found, false
+	}
+
+	for start := range g.units {
+		for i := start; i < len(g.units); i++ {
+			blocked[i] = false
+			blockedBy[i] = blockedBy[i][:0]
+		}
+		if _, stop := search(start, start); stop {
+			return cycles, true
+		}
+	}
+	return cycles, false
+}
+
+// containsUnit Checks whether the list of units contains given unit
+func containsUnit(units []int, unit int) bool {
+	for _, u := range units {
+		if u == unit {
+			return true
+		}
+	}
+	return false
+}
+
+// layers Finds feed-forward layers of the graph ignoring recurrent edges. Returns NetErrNetworkHasCycles if graph has
+// cycles formed by other edges.
+func (g *networkGraph) layers() ([][]int, error) {
+	inDegree := make([]int, len(g.units))
+	for _, e := range g.edges {
+		if !e.recurrent {
+			inDegree[e.to]++
+		}
+	}
+	frontier := make([]int, 0)
+	for i, degree := range inDegree {
+		if degree == 0 {
+			frontier = append(frontier, i)
+		}
+	}
+	layers := make([][]int, 0)
+	processed := 0
+	for len(frontier) > 0 {
+		layers = append(layers, frontier)
+		processed += len(frontier)
+		next := make([]int, 0)
+		for _, unit := range frontier {
+			for _, e := range g.outgoing[unit] {
+				if g.edges[e].recurrent {
+					continue
+				}
+				to := g.edges[e].to
+				inDegree[to]--
+				if inDegree[to] == 0 {
+					next = append(next, to)
+				}
+			}
+		}
+		frontier = next
+	}
+	if processed != len(g.units) {
+		return nil, NetErrNetworkHasCycles
+	}
+	return layers, nil
+}
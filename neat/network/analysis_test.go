@@ -0,0 +1,223 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// buildTwoTrianglesNetwork Creates the network with two densely connected triangles joined by a single link
+func buildTwoTrianglesNetwork() *Network {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, HiddenNeuron),
+		NewNNode(3, HiddenNeuron),
+		NewNNode(4, HiddenNeuron),
+		NewNNode(5, HiddenNeuron),
+		NewNNode(6, OutputNeuron),
+	}
+	// first triangle
+	allNodes[1].addIncoming(allNodes[0], 1.0)
+	allNodes[2].addIncoming(allNodes[0], 1.0)
+	allNodes[2].addIncoming(allNodes[1], 1.0)
+	// bridge
+	allNodes[3].addIncoming(allNodes[2], 1.0)
+	// second triangle
+	allNodes[4].addIncoming(allNodes[3], 1.0)
+	allNodes[5].addIncoming(allNodes[3], 1.0)
+	allNodes[5].addIncoming(allNodes[4], 1.0)
+
+	return NewNetwork(allNodes[0:1], allNodes[5:6], allNodes, 0)
+}
+
+func nodeIds(nodes []*NNode) []int {
+	ids := make([]int, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.Id
+	}
+	return ids
+}
+
+func TestNetwork_StronglyConnectedComponents(t *testing.T) {
+	net := buildNetwork()
+	components := net.StronglyConnectedComponents()
+	assert.Len(t, components, net.NodeCount(), "only trivial components expected")
+
+	// add recurrent link forming cycle: 4 -> 7 -> 4
+	nodes := net.AllNodes()
+	nodes[3].Incoming = append(nodes[3].Incoming, NewLink(1.0, nodes[6], nodes[3], true))
+	components = net.StronglyConnectedComponents()
+	require.Len(t, components, net.NodeCount()-1)
+	found := false
+	for _, component := range components {
+		if len(component) > 1 {
+			assert.ElementsMatch(t, []int{4, 7}, nodeIds(component))
+			found = true
+		}
+	}
+	assert.True(t, found, "non trivial component expected")
+}
+
+func TestNetwork_Cycles(t *testing.T) {
+	net := buildNetwork()
+	cycles, truncated := net.Cycles(0)
+	assert.Len(t, cycles, 0)
+	assert.False(t, truncated)
+
+	nodes := net.AllNodes()
+	// recurrent link forming cycle: 4 -> 7 -> 4
+	nodes[3].Incoming = append(nodes[3].Incoming, NewLink(1.0, nodes[6], nodes[3], true))
+	// self-loop at 6
+	nodes[5].Incoming = append(nodes[5].Incoming, NewLink(1.0, nodes[5], nodes[5], true))
+
+	cycles, truncated = net.Cycles(0)
+	require.Len(t, cycles, 2)
+	assert.False(t, truncated)
+	assert.Equal(t, []int{4, 7}, nodeIds(cycles[0]))
+	assert.Equal(t, []int{6}, nodeIds(cycles[1]))
+
+	// check limit
+	cycles, truncated = net.Cycles(1)
+	assert.Len(t, cycles, 1)
+	assert.True(t, truncated)
+
+	// check that limit equal to the number of cycles is not reported as truncation
+	cycles, truncated = net.Cycles(2)
+	assert.Len(t, cycles, 2)
+	assert.False(t, truncated)
+}
+
+func TestNetwork_Cycles_Complete(t *testing.T) {
+	// fully connected graph of n nodes has sum(C(n, k) * (k - 1)!) elementary cycles for k in [2, n]
+	size := 5
+	nodes := make([]*NNode, size)
+	for i := range nodes {
+		nodes[i] = NewNNode(i+1, HiddenNeuron)
+	}
+	for _, in := range nodes {
+		for _, out := range nodes {
+			if in != out {
+				out.Incoming = append(out.Incoming, NewLink(1.0, in, out, true))
+			}
+		}
+	}
+	net := NewNetwork(nodes[:1], nodes[1:2], nodes, 0)
+
+	cycles, truncated := net.Cycles(0)
+	assert.Len(t, cycles, 84)
+	assert.False(t, truncated)
+
+	cycles, truncated = net.Cycles(50)
+	assert.Len(t, cycles, 50)
+	assert.True(t, truncated)
+}
+
+func TestNetwork_Layers(t *testing.T) {
+	net := buildNetwork()
+	layers, err := net.Layers()
+	require.NoError(t, err)
+	require.Len(t, layers, 4)
+	assert.Equal(t, []int{1, 2, 3}, nodeIds(layers[0]))
+	assert.Equal(t, []int{4, 5}, nodeIds(layers[1]))
+	assert.Equal(t, []int{6}, nodeIds(layers[2]))
+	assert.Equal(t, []int{7, 8}, nodeIds(layers[3]))
+
+	// recurrent links are ignored
+	nodes := net.AllNodes()
+	nodes[3].Incoming = append(nodes[3].Incoming, NewLink(1.0, nodes[6], nodes[3], true))
+	layers, err = net.Layers()
+	require.NoError(t, err)
+	assert.Len(t, layers, 4)
+
+	// cycle without recurrent mark
+	nodes[4].addIncoming(nodes[5], 1.0)
+	_, err = net.Layers()
+	assert.EqualError(t, err, NetErrNetworkHasCycles.Error())
+}
+
+func TestNetwork_PathCounts(t *testing.T) {
+	net := buildNetwork()
+	paths, err := net.PathCounts()
+	require.NoError(t, err)
+	expected := [][]int{
+		{1, 0}, // input 1
+		{2, 1}, // input 2
+		{1, 1}, // bias 3
+	}
+	assert.Equal(t, expected, paths)
+
+	// modular network
+	net = buildModularNetwork()
+	paths, err = net.PathCounts()
+	require.NoError(t, err)
+	expected = [][]int{
+		{1, 1},
+		{1, 1},
+		{2, 2},
+	}
+	assert.Equal(t, expected, paths)
+}
+
+func TestNetwork_NodeDegrees(t *testing.T) {
+	net := buildModularNetwork()
+	degrees := net.NodeDegrees()
+	require.Len(t, degrees, net.NodeCount())
+
+	expected := map[int][2]int{
+		1: {0, 1}, 2: {0, 1}, 3: {0, 2}, 4: {2, 1}, 5: {2, 1}, 7: {1, 2}, 8: {1, 0}, 9: {1, 0}, 6: {2, 1},
+	}
+	for _, degree := range degrees {
+		exp := expected[degree.Node.Id]
+		assert.Equal(t, exp[0], degree.FanIn, "wrong fan-in at: %d", degree.Node.Id)
+		assert.Equal(t, exp[1], degree.FanOut, "wrong fan-out at: %d", degree.Node.Id)
+	}
+}
+
+func TestNetwork_EffectiveSize(t *testing.T) {
+	net := buildNetworkWithDeadNodes()
+	nodes, links := net.EffectiveSize()
+	assert.Equal(t, net.NodeCount()-2, nodes)
+	assert.Equal(t, net.LinkCount()-3, links)
+}
+
+func TestNetwork_Modularity(t *testing.T) {
+	net := buildTwoTrianglesNetwork()
+	q, communities := net.Modularity()
+	assert.InDelta(t, 5.0/14.0, q, 1e-9)
+	require.Len(t, communities, 2)
+	assert.ElementsMatch(t, []int{1, 2, 3}, nodeIds(communities[0]))
+	assert.ElementsMatch(t, []int{4, 5, 6}, nodeIds(communities[1]))
+
+	// network without links
+	net = NewNetwork(nil, nil, []*NNode{NewNNode(1, InputNeuron), NewNNode(2, OutputNeuron)}, 0)
+	q, communities = net.Modularity()
+	assert.Zero(t, q)
+	assert.Len(t, communities, 2)
+}
+
+func TestNetwork_Analyze(t *testing.T) {
+	net := buildNetwork()
+	analysis := net.Analyze()
+	require.NotNil(t, analysis)
+	assert.Equal(t, 8, analysis.Nodes)
+	assert.Equal(t, 8, analysis.Links)
+	assert.Equal(t, 8, analysis.EffectiveNodes)
+	assert.Equal(t, 8, analysis.EffectiveLinks)
+	assert.Zero(t, analysis.RecurrentComponents)
+	assert.Zero(t, analysis.Cycles)
+	assert.False(t, analysis.CyclesTruncated)
+	assert.Equal(t, 4, analysis.Layers)
+	assert.Equal(t, 6, analysis.InputOutputPaths)
+	assert.Equal(t, 2, analysis.MaxFanIn)
+	assert.Equal(t, 2, analysis.MaxFanOut)
+	assert.True(t, analysis.Communities > 0)
+
+	// cycle without recurrent mark
+	nodes := net.AllNodes()
+	nodes[4].addIncoming(nodes[5], 1.0)
+	analysis = net.Analyze()
+	assert.Equal(t, 1, analysis.RecurrentComponents)
+	assert.Equal(t, 1, analysis.Cycles)
+	assert.Zero(t, analysis.Layers)
+	assert.Equal(t, -1, analysis.InputOutputPaths)
+}
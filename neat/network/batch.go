@@ -0,0 +1,279 @@
+package network
+
+import (
+	neatmath "github.com/yaricom/goNEAT/v2/neat/math"
+)
+
+// The batch signals are stored in neuron-major order, i.e. the signals of all samples for particular neuron are stored
+// in continuous range [neuron * batchSize, (neuron + 1) * batchSize). Such layout allows to process each connection
+// for all samples in the batch within the tight loop over adjacent memory cells.
+
+// ActivateBatch Evaluates the batch of independent input samples provided as matrix with one row per sample. The
+// acyclic network is evaluated in single pass over precomputed connection arrays in topological order, thus
+// steps number should be positive but otherwise ignored. Returns matrix of outputs with one row per sample.
+func (s *FeedForwardNetworkSolver) ActivateBatch(inputs [][]float64, steps int) ([][]float64, error) {
+	if steps <= 0 {
+		return nil, NetErrInvalidStepsNumber
+	}
+	batchSize := len(inputs)
+	if batchSize == 0 {
+		return [][]float64{}, nil
+	}
+	signals, err := newBatchSignals(inputs, s.biasNeuronCount, s.inputNeuronCount, s.totalNeuronCount)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.evaluateBatch(signals, batchSize); err != nil {
+		return nil, err
+	}
+	return readBatchOutputs(signals, batchSize, s.sensorNeuronCount, s.outputNeuronCount), nil
+}
+
+// ActivateSequences Evaluates independent sequences of input samples. The feed-forward network has no state carried
+// between time steps, thus all time steps of all sequences are evaluated as one batch. Returns outputs in the same
+// layout as inputs, i.e. outputs[i][t] is the output of sequence i at time step t.
+func (s *FeedForwardNetworkSolver) ActivateSequences(sequences [][][]float64, steps int) ([][][]float64, error) {
+	samples := make([][]float64, 0)
+	for _, sequence := range sequences {
+		samples = append(samples, sequence...)
+	}
+	outputs, err := s.ActivateBatch(samples, steps)
+	if err != nil {
+		return nil, err
+	}
+	res := make([][][]float64, len(sequences))
+	start := 0
+	for i, sequence := range sequences {
+		res[i] = outputs[start : start+len(sequence)]
+		start += len(sequence)
+	}
+	return res, nil
+}
+
+// evaluateBatch Propagates sensors signals of all samples in the batch through all network units in topological order
+func (s *FeedForwardNetworkSolver) evaluateBatch(signals []float64, batchSize int) error {
+	for _, unit := range s.schedule {
+		if unit < s.totalNeuronCount {
+			acc := signals[unit*batchSize : (unit+1)*batchSize]
+			for b := range acc {
+				acc[b] = 0
+			}
+			for j := s.incomingStart[unit]; j < s.incomingStart[unit+1]; j++ {
+				source := s.incomingSources[j] * batchSize
+				src := signals[source : source+batchSize]
+				weight := s.incomingWeights[j]
+				for b, signal := range src {
+					acc[b] += signal * weight
+				}
+			}
+			if err := activateBatchSignals(acc, s.neuronBias(unit), s.activationFunctions[unit]); err != nil {
+				return err
+			}
+		} else {
+			module := s.modules[unit-s.totalNeuronCount]
+			if err := activateBatchModule(module, signals, batchSize); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ActivateBatch Evaluates the batch of independent input samples provided as matrix with one row per sample. Each
+// sample is evaluated from the flushed network state by propagating activation wave the given number of steps in
+// forward direction. All samples are processed in lockstep. Returns matrix of outputs with one row per sample.
+func (s *FastModularNetworkSolver) ActivateBatch(inputs [][]float64, steps int) ([][]float64, error) {
+	sequences := make([][][]float64, len(inputs))
+	for i, in := range inputs {
+		sequences[i] = [][]float64{in}
+	}
+	outputs, err := s.ActivateSequences(sequences, steps)
+	if err != nil {
+		return nil, err
+	}
+	res := make([][]float64, len(outputs))
+	for i, out := range outputs {
+		res[i] = out[0]
+	}
+	return res, nil
+}
+
+// ActivateSequences Evaluates independent sequences of input samples in lockstep, i.e. the sequence[i][t] is the
+// input of sequence i at time step t. The network state is kept separately for each sequence, starting from
+// the flushed state, and the activation wave is propagated the given number of steps per each time step. The sequences
+// may have different lengths, the sequences which are already finished are not loaded with new inputs. Returns outputs
+// in the same layout as inputs, i.e. outputs[i][t] is the output of sequence i at time step t.
+func (s *FastModularNetworkSolver) ActivateSequences(sequences [][][]float64, steps int) ([][][]float64, error) {
+	if steps <= 0 {
+		return nil, NetErrInvalidStepsNumber
+	}
+	batchSize := len(sequences)
+	if batchSize == 0 {
+		return [][][]float64{}, nil
+	}
+	maxLength := 0
+	for _, sequence := range sequences {
+		for _, in := range sequence {
+			if len(in) != s.inputNeuronCount {
+				return nil, NetErrUnsupportedSensorsArraySize
+			}
+		}
+		if len(sequence) > maxLength {
+			maxLength = len(sequence)
+		}
+	}
+
+	signals := make([]float64, s.totalNeuronCount*batchSize)
+	for i := 0; i < s.biasNeuronCount*batchSize; i++ {
+		signals[i] = 1.0 // BIAS neuron signal
+	}
+	processed := make([]float64, s.totalNeuronCount*batchSize)
+	outputs := make([][][]float64, batchSize)
+	for i, sequence := range sequences {
+		outputs[i] = make([][]float64, len(sequence))
+	}
+
+	for t := 0; t < maxLength; t++ {
+		// load sensors of the sequences having current time step
+		for b, sequence := range sequences {
+			if t < len(sequence) {
+				for i, value := range sequence[t] {
+					signals[(s.biasNeuronCount+i)*batchSize+b] = value
+				}
+			}
+		}
+		for step := 0; step < steps; step++ {
+			if err := s.forwardStepBatch(signals, processed, batchSize); err != nil {
+				return nil, err
+			}
+		}
+		// collect outputs
+		for b, sequence := range sequences {
+			if t < len(sequence) {
+				out := make([]float64, s.outputNeuronCount)
+				for i := range out {
+					out[i] = signals[(s.sensorNeuronCount+i)*batchSize+b]
+				}
+				outputs[b][t] = out
+			}
+		}
+	}
+	return outputs, nil
+}
+
+// forwardStepBatch Performs single forward activation step for all samples in the batch. It follows the same order of
+// operations as forwardStep, thus producing the same results as evaluation of each sample separately.
+func (s *FastModularNetworkSolver) forwardStepBatch(signals, processed []float64, batchSize int) error {
+	// Calculate output signal per each connection and add the signals to the target neurons
+	for _, conn := range s.connections {
+		source := conn.SourceIndex * batchSize
+		target := conn.TargetIndex * batchSize
+		src := signals[source : source+batchSize]
+		dst := processed[target : target+batchSize]
+		for b, signal := range src {
+			dst[b] += signal * conn.Weight
+		}
+	}
+
+	// Pass the signals through the single-valued activation functions
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		if err := activateBatchSignals(processed[i*batchSize:(i+1)*batchSize],
+			s.neuronBias(i), s.activationFunctions[i]); err != nil {
+			return err
+		}
+	}
+
+	// Pass the signals through each module (activation function with more than one input or output)
+	for _, module := range s.modules {
+		if err := activateBatchModule(module, processed, batchSize); err != nil {
+			return err
+		}
+	}
+
+	// Move all the neuron signals we changed while processing this network activation into storage.
+	start, end := s.sensorNeuronCount*batchSize, s.totalNeuronCount*batchSize
+	copy(signals[start:end], processed[start:end])
+	for i := start; i < end; i++ {
+		processed[i] = 0
+	}
+	return nil
+}
+
+// neuronBias Returns the bias value of the neuron at given index wrapped into slice or nil if network has no bias
+func (s *FeedForwardNetworkSolver) neuronBias(index int) []float64 {
+	if s.biasNeuronCount > 0 {
+		return s.biasList[index : index+1]
+	}
+	return nil
+}
+
+// neuronBias Returns the bias value of the neuron at given index wrapped into slice or nil if network has no bias
+func (s *FastModularNetworkSolver) neuronBias(index int) []float64 {
+	if s.biasNeuronCount > 0 {
+		return s.biasList[index : index+1]
+	}
+	return nil
+}
+
+// newBatchSignals Creates neuron-major signals storage for the batch and loads sensors with provided inputs
+func newBatchSignals(inputs [][]float64, biasNeuronCount, inputNeuronCount, totalNeuronCount int) ([]float64, error) {
+	batchSize := len(inputs)
+	signals := make([]float64, totalNeuronCount*batchSize)
+	for i := 0; i < biasNeuronCount*batchSize; i++ {
+		signals[i] = 1.0 // BIAS neuron signal
+	}
+	for b, in := range inputs {
+		if len(in) != inputNeuronCount {
+			return nil, NetErrUnsupportedSensorsArraySize
+		}
+		for i, value := range in {
+			signals[(biasNeuronCount+i)*batchSize+b] = value
+		}
+	}
+	return signals, nil
+}
+
+// readBatchOutputs Reads output values of all samples in the batch into the matrix with one row per sample
+func readBatchOutputs(signals []float64, batchSize, sensorNeuronCount, outputNeuronCount int) [][]float64 {
+	outputs := make([][]float64, batchSize)
+	for b := range outputs {
+		outputs[b] = make([]float64, outputNeuronCount)
+		for i := range outputs[b] {
+			outputs[b][i] = signals[(sensorNeuronCount+i)*batchSize+b]
+		}
+	}
+	return outputs
+}
+
+// activateBatchSignals Applies activation function to the accumulated signals of particular neuron for all samples in
+// the batch, appending the bias value if appropriate.
+func activateBatchSignals(signals []float64, bias []float64, activationType neatmath.NodeActivationType) (err error) {
+	for b, signal := range signals {
+		if len(bias) > 0 {
+			// append BIAS value to the signal if appropriate
+			signal += bias[0]
+		}
+		if signals[b], err = neatmath.NodeActivators.ActivateByType(signal, nil, activationType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activateBatchModule Passes the signals of all samples in the batch through the module.
+func activateBatchModule(module *FastControlNode, signals []float64, batchSize int) error {
+	inputs := make([]float64, len(module.InputIndexes))
+	for b := 0; b < batchSize; b++ {
+		for i, inIndex := range module.InputIndexes {
+			inputs[i] = signals[inIndex*batchSize+b]
+		}
+		outputs, err := neatmath.NodeActivators.ActivateModuleByType(inputs, nil, module.ActivationType)
+		if err != nil {
+			return err
+		}
+		for i, outIndex := range module.OutputIndexes {
+			signals[outIndex*batchSize+b] = outputs[i]
+		}
+	}
+	return nil
+}
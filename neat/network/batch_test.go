@@ -0,0 +1,129 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var batchInputs = [][]float64{
+	{0.5, 1.1},
+	{1.5, 2.0},
+	{-1.0, 0.0},
+	{0.0, 0.3},
+}
+
+func TestFeedForwardNetworkSolver_ActivateBatch(t *testing.T) {
+	for _, net := range []*Network{buildNetwork(), buildModularNetwork()} {
+		ffs, err := net.FeedForwardSolver()
+		require.NoError(t, err, "failed to create feed-forward solver")
+
+		outputs, err := ffs.ActivateBatch(batchInputs, 1)
+		require.NoError(t, err, "failed to activate batch")
+		require.Len(t, outputs, len(batchInputs))
+
+		for i, in := range batchInputs {
+			err = ffs.LoadSensors(in)
+			require.NoError(t, err, "failed to load sensors")
+			res, err := ffs.RecursiveSteps()
+			require.NoError(t, err, "failed to activate")
+			require.True(t, res)
+			assert.Equal(t, ffs.ReadOutputs(), outputs[i], "wrong outputs of sample: %d", i)
+		}
+	}
+}
+
+func TestFeedForwardNetworkSolver_ActivateSequences(t *testing.T) {
+	net := buildModularNetwork()
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+
+	sequences := [][][]float64{batchInputs[:3], batchInputs[3:], {}}
+	outputs, err := ffs.ActivateSequences(sequences, 1)
+	require.NoError(t, err, "failed to activate sequences")
+	require.Len(t, outputs, len(sequences))
+
+	expected, err := ffs.ActivateBatch(batchInputs, 1)
+	require.NoError(t, err, "failed to activate batch")
+	assert.Equal(t, expected[:3], outputs[0])
+	assert.Equal(t, expected[3:], outputs[1])
+	assert.Len(t, outputs[2], 0)
+}
+
+func TestFastModularNetworkSolver_ActivateBatch(t *testing.T) {
+	for _, net := range []*Network{buildNetwork(), buildModularNetwork()} {
+		fmm, err := net.FastModularSolver()
+		require.NoError(t, err, "failed to create fast network solver")
+
+		steps := 5
+		outputs, err := fmm.ActivateBatch(batchInputs, steps)
+		require.NoError(t, err, "failed to activate batch")
+		require.Len(t, outputs, len(batchInputs))
+
+		for i, in := range batchInputs {
+			_, err = fmm.Flush()
+			require.NoError(t, err, "failed to flush")
+			err = fmm.LoadSensors(in)
+			require.NoError(t, err, "failed to load sensors")
+			_, err = fmm.ForwardSteps(steps)
+			require.NoError(t, err, "failed to activate")
+			assert.Equal(t, fmm.ReadOutputs(), outputs[i], "wrong outputs of sample: %d", i)
+		}
+	}
+}
+
+func TestFastModularNetworkSolver_ActivateSequences(t *testing.T) {
+	net := buildNetwork()
+	// add recurrent link to make network state matter between time steps
+	nodes := net.AllNodes()
+	link := NewLink(0.5, nodes[7], nodes[3], true)
+	nodes[3].Incoming = append(nodes[3].Incoming, link)
+
+	fmm, err := net.FastModularSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+
+	sequences := [][][]float64{batchInputs, batchInputs[1:3], {batchInputs[3]}}
+	steps := 2
+	outputs, err := fmm.ActivateSequences(sequences, steps)
+	require.NoError(t, err, "failed to activate sequences")
+	require.Len(t, outputs, len(sequences))
+
+	for i, sequence := range sequences {
+		_, err = fmm.Flush()
+		require.NoError(t, err, "failed to flush")
+		require.Len(t, outputs[i], len(sequence))
+		for j, in := range sequence {
+			err = fmm.LoadSensors(in)
+			require.NoError(t, err, "failed to load sensors")
+			_, err = fmm.ForwardSteps(steps)
+			require.NoError(t, err, "failed to activate")
+			assert.Equal(t, fmm.ReadOutputs(), outputs[i][j], "wrong outputs of sequence: %d at: %d", i, j)
+		}
+	}
+}
+
+func TestBatchSolver_Errors(t *testing.T) {
+	net := buildNetwork()
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	fmm, err := net.FastModularSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+
+	for _, s := range []Solver{solver, fmm} {
+		batch, ok := s.(BatchSolver)
+		require.True(t, ok, "batch solver expected: %T", s)
+
+		_, err = batch.ActivateBatch(batchInputs, 0)
+		assert.EqualError(t, err, NetErrInvalidStepsNumber.Error())
+
+		_, err = batch.ActivateBatch([][]float64{{1.0, 2.0, 3.0}}, 1)
+		assert.EqualError(t, err, NetErrUnsupportedSensorsArraySize.Error())
+
+		_, err = batch.ActivateSequences([][][]float64{{{1.0}}}, 1)
+		assert.EqualError(t, err, NetErrUnsupportedSensorsArraySize.Error())
+
+		outputs, err := batch.ActivateBatch([][]float64{}, 1)
+		assert.NoError(t, err)
+		assert.Len(t, outputs, 0)
+	}
+}
@@ -15,6 +15,10 @@ var (
 	NetErrUnsupportedSensorsArraySize = errors.New("the sensors array size is unsupported by network solver")
 	// NetErrDepthCalculationFailedLoopDetected The error to be raised when depth calculation failed due to the loop in network
 	NetErrDepthCalculationFailedLoopDetected = errors.New("depth can not be determined for network with loop")
+	// NetErrNetworkHasCycles The error to be raised when feed-forward solver requested for network with cycles
+	NetErrNetworkHasCycles = errors.New("the network has cycles and can not be evaluated in topological order")
+	// NetErrInvalidStepsNumber The error to be raised when non-positive number of activation steps requested
+	NetErrInvalidStepsNumber = errors.New("the number of activation steps should be positive")
 )
 
 // NodeType NNodeType defines the type of NNode to create
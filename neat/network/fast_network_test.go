@@ -11,7 +11,7 @@ func TestFastModularNetworkSolver_RecursiveSteps(t *testing.T) {
 
 	// Create network solver
 	data := []float64{0.5, 1.1} // BIAS is 1.0 by definition
-	fmm, err := net.FastNetworkSolver()
+	fmm, err := net.FastModularSolver()
 	require.NoError(t, err, "failed to create fast network solver")
 	err = fmm.LoadSensors(data)
 	require.NoError(t, err, "failed to load sensors")
@@ -50,7 +50,7 @@ func TestFastModularNetworkSolver_ForwardSteps(t *testing.T) {
 
 	// create network solver
 	data := []float64{1.0, 2.0} // bias inherent
-	fmm, err := net.FastNetworkSolver()
+	fmm, err := net.FastModularSolver()
 	require.NoError(t, err, "failed to create fast network solver")
 	err = fmm.LoadSensors(data)
 	require.NoError(t, err, "failed to load sensors")
@@ -87,7 +87,7 @@ func TestFastModularNetworkSolver_Relax(t *testing.T) {
 
 	// create network solver
 	data := []float64{1.5, 2.0} // bias inherent
-	fmm, err := net.FastNetworkSolver()
+	fmm, err := net.FastModularSolver()
 	require.NoError(t, err, "failed to create fast network solver")
 	err = fmm.LoadSensors(data)
 	require.NoError(t, err, "failed to load sensors")
@@ -124,14 +124,13 @@ func TestFastModularNetworkSolver_Flush(t *testing.T) {
 
 	// create network solver
 	data := []float64{1.5, 2.0} // bias inherent
-	fmm, err := net.FastNetworkSolver()
+	fmm, err := net.FastModularSolver()
 	require.NoError(t, err, "failed to create fast network solver")
 	err = fmm.LoadSensors(data)
 	require.NoError(t, err, "failed to load sensors")
 
-	fmmImpl := fmm.(*FastModularNetworkSolver)
 	// test that network has active signals
-	active := countActiveSignals(fmmImpl)
+	active := countActiveSignals(fmm)
 	assert.NotZero(t, active, "no active signal found")
 
 	// flush and test
@@ -139,14 +138,14 @@ func TestFastModularNetworkSolver_Flush(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, res, "failed to flush network")
 
-	active = countActiveSignals(fmmImpl)
+	active = countActiveSignals(fmm)
 	assert.Zero(t, active, "after flush the active signal still present")
 }
 
 func TestFastModularNetworkSolver_NodeCount(t *testing.T) {
 	net := buildModularNetwork()
 
-	fmm, err := net.FastNetworkSolver()
+	fmm, err := net.FastModularSolver()
 	require.NoError(t, err, "failed to create fast network solver")
 	assert.Equal(t, 9, fmm.NodeCount())
 }
@@ -154,7 +153,7 @@ func TestFastModularNetworkSolver_NodeCount(t *testing.T) {
 func TestFastModularNetworkSolver_LinkCount(t *testing.T) {
 	net := buildModularNetwork()
 
-	fmm, err := net.FastNetworkSolver()
+	fmm, err := net.FastModularSolver()
 	require.NoError(t, err, "failed to create fast network solver")
 	assert.Equal(t, 9, fmm.LinkCount())
 }
@@ -0,0 +1,289 @@
+package network
+
+import (
+	"fmt"
+	neatmath "github.com/yaricom/goNEAT/v2/neat/math"
+)
+
+// FeedForwardNetworkSolver is the network solver implementation for acyclic networks. It computes the topological
+// order of neurons (and modules) once at construction and evaluates every neuron exactly once per input, without any
+// relaxation loop.
+type FeedForwardNetworkSolver struct {
+	// A network id
+	Id int
+	// Is a name of this network */
+	Name string
+
+	// The current activation values per each neuron
+	neuronSignals []float64
+
+	// The activation functions per neuron, must be in the same order as neuronSignals.
+	activationFunctions []neatmath.NodeActivationType
+	// The bias values associated with neurons
+	biasList []float64
+	// The control nodes relaying between network modules
+	modules []*FastControlNode
+
+	// The evaluation schedule in topological order. The values less than totalNeuronCount are indexes of neurons,
+	// the rest are indexes of modules shifted by totalNeuronCount.
+	schedule []int
+	// The number of layers in the network, i.e. the length of the longest path from sensors to any unit
+	layersCount int
+
+	// The incoming connections of neurons stored in compressed form: the connections of neuron at index i are
+	// stored in range [incomingStart[i], incomingStart[i+1]) of incomingSources and incomingWeights arrays.
+	incomingStart []int
+	// The indexes of source neurons of incoming connections
+	incomingSources []int
+	// The weights of incoming connections
+	incomingWeights []float64
+
+	// The number of input neurons
+	inputNeuronCount int
+	// The total number of sensors in the network (input + bias). This is also the index of the first output neuron in the neuron signals.
+	sensorNeuronCount int
+	// The number of output neurons
+	outputNeuronCount int
+	// The bias neuron count (usually one). This is also the index of the first input neuron in the neuron signals.
+	biasNeuronCount int
+	// The total number of neurons in network
+	totalNeuronCount int
+	// The total number of connections in network
+	connectionsCount int
+}
+
+// NewFeedForwardNetworkSolver Creates new feed-forward network solver. The neurons should be ordered as following:
+// bias, input, output, hidden. Returns NetErrNetworkHasCycles error if provided connections or modules form a cycle.
+func NewFeedForwardNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuronCount, totalNeuronCount int,
+	activationFunctions []neatmath.NodeActivationType, connections []*FastNetworkLink,
+	biasList []float64, modules []*FastControlNode) (*FeedForwardNetworkSolver, error) {
+
+	ffs := FeedForwardNetworkSolver{
+		biasNeuronCount:     biasNeuronCount,
+		inputNeuronCount:    inputNeuronCount,
+		sensorNeuronCount:   biasNeuronCount + inputNeuronCount,
+		outputNeuronCount:   outputNeuronCount,
+		totalNeuronCount:    totalNeuronCount,
+		activationFunctions: activationFunctions,
+		biasList:            biasList,
+		modules:             modules,
+		connectionsCount:    len(connections),
+	}
+
+	// The neuron signals are initialised to 0 by default. Only bias nodes need setting to 1.
+	ffs.neuronSignals = make([]float64, totalNeuronCount)
+	for i := 0; i < biasNeuronCount; i++ {
+		ffs.neuronSignals[i] = 1.0 // BIAS neuron signal
+	}
+
+	// Build compressed incoming connections lists preserving the original order of connections per target neuron
+	ffs.incomingStart = make([]int, totalNeuronCount+1)
+	for _, conn := range connections {
+		ffs.incomingStart[conn.TargetIndex+1]++
+	}
+	for i := 0; i < totalNeuronCount; i++ {
+		ffs.incomingStart[i+1] += ffs.incomingStart[i]
+	}
+	ffs.incomingSources = make([]int, len(connections))
+	ffs.incomingWeights = make([]float64, len(connections))
+	fill := make([]int, totalNeuronCount)
+	copy(fill, ffs.incomingStart[:totalNeuronCount])
+	for _, conn := range connections {
+		ffs.incomingSources[fill[conn.TargetIndex]] = conn.SourceIndex
+		ffs.incomingWeights[fill[conn.TargetIndex]] = conn.Weight
+		fill[conn.TargetIndex]++
+	}
+
+	if err := ffs.buildSchedule(connections); err != nil {
+		return nil, err
+	}
+	return &ffs, nil
+}
+
+// Builds evaluation schedule by sorting network units (neurons and modules) in topological order layer by layer.
+func (s *FeedForwardNetworkSolver) buildSchedule(connections []*FastNetworkLink) error {
+	unitsCount := s.totalNeuronCount + len(s.modules)
+	inDegree := make([]int, unitsCount)
+	outgoing := make([][]int, unitsCount)
+	// the neurons which values are set by modules
+	moduleOutputs := make([]bool, s.totalNeuronCount)
+
+	for _, conn := range connections {
+		if conn.TargetIndex < s.sensorNeuronCount {
+			// sensors are never updated by solver
+			continue
+		}
+		outgoing[conn.SourceIndex] = append(outgoing[conn.SourceIndex], conn.TargetIndex)
+		inDegree[conn.TargetIndex]++
+	}
+	for i, module := range s.modules {
+		unit := s.totalNeuronCount + i
+		for _, inIndex := range module.InputIndexes {
+			outgoing[inIndex] = append(outgoing[inIndex], unit)
+			inDegree[unit]++
+		}
+		for _, outIndex := range module.OutputIndexes {
+			outgoing[unit] = append(outgoing[unit], outIndex)
+			inDegree[outIndex]++
+			moduleOutputs[outIndex] = true
+		}
+	}
+
+	// Kahn's algorithm processing one layer at a time
+	frontier := make([]int, 0)
+	for i := 0; i < unitsCount; i++ {
+		if inDegree[i] == 0 {
+			frontier = append(frontier, i)
+		}
+	}
+	s.schedule = make([]int, 0, unitsCount)
+	processed := 0
+	for len(frontier) > 0 {
+		next := make([]int, 0)
+		for _, unit := range frontier {
+			processed++
+			if unit >= s.sensorNeuronCount && (unit >= s.totalNeuronCount || !moduleOutputs[unit]) {
+				// the values of sensors and module outputs are not computed by neuron activation
+				s.schedule = append(s.schedule, unit)
+			}
+			for _, target := range outgoing[unit] {
+				inDegree[target]--
+				if inDegree[target] == 0 {
+					next = append(next, target)
+				}
+			}
+		}
+		frontier = next
+		s.layersCount++
+	}
+	if processed != unitsCount {
+		return NetErrNetworkHasCycles
+	}
+	return nil
+}
+
+// evaluate Propagates sensors signals through all network units in topological order
+func (s *FeedForwardNetworkSolver) evaluate() (err error) {
+	for _, unit := range s.schedule {
+		if unit < s.totalNeuronCount {
+			signal := 0.0
+			for j := s.incomingStart[unit]; j < s.incomingStart[unit+1]; j++ {
+				signal += s.neuronSignals[s.incomingSources[j]] * s.incomingWeights[j]
+			}
+			if s.biasNeuronCount > 0 {
+				// append BIAS value to the signal if appropriate
+				signal += s.biasList[unit]
+			}
+			if s.neuronSignals[unit], err = neatmath.NodeActivators.ActivateByType(
+				signal, nil, s.activationFunctions[unit]); err != nil {
+				return err
+			}
+		} else {
+			module := s.modules[unit-s.totalNeuronCount]
+			inputs := make([]float64, len(module.InputIndexes))
+			for i, inIndex := range module.InputIndexes {
+				inputs[i] = s.neuronSignals[inIndex]
+			}
+			outputs, err := neatmath.NodeActivators.ActivateModuleByType(inputs, nil, module.ActivationType)
+			if err != nil {
+				return err
+			}
+			for i, outIndex := range module.OutputIndexes {
+				s.neuronSignals[outIndex] = outputs[i]
+			}
+		}
+	}
+	return nil
+}
+
+// ForwardSteps Propagates activation wave through all network nodes. Due to the acyclic network topology, the single
+// evaluation pass is enough to propagate signals from all inputs to the outputs, thus the network evaluated only once
+// if steps number is positive. Returns true if activation wave passed from all inputs to the outputs.
+func (s *FeedForwardNetworkSolver) ForwardSteps(steps int) (bool, error) {
+	if steps <= 0 {
+		return false, nil
+	}
+	if err := s.evaluate(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RecursiveSteps Propagates activation wave through all network nodes in topological order, which is equivalent to
+// the recursive activation from output nodes for acyclic networks. Returns true if activation wave passed from all
+// inputs to the outputs.
+func (s *FeedForwardNetworkSolver) RecursiveSteps() (bool, error) {
+	if err := s.evaluate(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Relax Propagates activation wave through all network nodes. The acyclic network is always relaxed after single
+// evaluation pass, thus this method returns true if at least one step is allowed.
+func (s *FeedForwardNetworkSolver) Relax(maxSteps int, _ float64) (bool, error) {
+	return s.ForwardSteps(maxSteps)
+}
+
+// Flush Flushes network state by removing all current activations. Returns true if network flushed successfully or
+// false in case of error.
+func (s *FeedForwardNetworkSolver) Flush() (bool, error) {
+	for i := s.biasNeuronCount; i < s.totalNeuronCount; i++ {
+		s.neuronSignals[i] = 0.0
+	}
+	return true, nil
+}
+
+// LoadSensors Set sensors values to the input nodes of the network
+func (s *FeedForwardNetworkSolver) LoadSensors(inputs []float64) error {
+	if len(inputs) != s.inputNeuronCount {
+		return NetErrUnsupportedSensorsArraySize
+	}
+	copy(s.neuronSignals[s.biasNeuronCount:s.sensorNeuronCount], inputs)
+	return nil
+}
+
+// ReadOutputs Read output values from the output nodes of the network
+func (s *FeedForwardNetworkSolver) ReadOutputs() []float64 {
+	return s.neuronSignals[s.sensorNeuronCount : s.sensorNeuronCount+s.outputNeuronCount]
+}
+
+// NodeCount Returns the total number of neural units in the network
+func (s *FeedForwardNetworkSolver) NodeCount() int {
+	return s.totalNeuronCount + len(s.modules)
+}
+
+// LinkCount Returns the total number of links between nodes in the network
+func (s *FeedForwardNetworkSolver) LinkCount() int {
+	// count all connections
+	numLinks := s.connectionsCount
+
+	// count all bias links if any
+	if s.biasNeuronCount > 0 {
+		for _, b := range s.biasList {
+			if b != 0 {
+				numLinks++
+			}
+		}
+	}
+
+	// count all modules links
+	for _, module := range s.modules {
+		numLinks += len(module.InputIndexes) + len(module.OutputIndexes)
+	}
+	return numLinks
+}
+
+// LayersCount Returns the number of layers in the network, i.e. the number of units on the longest path from
+// the sensors to any other network unit, including sensors.
+func (s *FeedForwardNetworkSolver) LayersCount() int {
+	return s.layersCount
+}
+
+// Stringer
+func (s *FeedForwardNetworkSolver) String() string {
+	str := fmt.Sprintf("FeedForwardNetwork, id: %d, name: [%s], neurons: %d,\n\tinputs: %d,\tbias: %d,\toutputs:%d,\t hidden: %d,\t layers: %d",
+		s.Id, s.Name, s.totalNeuronCount, s.inputNeuronCount, s.biasNeuronCount, s.outputNeuronCount,
+		s.totalNeuronCount-s.sensorNeuronCount-s.outputNeuronCount, s.layersCount)
+	return str
+}
@@ -0,0 +1,159 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestFeedForwardNetworkSolver_RecursiveSteps(t *testing.T) {
+	net := buildNetwork()
+
+	data := []float64{0.5, 1.1} // BIAS is 1.0 by definition
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	err = ffs.LoadSensors(data)
+	require.NoError(t, err, "failed to load sensors")
+
+	// Activate fast modular solver as reference
+	fmm, err := net.FastModularSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	err = fmm.LoadSensors(data)
+	require.NoError(t, err, "failed to load sensors")
+	res, err := fmm.RecursiveSteps()
+	require.NoError(t, err, "error when trying to activate Fast Network Solver")
+	require.True(t, res, "recursive activation failed")
+
+	res, err = ffs.RecursiveSteps()
+	require.NoError(t, err, "error when trying to activate feed-forward solver")
+	require.True(t, res, "recursive activation failed")
+
+	outputs := ffs.ReadOutputs()
+	expected := fmm.ReadOutputs()
+	require.Len(t, outputs, len(expected))
+	for i, out := range outputs {
+		assert.Equal(t, expected[i], out, "wrong activation at: %d", i)
+	}
+}
+
+func TestFeedForwardNetworkSolver_ForwardSteps(t *testing.T) {
+	net := buildModularNetwork()
+
+	data := []float64{1.0, 2.0} // bias inherent
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	err = ffs.LoadSensors(data)
+	require.NoError(t, err, "failed to load sensors")
+
+	// activate objective network
+	data = append(data, 1.0) // BIAS is third object
+	err = net.LoadSensors(data)
+	require.NoError(t, err, "failed to load sensors")
+	steps := 5
+	for i := 0; i < steps; i++ {
+		res, err := net.Activate()
+		require.NoError(t, err, "error when trying to activate at: %d", i)
+		require.True(t, res, "failed to activate at: %d", i)
+	}
+
+	res, err := ffs.ForwardSteps(steps)
+	require.NoError(t, err, "error while do forward steps")
+	require.True(t, res, "forward steps returned false")
+
+	outputs := ffs.ReadOutputs()
+	for i, out := range outputs {
+		assert.Equal(t, net.Outputs[i].Activation, out, "wrong activation at: %d", i)
+	}
+
+	// zero steps should not evaluate
+	res, err = ffs.ForwardSteps(0)
+	require.NoError(t, err)
+	assert.False(t, res)
+}
+
+func TestFeedForwardNetworkSolver_Relax(t *testing.T) {
+	net := buildModularNetwork()
+
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	err = ffs.LoadSensors([]float64{1.5, 2.0})
+	require.NoError(t, err, "failed to load sensors")
+
+	res, err := ffs.Relax(1, 0.001)
+	require.NoError(t, err)
+	assert.True(t, res, "acyclic network must relax after single step")
+	assert.Equal(t, []float64{1608.75, 4647.5}, ffs.ReadOutputs())
+}
+
+func TestFeedForwardNetworkSolver_Flush(t *testing.T) {
+	net := buildNetwork()
+
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	err = ffs.LoadSensors([]float64{1.5, 2.0})
+	require.NoError(t, err, "failed to load sensors")
+	_, err = ffs.RecursiveSteps()
+	require.NoError(t, err)
+
+	res, err := ffs.Flush()
+	require.NoError(t, err)
+	require.True(t, res, "failed to flush network")
+	for i := ffs.biasNeuronCount; i < ffs.totalNeuronCount; i++ {
+		assert.Zero(t, ffs.neuronSignals[i], "signal is not flushed at: %d", i)
+	}
+	assert.Equal(t, 1.0, ffs.neuronSignals[0], "bias signal must stay")
+}
+
+func TestFeedForwardNetworkSolver_LoadSensors(t *testing.T) {
+	net := buildNetwork()
+
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	err = ffs.LoadSensors([]float64{1.5, 2.0, 1.0})
+	assert.EqualError(t, err, NetErrUnsupportedSensorsArraySize.Error())
+}
+
+func TestFeedForwardNetworkSolver_Structure(t *testing.T) {
+	net := buildNetwork()
+
+	ffs, err := net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	assert.Equal(t, net.NodeCount(), ffs.NodeCount())
+	assert.Equal(t, net.LinkCount(), ffs.LinkCount())
+	assert.Equal(t, 4, ffs.LayersCount())
+
+	net = buildModularNetwork()
+	ffs, err = net.FeedForwardSolver()
+	require.NoError(t, err, "failed to create feed-forward solver")
+	assert.Equal(t, 9, ffs.NodeCount())
+	assert.Equal(t, 9, ffs.LinkCount())
+}
+
+func TestFeedForwardNetworkSolver_Cycle(t *testing.T) {
+	net := buildNetwork()
+	// introduce cycle between hidden nodes not marked as recurrent
+	nodes := net.AllNodes()
+	nodes[4].addIncoming(nodes[5], 1.0)
+
+	_, err := net.FeedForwardSolver()
+	assert.EqualError(t, err, NetErrNetworkHasCycles.Error())
+
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	assert.IsType(t, &FastModularNetworkSolver{}, solver)
+}
+
+func TestNetwork_FastNetworkSolver_Selection(t *testing.T) {
+	net := buildNetwork()
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	assert.IsType(t, &FeedForwardNetworkSolver{}, solver)
+
+	// the network with recurrent link
+	nodes := net.AllNodes()
+	link := NewLink(1.0, nodes[7], nodes[3], true)
+	nodes[3].Incoming = append(nodes[3].Incoming, link)
+	solver, err = net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	assert.IsType(t, &FastModularNetworkSolver{}, solver)
+}
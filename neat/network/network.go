@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/yaricom/goNEAT/v2/neat/math"
+	gomath "math"
 )
 
 // Network is a collection of all nodes within an organism's phenotype, which effectively defines Neural Network topology.
@@ -48,8 +49,71 @@ func NewModularNetwork(in, out, all, control []*NNode, netId int) *Network {
 }
 
 // FastNetworkSolver Creates fast network solver based on the architecture of this network. It's primarily aimed for
-// big networks to improve processing speed.
+// big networks to improve processing speed. If this network has no recurrent links and no cycles, the
+// FeedForwardNetworkSolver will be created, which evaluates each neuron exactly once per input. Otherwise, the
+// FastModularNetworkSolver will be returned. Both returned solvers implement BatchSolver interface.
 func (n *Network) FastNetworkSolver() (Solver, error) {
+	return n.fastNetworkSolver(nil)
+}
+
+// Creates the most appropriate fast network solver omitting provided dead nodes
+func (n *Network) fastNetworkSolver(dead map[*NNode]bool) (Solver, error) {
+	if !n.hasRecurrentLinks() {
+		solver, err := n.feedForwardSolver(dead)
+		if err == nil {
+			return solver, nil
+		} else if err != NetErrNetworkHasCycles {
+			return nil, err
+		}
+	}
+	return n.fastModularSolver(dead)
+}
+
+// FastModularSolver Creates fast modular network solver based on the architecture of this network. This solver is able
+// to process any kind of network topology including recurrent networks.
+func (n *Network) FastModularSolver() (*FastModularNetworkSolver, error) {
+	return n.fastModularSolver(nil)
+}
+
+func (n *Network) fastModularSolver(dead map[*NNode]bool) (*FastModularNetworkSolver, error) {
+	layout, err := n.fastSolverLayout(dead)
+	if err != nil {
+		return nil, err
+	}
+	return NewFastModularNetworkSolver(layout.biasNeuronCount, layout.inputNeuronCount, layout.outputNeuronCount,
+		layout.totalNeuronCount, layout.activations, layout.connections, layout.biases, layout.modules), nil
+}
+
+// FeedForwardSolver Creates feed-forward network solver based on the architecture of this network. Returns
+// NetErrNetworkHasCycles error if this network has cycles and can not be evaluated in topological order.
+func (n *Network) FeedForwardSolver() (*FeedForwardNetworkSolver, error) {
+	return n.feedForwardSolver(nil)
+}
+
+func (n *Network) feedForwardSolver(dead map[*NNode]bool) (*FeedForwardNetworkSolver, error) {
+	layout, err := n.fastSolverLayout(dead)
+	if err != nil {
+		return nil, err
+	}
+	return NewFeedForwardNetworkSolver(layout.biasNeuronCount, layout.inputNeuronCount, layout.outputNeuronCount,
+		layout.totalNeuronCount, layout.activations, layout.connections, layout.biases, layout.modules)
+}
+
+// fastSolverLayout holds the arrays describing this network in the form used by the fast network solvers
+type fastSolverLayout struct {
+	biasNeuronCount   int
+	inputNeuronCount  int
+	outputNeuronCount int
+	totalNeuronCount  int
+	activations       []math.NodeActivationType
+	connections       []*FastNetworkLink
+	biases            []float64
+	modules           []*FastControlNode
+}
+
+// Builds arrays describing this network in the form used by the fast network solvers. The neurons are indexed in
+// the following order: bias, input, output, hidden. The provided dead nodes and all their links are omitted.
+func (n *Network) fastSolverLayout(dead map[*NNode]bool) (*fastSolverLayout, error) {
 	// calculate neurons per layer
 	outputNeuronCount := len(n.Outputs)
 	// build bias, input and hidden neurons lists
@@ -58,6 +122,9 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 	biasList := make([]*NNode, 0)
 	hiddenList := make([]*NNode, 0)
 	for _, ne := range n.allNodes {
+		if dead[ne] {
+			continue
+		}
 		switch ne.NeuronType {
 		case BiasNeuron:
 			biasNeuronCount += 1
@@ -69,7 +136,7 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 		}
 	}
 	inputNeuronCount := len(inList)
-	totalNeuronCount := len(n.allNodes)
+	totalNeuronCount := biasNeuronCount + inputNeuronCount + outputNeuronCount + len(hiddenList)
 
 	// create activation functions array
 	activations := make([]math.NodeActivationType, totalNeuronCount)
@@ -85,25 +152,28 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 	biases := make([]float64, totalNeuronCount)
 	connections := make([]*FastNetworkLink, 0)
 
-	if inConnects, err := processIncomingConnections(inList, biases, neuronLookup); err == nil {
+	if inConnects, err := processIncomingConnections(inList, biases, neuronLookup, dead); err == nil {
 		connections = append(connections, inConnects...)
 	} else {
 		return nil, err
 	}
-	if inConnects, err := processIncomingConnections(hiddenList, biases, neuronLookup); err == nil {
+	if inConnects, err := processIncomingConnections(hiddenList, biases, neuronLookup, dead); err == nil {
 		connections = append(connections, inConnects...)
 	} else {
 		return nil, err
 	}
-	if inConnects, err := processIncomingConnections(n.Outputs, biases, neuronLookup); err == nil {
+	if inConnects, err := processIncomingConnections(n.Outputs, biases, neuronLookup, dead); err == nil {
 		connections = append(connections, inConnects...)
 	} else {
 		return nil, err
 	}
 
 	// walk through control neurons
-	modules := make([]*FastControlNode, len(n.controlNodes))
-	for i, cn := range n.controlNodes {
+	modules := make([]*FastControlNode, 0, len(n.controlNodes))
+	for _, cn := range n.controlNodes {
+		if dead[cn] {
+			continue
+		}
 		// collect inputs
 		inputs := make([]int, len(cn.Incoming))
 		for j, in := range cn.Incoming {
@@ -125,11 +195,31 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 			}
 		}
 		// build control node
-		modules[i] = &FastControlNode{InputIndexes: inputs, OutputIndexes: outputs, ActivationType: cn.ActivationType}
+		modules = append(modules, &FastControlNode{InputIndexes: inputs, OutputIndexes: outputs, ActivationType: cn.ActivationType})
 	}
 
-	return NewFastModularNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuronCount, totalNeuronCount,
-		activations, connections, biases, modules), nil
+	return &fastSolverLayout{
+		biasNeuronCount:   biasNeuronCount,
+		inputNeuronCount:  inputNeuronCount,
+		outputNeuronCount: outputNeuronCount,
+		totalNeuronCount:  totalNeuronCount,
+		activations:       activations,
+		connections:       connections,
+		biases:            biases,
+		modules:           modules,
+	}, nil
+}
+
+// Returns true if any link of this network is marked as recurrent or time delayed
+func (n *Network) hasRecurrentLinks() bool {
+	for _, node := range n.allNodes {
+		for _, link := range node.Incoming {
+			if link.IsRecurrent || link.IsTimeDelayed {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func processList(startIndex int, nList []*NNode, activations []math.NodeActivationType, neuronLookup map[int]int) int {
@@ -141,11 +231,14 @@ func processList(startIndex int, nList []*NNode, activations []math.NodeActivati
 	return startIndex
 }
 
-func processIncomingConnections(nList []*NNode, biases []float64, neuronLookup map[int]int) (connections []*FastNetworkLink, err error) {
+func processIncomingConnections(nList []*NNode, biases []float64, neuronLookup map[int]int, dead map[*NNode]bool) (connections []*FastNetworkLink, err error) {
 	connections = make([]*FastNetworkLink, 0)
 	for _, ne := range nList {
 		if targetIndex, ok := neuronLookup[ne.Id]; ok {
 			for _, in := range ne.Incoming {
+				if dead[in.InNode] {
+					continue
+				}
 				if sourceIndex, ok := neuronLookup[in.InNode.Id]; ok {
 					if in.InNode.NeuronType == BiasNeuron {
 						// store bias for target neuron
@@ -223,8 +316,6 @@ func (n *Network) OutputIsOff() bool {
 
 // ActivateSteps Attempts to activate the network given number of steps before returning error.
 func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
-	// For adding to the active sum
-	addAmount := 0.0
 	// Make sure we at least activate once
 	oneTime := false
 	// Used in case the output is somehow truncated from the network
@@ -238,57 +329,89 @@ func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
 			return false, NetErrExceededMaxActivationAttempts
 		}
 
-		// For each neuron node, compute the sum of its incoming activation
-		for _, np := range n.allNodes {
-			if np.IsNeuron() {
-				np.ActivationSum = 0.0 // reset activation value
+		if _, _, err := n.forwardStep(-1); err != nil {
+			return false, err
+		}
 
-				// For each node's incoming connection, add the activity from the connection to the activesum
-				for _, link := range np.Incoming {
-					// Handle possible time delays
-					if !link.IsTimeDelayed {
-						addAmount = link.Weight * link.InNode.GetActiveOut()
-						if link.InNode.isActive || link.InNode.IsSensor() {
-							np.isActive = true
-						}
-					} else {
-						addAmount = link.Weight * link.InNode.GetActiveOutTd()
-					}
-					np.ActivationSum += addAmount
-				} // End {for} over incoming links
-			} // End if != SENSOR
-		} // End {for} over all nodes
-
-		// Now activate all the neuron nodes off their incoming activation
-		for _, np := range n.allNodes {
-			if np.IsNeuron() {
-				// Only activate if some active input came in
-				if np.isActive {
-					// Now run the net activation through an activation function
-					err := ActivateNode(np, math.NodeActivators)
-					if err != nil {
-						return false, err
+		oneTime = true
+		abortCount += 1
+	}
+	return true, nil
+}
+
+// Performs single activation step through the network. If maxAllowedSignalDelta is not negative, also tests if network
+// become relaxed, i.e. absolute value of the activation change of every neuron is not greater than maxAllowedSignalDelta.
+// Returns relaxation flag and the flag to indicate whether any node became active during this step.
+func (n *Network) forwardStep(maxAllowedSignalDelta float64) (isRelaxed, activated bool, err error) {
+	checkRelaxed := maxAllowedSignalDelta >= 0
+	var previous []float64
+	var wasActive []bool
+	if checkRelaxed {
+		previous = make([]float64, len(n.allNodes))
+		wasActive = make([]bool, len(n.allNodes))
+		for i, np := range n.allNodes {
+			previous[i] = np.Activation
+			wasActive[i] = np.isActive
+		}
+	}
+
+	// For each neuron node, compute the sum of its incoming activation
+	for _, np := range n.allNodes {
+		if np.IsNeuron() {
+			np.ActivationSum = 0.0 // reset activation value
+
+			// For each node's incoming connection, add the activity from the connection to the activesum
+			for _, link := range np.Incoming {
+				// Handle possible time delays
+				if !link.IsTimeDelayed {
+					np.ActivationSum += link.Weight * link.InNode.GetActiveOut()
+					if link.InNode.isActive || link.InNode.IsSensor() {
+						np.isActive = true
 					}
+				} else {
+					np.ActivationSum += link.Weight * link.InNode.GetActiveOutTd()
+				}
+			} // End {for} over incoming links
+		} // End if != SENSOR
+	} // End {for} over all nodes
+
+	// Now activate all the neuron nodes off their incoming activation
+	for _, np := range n.allNodes {
+		if np.IsNeuron() {
+			// Only activate if some active input came in
+			if np.isActive {
+				// Now run the net activation through an activation function
+				if err = ActivateNode(np, math.NodeActivators); err != nil {
+					return false, false, err
 				}
 			}
 		}
+	}
 
-		// Now activate all MIMO control genes to propagate activation through genome modules
-		for _, cn := range n.controlNodes {
-			cn.isActive = false
-			// Activate control MIMO node as control module
-			err := ActivateModule(cn, math.NodeActivators)
-			if err != nil {
-				return false, err
-			}
-			// mark control node as active
-			cn.isActive = true
+	// Now activate all MIMO control genes to propagate activation through genome modules
+	for _, cn := range n.controlNodes {
+		cn.isActive = false
+		// Activate control MIMO node as control module
+		if err = ActivateModule(cn, math.NodeActivators); err != nil {
+			return false, false, err
 		}
+		// mark control node as active
+		cn.isActive = true
+	}
 
-		oneTime = true
-		abortCount += 1
+	if !checkRelaxed {
+		return false, false, nil
 	}
-	return true, nil
+
+	// check whether any neuron in the network has changed by more than a small amount
+	isRelaxed = true
+	for i, np := range n.allNodes {
+		if np.IsNeuron() {
+			activated = activated || np.isActive != wasActive[i]
+			isRelaxed = isRelaxed && !(gomath.Abs(np.Activation-previous[i]) > maxAllowedSignalDelta)
+		}
+	}
+	return isRelaxed, activated, nil
 }
 
 // Activate Activates the net such that all outputs are active
@@ -296,23 +419,129 @@ func (n *Network) Activate() (bool, error) {
 	return n.ActivateSteps(20)
 }
 
+// ForwardSteps Propagates activation wave through all network nodes provided number of steps in forward direction.
+// The first step keeps activating network until all outputs become active. The propagation terminates early when
+// network reached steady state, i.e. when no neuron changed its activation during two consecutive steps, because any
+// further step will not change the network state anymore. Returns true if activation wave passed from all inputs to
+// the outputs.
 func (n *Network) ForwardSteps(steps int) (res bool, err error) {
-	for i := 0; i < steps; i++ {
-		res, err = n.Activate()
+	unchangedSteps := 0
+	for i := 0; i < steps && unchangedSteps < 2; i++ {
+		if n.OutputIsOff() {
+			if res, err = n.Activate(); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		relaxed, activated, err := n.forwardStep(0)
 		if err != nil {
-			// failure - no need to continue
-			break
+			return false, err
+		}
+		res = true
+		if relaxed && !activated {
+			unchangedSteps++
+		} else {
+			unchangedSteps = 0
 		}
 	}
-	return res, err
+	return res, nil
 }
 
+// RecursiveSteps Propagates activation wave through all network nodes by recursion from the output nodes, activating
+// each node exactly once after all its inputs. The links marked as recurrent or time delayed use the activation of
+// the source node from the previous activation. Returns true if activation wave passed from all inputs to the outputs.
+// Returns NetErrNetworkHasCycles if network has cycles formed by links not marked as recurrent.
 func (n *Network) RecursiveSteps() (bool, error) {
-	return false, errors.New("RecursiveSteps is not implemented")
+	if _, err := n.buildGraph().layers(); err != nil {
+		return false, err
+	}
+
+	// the control nodes of modules per module output node
+	moduleOf := make(map[*NNode]*NNode)
+	for _, cn := range n.controlNodes {
+		for _, link := range cn.Outgoing {
+			moduleOf[link.OutNode] = cn
+		}
+	}
+
+	activated := make(map[*NNode]bool)
+	var activate func(node *NNode) error
+	activate = func(node *NNode) error {
+		if node.IsSensor() || activated[node] {
+			return nil
+		}
+		activated[node] = true
+
+		if cn, ok := moduleOf[node]; ok {
+			// the node value is set by the module
+			for _, link := range cn.Incoming {
+				if err := activate(link.InNode); err != nil {
+					return err
+				}
+			}
+			for _, link := range cn.Outgoing {
+				activated[link.OutNode] = true
+			}
+			cn.isActive = false
+			if err := ActivateModule(cn, math.NodeActivators); err != nil {
+				return err
+			}
+			cn.isActive = true
+			return nil
+		}
+
+		node.ActivationSum = 0.0
+		for _, link := range node.Incoming {
+			if link.IsTimeDelayed {
+				node.ActivationSum += link.Weight * link.InNode.GetActiveOutTd()
+				continue
+			}
+			if !link.IsRecurrent {
+				if err := activate(link.InNode); err != nil {
+					return err
+				}
+			}
+			node.ActivationSum += link.Weight * link.InNode.GetActiveOut()
+			if link.InNode.isActive || link.InNode.IsSensor() {
+				node.isActive = true
+			}
+		}
+		// Only activate if some active input came in
+		if node.isActive {
+			return ActivateNode(node, math.NodeActivators)
+		}
+		return nil
+	}
+
+	for _, out := range n.Outputs {
+		if err := activate(out); err != nil {
+			return false, err
+		}
+	}
+	return !n.OutputIsOff(), nil
 }
 
-func (n *Network) Relax(_ int, _ float64) (bool, error) {
-	return false, errors.New("relax is not implemented")
+// Relax Attempts to relax network given amount of steps until giving up. The network considered relaxed when absolute
+// value of the activation change of every neuron is not greater than maxAllowedSignalDelta during activation waves
+// propagation. If maxAllowedSignalDelta value is less than or equal to 0, the method will return true after single
+// step without checking for relaxation.
+func (n *Network) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	for i := 0; i < maxSteps; i++ {
+		if maxAllowedSignalDelta <= 0 {
+			// no need to check for relaxation
+			_, _, err = n.forwardStep(-1)
+			relaxed = true
+		} else {
+			relaxed, _, err = n.forwardStep(maxAllowedSignalDelta)
+		}
+		if err != nil {
+			return false, err
+		} else if relaxed {
+			break // no need to iterate any further, already reached desired accuracy
+		}
+	}
+	return relaxed, nil
 }
 
 func (n *Network) LoadSensors(sensors []float64) error {
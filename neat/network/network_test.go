@@ -220,3 +220,110 @@ func TestNetwork_FastNetworkSolver(t *testing.T) {
 	assert.Equal(t, net.NodeCount(), solver.NodeCount(), "wrong number of nodes")
 	assert.Equal(t, net.LinkCount(), solver.LinkCount(), "wrong number of links")
 }
+
+func TestNetwork_ForwardSteps(t *testing.T) {
+	net := buildModularNetwork()
+	err := net.LoadSensors([]float64{1.0, 2.0, 0.5})
+	require.NoError(t, err, "failed to load sensors")
+
+	res, err := net.ForwardSteps(5)
+	require.NoError(t, err, "error when do forward steps")
+	require.True(t, res, "failed to do forward steps")
+	assert.Equal(t, []float64{945.0, 2730.0}, net.ReadOutputs())
+
+	// zero steps should not activate
+	res, err = net.ForwardSteps(0)
+	require.NoError(t, err)
+	assert.False(t, res)
+
+	// the steady state network should terminate early
+	net = buildNetwork()
+	data := []float64{1.0, 2.0, 1.0}
+	err = net.LoadSensors(data)
+	require.NoError(t, err, "failed to load sensors")
+	steps := 100
+	res, err = net.ForwardSteps(steps)
+	require.NoError(t, err, "error when do forward steps")
+	require.True(t, res, "failed to do forward steps")
+	assert.True(t, net.Outputs[0].ActivationsCount < int32(steps), "early termination expected")
+
+	// compare with fast solver
+	fmm, err := net.FastModularSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	err = fmm.LoadSensors(data[:2])
+	require.NoError(t, err, "failed to load sensors")
+	_, err = fmm.ForwardSteps(steps)
+	require.NoError(t, err)
+	for i, out := range fmm.ReadOutputs() {
+		assert.InDelta(t, out, net.Outputs[i].Activation, 1e-9, "wrong activation at: %d", i)
+	}
+}
+
+func TestNetwork_RecursiveSteps(t *testing.T) {
+	for _, net := range []*Network{buildNetwork(), buildModularNetwork()} {
+		data := []float64{0.5, 1.1}
+		ffs, err := net.FeedForwardSolver()
+		require.NoError(t, err, "failed to create feed-forward solver")
+		err = ffs.LoadSensors(data)
+		require.NoError(t, err, "failed to load sensors")
+		res, err := ffs.RecursiveSteps()
+		require.NoError(t, err)
+		require.True(t, res)
+
+		err = net.LoadSensors(append(data, 1.0)) // BIAS is a third object
+		require.NoError(t, err, "failed to load sensors")
+		res, err = net.RecursiveSteps()
+		require.NoError(t, err, "failed to do recursive steps")
+		require.True(t, res, "recursive activation failed")
+
+		for i, out := range ffs.ReadOutputs() {
+			assert.InDelta(t, out, net.Outputs[i].Activation, 1e-9, "wrong activation at: %d", i)
+		}
+		// each neuron activated exactly once
+		for _, node := range net.AllNodes() {
+			if node.IsNeuron() {
+				assert.EqualValues(t, 1, node.ActivationsCount, "wrong activations count at: %d", node.Id)
+			}
+		}
+	}
+
+	// network with cycle
+	net := buildNetwork()
+	nodes := net.AllNodes()
+	nodes[4].addIncoming(nodes[5], 1.0)
+	_, err := net.RecursiveSteps()
+	assert.EqualError(t, err, NetErrNetworkHasCycles.Error())
+}
+
+func TestNetwork_Relax(t *testing.T) {
+	net := buildModularNetwork()
+	data := []float64{1.5, 2.0}
+	fmm, err := net.FastModularSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	err = fmm.LoadSensors(data)
+	require.NoError(t, err, "failed to load sensors")
+
+	err = net.LoadSensors(append(data, 1.0))
+	require.NoError(t, err, "failed to load sensors")
+
+	// not enough steps to relax
+	steps, delta := 2, 0.001
+	res, err := net.Relax(steps, delta)
+	require.NoError(t, err)
+	assert.False(t, res, "network should not relax in %d steps", steps)
+	res, err = fmm.Relax(steps, delta)
+	require.NoError(t, err)
+	assert.False(t, res, "fast solver should not relax in %d steps", steps)
+
+	steps = 10
+	res, err = net.Relax(steps, delta)
+	require.NoError(t, err)
+	assert.True(t, res, "failed to relax network")
+	res, err = fmm.Relax(steps, delta)
+	require.NoError(t, err)
+	assert.True(t, res, "failed to relax fast solver")
+
+	for i, out := range fmm.ReadOutputs() {
+		assert.Equal(t, out, net.Outputs[i].Activation, "wrong activation at: %d", i)
+	}
+}
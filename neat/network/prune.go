@@ -0,0 +1,211 @@
+package network
+
+import (
+	"fmt"
+)
+
+// PruningReport holds the information about dead structure removed from the network, i.e. hidden nodes which can not
+// influence network outputs.
+type PruningReport struct {
+	// The IDs of removed hidden nodes which have no path to any output node
+	NoPathToOutputs []int
+	// The IDs of removed hidden nodes which have no path from any input (or bias) node
+	NoPathFromInputs []int
+	// The IDs of removed control nodes of network modules
+	RemovedModules []int
+	// The removed links
+	RemovedLinks []*Link
+}
+
+// RemovedNodesCount Returns the total number of removed nodes including control nodes of network modules
+func (r *PruningReport) RemovedNodesCount() int {
+	return len(r.NoPathToOutputs) + len(r.NoPathFromInputs) + len(r.RemovedModules)
+}
+
+// IsEmpty Returns true if nothing was removed
+func (r *PruningReport) IsEmpty() bool {
+	return r.RemovedNodesCount() == 0 && len(r.RemovedLinks) == 0
+}
+
+func (r *PruningReport) String() string {
+	return fmt.Sprintf("pruned nodes: %d, no path to outputs: %v, no path from inputs: %v, modules: %v, links: %d",
+		r.RemovedNodesCount(), r.NoPathToOutputs, r.NoPathFromInputs, r.RemovedModules, len(r.RemovedLinks))
+}
+
+// Prune Removes dead structure from this network in place: the hidden nodes which can not reach any output and
+// the hidden nodes which have no path from any input or bias node, along with all their links. The sensors and outputs
+// are never removed. The network modules are removed as whole, i.e. if module is alive all its input and output nodes
+// are preserved. Returns report about removed nodes and links.
+//
+// Note that the nodes without path from inputs never get activated by the standard network solver, thus removing them
+// doesn't change the outputs of this network. The fast solvers activate such nodes with zero signal, thus use
+// PrunedFastNetworkSolver to get the fast solver of pruned network with unchanged outputs.
+func (n *Network) Prune() *PruningReport {
+	dead, report := n.findDeadStructure(false)
+	if report.IsEmpty() {
+		return report
+	}
+
+	aliveNodes := make([]*NNode, 0, len(n.allNodes))
+	for _, node := range n.allNodes {
+		if !dead[node] {
+			node.Incoming = filterAliveLinks(node.Incoming, dead)
+			node.Outgoing = filterAliveLinks(node.Outgoing, dead)
+			aliveNodes = append(aliveNodes, node)
+		}
+	}
+	n.allNodes = aliveNodes
+
+	if len(n.controlNodes) > 0 {
+		aliveControlNodes := make([]*NNode, 0, len(n.controlNodes))
+		for _, node := range n.controlNodes {
+			if !dead[node] {
+				aliveControlNodes = append(aliveControlNodes, node)
+			}
+		}
+		n.controlNodes = aliveControlNodes
+	}
+	n.numLinks = -1
+
+	return report
+}
+
+// PrunedFastNetworkSolver Creates fast network solver the same way as FastNetworkSolver, but omitting the hidden nodes
+// which can not reach any output along with their links. This network stays unchanged. Returns created solver and
+// report about nodes and links omitted.
+//
+// Unlike Prune, the nodes without path from inputs are kept, because the fast solvers apply activation function to
+// the zero signal of such nodes, which may produce constant non-zero contribution to the outputs. Thus, the pruned
+// solver produces the same outputs as the solver created by FastNetworkSolver.
+func (n *Network) PrunedFastNetworkSolver() (Solver, *PruningReport, error) {
+	dead, report := n.findDeadStructure(true)
+	solver, err := n.fastNetworkSolver(dead)
+	if err != nil {
+		return nil, nil, err
+	}
+	return solver, report, nil
+}
+
+// findDeadStructure Finds the dead nodes of this network. If keepUnreachable is true, the nodes without path from inputs
+// are considered alive as long as they can reach outputs. Returns the set of dead nodes (including control nodes) and
+// the report describing them.
+func (n *Network) findDeadStructure(keepUnreachable bool) (map[*NNode]bool, *PruningReport) {
+	// build adjacency lists including the links through the control nodes
+	forward := make(map[*NNode][]*NNode)
+	backward := make(map[*NNode][]*NNode)
+	addEdge := func(from, to *NNode) {
+		forward[from] = append(forward[from], to)
+		backward[to] = append(backward[to], from)
+	}
+	for _, node := range n.allNodes {
+		for _, link := range node.Incoming {
+			addEdge(link.InNode, node)
+		}
+	}
+	for _, cn := range n.controlNodes {
+		for _, link := range cn.Incoming {
+			addEdge(link.InNode, cn)
+		}
+		for _, link := range cn.Outgoing {
+			addEdge(cn, link.OutNode)
+		}
+	}
+
+	sensors := make([]*NNode, 0)
+	for _, node := range n.allNodes {
+		if node.IsSensor() {
+			sensors = append(sensors, node)
+		}
+	}
+	fromInputs := traverseNodes(sensors, forward)
+	toOutputs := traverseNodes(n.Outputs, backward)
+
+	alive := func(node *NNode) bool {
+		return node.IsSensor() || node.NeuronType == OutputNeuron || (keepUnreachable || fromInputs[node]) && toOutputs[node]
+	}
+
+	// the modules are kept as whole to preserve arity of their activation functions
+	dead := make(map[*NNode]bool)
+	keep := make(map[*NNode]bool)
+	for _, cn := range n.controlNodes {
+		if alive(cn) {
+			for _, link := range cn.Incoming {
+				keep[link.InNode] = true
+			}
+			for _, link := range cn.Outgoing {
+				keep[link.OutNode] = true
+			}
+		} else {
+			dead[cn] = true
+		}
+	}
+
+	report := &PruningReport{
+		NoPathToOutputs:  make([]int, 0),
+		NoPathFromInputs: make([]int, 0),
+		RemovedModules:   make([]int, 0),
+		RemovedLinks:     make([]*Link, 0),
+	}
+	for _, node := range n.allNodes {
+		if alive(node) || keep[node] {
+			continue
+		}
+		dead[node] = true
+		if !toOutputs[node] {
+			report.NoPathToOutputs = append(report.NoPathToOutputs, node.Id)
+		} else {
+			report.NoPathFromInputs = append(report.NoPathFromInputs, node.Id)
+		}
+	}
+
+	// collect removed links
+	for _, node := range n.allNodes {
+		for _, link := range node.Incoming {
+			if dead[node] || dead[link.InNode] {
+				report.RemovedLinks = append(report.RemovedLinks, link)
+			}
+		}
+	}
+	for _, cn := range n.controlNodes {
+		if dead[cn] {
+			report.RemovedModules = append(report.RemovedModules, cn.Id)
+			report.RemovedLinks = append(report.RemovedLinks, cn.Incoming...)
+			report.RemovedLinks = append(report.RemovedLinks, cn.Outgoing...)
+		}
+	}
+	return dead, report
+}
+
+// traverseNodes Returns the set of nodes reachable from the start nodes following provided adjacency lists
+func traverseNodes(start []*NNode, adjacency map[*NNode][]*NNode) map[*NNode]bool {
+	visited := make(map[*NNode]bool)
+	queue := make([]*NNode, 0, len(start))
+	for _, node := range start {
+		if !visited[node] {
+			visited[node] = true
+			queue = append(queue, node)
+		}
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[node] {
+			if !visited[next] {
+				visited[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return visited
+}
+
+// filterAliveLinks Returns links which are not connected to any of the dead nodes
+func filterAliveLinks(links []*Link, dead map[*NNode]bool) []*Link {
+	res := make([]*Link, 0, len(links))
+	for _, link := range links {
+		if !dead[link.InNode] && !dead[link.OutNode] {
+			res = append(res, link)
+		}
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// buildNetworkWithDeadNodes Creates the network from buildNetwork with extra dead structure: the hidden node 9 which
+// has no path to outputs and the hidden node 10 which has no path from inputs.
+func buildNetworkWithDeadNodes() *Network {
+	net := buildNetwork()
+	allNodes := net.AllNodes()
+
+	// HIDDEN 9 - no path to outputs
+	deadEnd := NewNNode(9, HiddenNeuron)
+	deadEnd.addIncoming(allNodes[0], 3.0)
+	deadEnd.addIncoming(allNodes[3], 2.0)
+	// HIDDEN 10 - no path from inputs
+	orphan := NewNNode(10, HiddenNeuron)
+	allNodes[6].addIncoming(orphan, 1.5)
+
+	allNodes = append(allNodes, deadEnd, orphan)
+	return NewNetwork(allNodes[0:3], allNodes[6:8], allNodes, 0)
+}
+
+func TestNetwork_Prune(t *testing.T) {
+	net := buildNetworkWithDeadNodes()
+	nodesCount, linksCount := net.NodeCount(), net.LinkCount()
+
+	report := net.Prune()
+	require.NotNil(t, report)
+	assert.False(t, report.IsEmpty())
+	assert.Equal(t, []int{9}, report.NoPathToOutputs)
+	assert.Equal(t, []int{10}, report.NoPathFromInputs)
+	assert.Len(t, report.RemovedModules, 0)
+	assert.Equal(t, 2, report.RemovedNodesCount())
+	assert.Len(t, report.RemovedLinks, 3)
+
+	assert.Equal(t, nodesCount-2, net.NodeCount())
+	assert.Equal(t, linksCount-3, net.LinkCount())
+
+	// check that outputs are the same as for original network
+	reference := buildNetwork()
+	data := []float64{0.5, 1.1, 1.0}
+	err := reference.LoadSensors(data)
+	require.NoError(t, err)
+	err = net.LoadSensors(data)
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		_, err = reference.Activate()
+		require.NoError(t, err)
+		_, err = net.Activate()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, reference.ReadOutputs(), net.ReadOutputs())
+
+	// nothing to remove from already pruned network
+	report = net.Prune()
+	assert.True(t, report.IsEmpty())
+}
+
+func TestNetwork_Prune_Modular(t *testing.T) {
+	net := buildModularNetwork()
+	report := net.Prune()
+	assert.True(t, report.IsEmpty(), "nothing to prune expected: %s", report)
+
+	// disconnect module outputs from network outputs
+	net = buildModularNetwork()
+	for _, out := range net.Outputs {
+		out.Incoming = nil
+	}
+	nodesCount := net.NodeCount()
+	report = net.Prune()
+	assert.Equal(t, []int{6}, report.RemovedModules)
+	assert.ElementsMatch(t, []int{4, 5, 7}, report.NoPathToOutputs)
+	assert.Equal(t, nodesCount-4, net.NodeCount())
+	assert.Equal(t, 0, net.LinkCount())
+}
+
+func TestNetwork_PrunedFastNetworkSolver(t *testing.T) {
+	net := buildNetworkWithDeadNodes()
+	nodesCount, linksCount := net.NodeCount(), net.LinkCount()
+
+	solver, report, err := net.PrunedFastNetworkSolver()
+	require.NoError(t, err, "failed to create pruned solver")
+	assert.IsType(t, &FeedForwardNetworkSolver{}, solver)
+	// the node without path from inputs is kept, because it contributes activation of zero signal to the outputs
+	assert.Equal(t, []int{9}, report.NoPathToOutputs)
+	assert.Empty(t, report.NoPathFromInputs)
+	assert.Equal(t, nodesCount-1, solver.NodeCount())
+	assert.Equal(t, linksCount-2, solver.LinkCount())
+
+	// the network itself must stay unchanged
+	assert.Equal(t, nodesCount, net.NodeCount())
+	assert.Equal(t, linksCount, net.LinkCount())
+
+	// check that outputs are the same as for the fast solver of the whole network
+	reference, err := buildNetworkWithDeadNodes().FastNetworkSolver()
+	require.NoError(t, err)
+	data := []float64{0.5, 1.1}
+	require.NoError(t, reference.LoadSensors(data))
+	require.NoError(t, solver.LoadSensors(data))
+	_, err = reference.RecursiveSteps()
+	require.NoError(t, err)
+	_, err = solver.RecursiveSteps()
+	require.NoError(t, err)
+	assert.Equal(t, reference.ReadOutputs(), solver.ReadOutputs())
+}
@@ -29,3 +29,20 @@ type Solver interface {
 	// LinkCount Returns the total number of links between nodes in the network
 	LinkCount() int
 }
+
+// BatchSolver defines network solver able to evaluate batches of input samples at once. The batch evaluation uses its
+// own state storage and does not affect the current state of the solver.
+type BatchSolver interface {
+	Solver
+
+	// ActivateBatch Evaluates the batch of independent input samples provided as matrix with one row per sample.
+	// Each sample is evaluated from the flushed network state by propagating activation wave the given number of
+	// steps in forward direction. Returns matrix of outputs with one row per sample.
+	ActivateBatch(inputs [][]float64, steps int) ([][]float64, error)
+
+	// ActivateSequences Evaluates independent sequences of input samples in lockstep, i.e. the sequence[i][t] is the
+	// input of sequence i at time step t. The network state is kept separately for each sequence, starting from
+	// the flushed state, and the activation wave is propagated the given number of steps per each time step.
+	// Returns outputs in the same layout as inputs, i.e. outputs[i][t] is the output of sequence i at time step t.
+	ActivateSequences(sequences [][][]float64, steps int) ([][][]float64, error)
+}
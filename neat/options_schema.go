@@ -0,0 +1,422 @@
+package neat
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v2/neat/math"
+	"io"
+	gomath "math"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OptionType defines the type of value of the NEAT option
+type OptionType string
+
+const (
+	OptionTypeFloat  OptionType = "float"
+	OptionTypeInt    OptionType = "int"
+	OptionTypeString OptionType = "string"
+	OptionTypeList   OptionType = "list"
+)
+
+// OptionSchema The description of the NEAT option shared by all configuration formats
+type OptionSchema struct {
+	// The name of the option in the configuration files
+	Name string
+	// The type of the option value
+	Type OptionType
+	// The human readable description of the option
+	Description string
+	// The default value of the option encoded as in the plain text configuration
+	Default string
+	// The minimal allowed value of the numeric option
+	Min float64
+	// The maximal allowed value of the numeric option
+	Max float64
+	// The list of allowed values of the string option, empty if any value allowed
+	Allowed []string
+
+	// the index of the corresponding Options field
+	field int
+}
+
+// check Checks that provided value of this option is within allowed range. Returns the description of violation
+// or empty string if value is valid.
+func (s *OptionSchema) check(value reflect.Value) string {
+	switch s.Type {
+	case OptionTypeFloat, OptionTypeInt:
+		var v float64
+		if s.Type == OptionTypeFloat {
+			v = value.Float()
+		} else {
+			v = float64(value.Int())
+		}
+		if gomath.IsNaN(v) || v < s.Min || v > s.Max {
+			return fmt.Sprintf("%s: value %v is out of range %s", s.Name, value.Interface(), s.rangeString())
+		}
+	case OptionTypeString:
+		if len(s.Allowed) == 0 {
+			return ""
+		}
+		str := value.String()
+		for _, allowed := range s.Allowed {
+			if str == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%s: unsupported value [%s], allowed values: %s", s.Name, str, strings.Join(s.Allowed, ", "))
+	}
+	return ""
+}
+
+// rangeString Returns the string representation of the allowed values range
+func (s *OptionSchema) rangeString() string {
+	if gomath.IsInf(s.Max, 1) {
+		return fmt.Sprintf("[%v, +Inf)", s.Min)
+	}
+	return fmt.Sprintf("[%v, %v]", s.Min, s.Max)
+}
+
+// ValidationError The error holding all violations found during NEAT options validation
+type ValidationError struct {
+	// The list of found violations
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid NEAT options, %d violation(s) found: %s", len(e.Violations), strings.Join(e.Violations, "; "))
+}
+
+var (
+	unbounded = gomath.Inf(1)
+
+	// optionsSchema the schema of all NEAT options in order of their appearance in the configuration files
+	optionsSchema = []*OptionSchema{
+		probability("trait_param_mut_prob", "0.5", "Probability of mutating a single trait param"),
+		positive("trait_mutation_power", "1.0", "Power of mutation on a single trait param"),
+		positive("weight_mut_power", "2.5", "The power of a link weight mutation"),
+		positive("disjoint_coeff", "1.0", "The importance of disjoint genes in the genomes compatibility"),
+		positive("excess_coeff", "1.0", "The importance of excess genes in the genomes compatibility"),
+		positive("mutdiff_coeff", "0.4", "The importance of parametric difference between genes in the genomes compatibility"),
+		positive("compat_threshold", "3.0", "The compatibility threshold under which two genomes are considered the same species"),
+		positive("age_significance", "1.0", "How much does age matter, if it is 1, then young species get no fitness boost"),
+		probability("survival_thresh", "0.2", "Percent of average fitness for survival, how many get to reproduce based on survival_thresh * pop_size"),
+		probability("mutate_only_prob", "0.25", "Probability of a non-mating reproduction"),
+		probability("mutate_random_trait_prob", "0.1", "Probability of a random trait mutation"),
+		probability("mutate_link_trait_prob", "0.1", "Probability of a link trait mutation"),
+		probability("mutate_node_trait_prob", "0.1", "Probability of a node trait mutation"),
+		probability("mutate_link_weights_prob", "0.9", "Probability of a link weights mutation"),
+		probability("mutate_toggle_enable_prob", "0.0", "Probability of toggling enable status of a gene"),
+		probability("mutate_gene_reenable_prob", "0.0", "Probability of re-enabling of a disabled gene"),
+		probability("mutate_add_node_prob", "0.03", "Probability of adding a new node"),
+		probability("mutate_add_link_prob", "0.08", "Probability of adding a new link"),
+		probability("mutate_connect_sensors", "0.5", "Probability of mutation involving disconnected inputs connection"),
+		probability("interspecies_mate_rate", "0.001", "Probability of a mate being outside species"),
+		probability("mate_multipoint_prob", "0.3", "Probability of a multipoint mating"),
+		probability("mate_multipoint_avg_prob", "0.3", "Probability of a multipoint mating with averaging of genes"),
+		probability("mate_singlepoint_prob", "0.3", "Probability of a single point mating"),
+		probability("mate_only_prob", "0.2", "Probability of mating without mutation"),
+		probability("recur_only_prob", "0.0", "Probability of forcing selection of ONLY links that are naturally recurrent"),
+		count("pop_size", "200", 1, "Size of population"),
+		count("dropoff_age", "50", 0, "Age when Species starts to be penalized"),
+		count("newlink_tries", "50", 0, "Number of tries mutate_add_link will attempt to find an open link"),
+		count("print_every", "10", 0, "Tells to print population to file every n generations"),
+		count("babies_stolen", "0", 0, "The number of babies to stolen off to the champions"),
+		count("num_runs", "100", 1, "The number of runs to average over in an experiment"),
+		count("num_generations", "100", 1, "The number of epochs (generations) to execute training"),
+		{Name: "log_level", Type: OptionTypeString, Default: "info", Description: "The log output details level",
+			Allowed: []string{"debug", "info", "warn", "error"}},
+		{Name: "epoch_executor", Type: OptionTypeString, Default: string(EpochExecutorTypeSequential),
+			Description: "The epoch's executor type to apply",
+			Allowed:     []string{string(EpochExecutorTypeSequential), string(EpochExecutorTypeParallel)}},
+		{Name: "genome_compat_method", Type: OptionTypeString, Default: string(GenomeCompatibilityMethodLinear),
+			Description: "The genome compatibility testing method to use, the fast method is best for bigger genomes",
+			Allowed:     []string{string(GenomeCompatibilityMethodLinear), string(GenomeCompatibilityMethodFast)}},
+		{Name: "node_activators", Type: OptionTypeList, Default: "",
+			Description: "The comma separated list of node activation functions to choose from with probability of selection of each one"},
+	}
+
+	// optionsSchemaByName the options schema indexed by the option name
+	optionsSchemaByName = make(map[string]*OptionSchema, len(optionsSchema))
+)
+
+func init() {
+	optsType := reflect.TypeOf(Options{})
+	fields := make(map[string]int, optsType.NumField())
+	for i := 0; i < optsType.NumField(); i++ {
+		if name := strings.Split(optsType.Field(i).Tag.Get("yaml"), ",")[0]; name != "" && name != "-" {
+			fields[name] = i
+		}
+	}
+	for _, s := range optionsSchema {
+		index, ok := fields[s.Name]
+		if !ok {
+			panic(fmt.Sprintf("NEAT options field not found for option: %s", s.Name))
+		}
+		s.field = index
+		optionsSchemaByName[s.Name] = s
+	}
+	if len(fields) != len(optionsSchema) {
+		panic("the NEAT options schema doesn't describe all options fields")
+	}
+}
+
+func probability(name, def, description string) *OptionSchema {
+	return &OptionSchema{Name: name, Type: OptionTypeFloat, Default: def, Min: 0, Max: 1, Description: description}
+}
+
+func positive(name, def, description string) *OptionSchema {
+	return &OptionSchema{Name: name, Type: OptionTypeFloat, Default: def, Min: 0, Max: unbounded, Description: description}
+}
+
+func count(name, def string, min float64, description string) *OptionSchema {
+	return &OptionSchema{Name: name, Type: OptionTypeInt, Default: def, Min: min, Max: unbounded, Description: description}
+}
+
+// OptionsSchema Returns the schema of all NEAT options in order of their appearance in the configuration files
+func OptionsSchema() []OptionSchema {
+	schema := make([]OptionSchema, len(optionsSchema))
+	for i, s := range optionsSchema {
+		schema[i] = *s
+	}
+	return schema
+}
+
+// LookupOption Returns the schema of the NEAT option with given name
+func LookupOption(name string) (OptionSchema, bool) {
+	if s, ok := optionsSchemaByName[name]; ok {
+		return *s, true
+	}
+	return OptionSchema{}, false
+}
+
+// DefaultOptions Returns the NEAT options with default values of all parameters
+func DefaultOptions() *Options {
+	opts := &Options{}
+	for _, s := range optionsSchema {
+		if err := opts.Set(s.Name, s.Default); err != nil {
+			panic(err)
+		}
+	}
+	return opts
+}
+
+// Set Sets the value of the NEAT option with given name. The value is encoded as in the plain text configuration,
+// i.e., the node activators are encoded as comma separated list, e.g.: "SigmoidBipolarActivation 0.25, SineActivation 0.75".
+// The values are not checked against allowed ranges, use Validate after all values are set.
+func (c *Options) Set(name, value string) error {
+	s, ok := optionsSchemaByName[name]
+	if !ok {
+		return errors.Errorf("unknown NEAT option: [%s]", name)
+	}
+	value = strings.TrimSpace(value)
+	field := reflect.ValueOf(c).Elem().Field(s.field)
+	switch s.Type {
+	case OptionTypeFloat:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value of option: [%s]", name)
+		}
+		field.SetFloat(v)
+	case OptionTypeInt:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid value of option: [%s]", name)
+		}
+		field.SetInt(int64(v))
+	case OptionTypeString:
+		field.SetString(value)
+	case OptionTypeList:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.Join(strings.Fields(item), " "); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+		if err := c.initNodeActivators(); err != nil {
+			return errors.Wrapf(err, "invalid value of option: [%s]", name)
+		}
+	}
+	return nil
+}
+
+// OptionValueString Returns the value of NEAT option decoded from YAML or JSON encoded as in the plain text
+// configuration, which can be passed to Options.Set. The list values are joined with commas.
+func OptionValueString(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = OptionValueString(item)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Get Returns the value of the NEAT option with given name encoded as in the plain text configuration
+func (c *Options) Get(name string) (string, error) {
+	s, ok := optionsSchemaByName[name]
+	if !ok {
+		return "", errors.Errorf("unknown NEAT option: [%s]", name)
+	}
+	field := reflect.ValueOf(c).Elem().Field(s.field)
+	switch s.Type {
+	case OptionTypeFloat:
+		return strconv.FormatFloat(field.Float(), 'g', -1, 64), nil
+	case OptionTypeList:
+		return strings.Join(c.NodeActivatorsWithProbs, ", "), nil
+	default:
+		return fmt.Sprint(field.Interface()), nil
+	}
+}
+
+// SetFromEnvironment Overrides the NEAT options with values of environment variables. The name of environment variable
+// is the upper cased name of the option with given prefix, e.g., NEAT_POP_SIZE for pop_size with NEAT_ prefix.
+func (c *Options) SetFromEnvironment(prefix string) error {
+	for _, s := range optionsSchema {
+		if value, ok := os.LookupEnv(prefix + strings.ToUpper(s.Name)); ok {
+			if err := c.Set(s.Name, value); err != nil {
+				return errors.Wrap(err, "failed to set option from environment")
+			}
+		}
+	}
+	return nil
+}
+
+// SetFromFlags Overrides the NEAT options with values provided as list of key=value pairs, e.g., pop_size=150
+func (c *Options) SetFromFlags(flags []string) error {
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("option override must be in key=value format: [%s]", flag)
+		}
+		if err := c.Set(strings.TrimSpace(parts[0]), parts[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate Checks that all options have valid values. Returns *ValidationError with all found violations if any.
+func (c *Options) Validate() error {
+	var violations []string
+	value := reflect.ValueOf(c).Elem()
+	for _, s := range optionsSchema {
+		if violation := s.check(value.Field(s.field)); violation != "" {
+			violations = append(violations, violation)
+		}
+	}
+	violations = append(violations, c.checkNodeActivators()...)
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// checkNodeActivators Returns violations found in the node activators and their probabilities
+func (c *Options) checkNodeActivators() []string {
+	var violations []string
+	if len(c.NodeActivators) == 0 || len(c.NodeActivators) != len(c.NodeActivatorsProb) {
+		violations = append(violations, fmt.Sprintf("node_activators: %d activators with %d probabilities found",
+			len(c.NodeActivators), len(c.NodeActivatorsProb)))
+	}
+	for i, prob := range c.NodeActivatorsProb {
+		if gomath.IsNaN(prob) || prob < 0 || prob > 1 {
+			violations = append(violations, fmt.Sprintf("node_activators: probability %v of activator %d is out of range [0, 1]", prob, i))
+		}
+	}
+	return violations
+}
+
+// Write Writes this options into provided writer in the plain text format (.neat), which can be loaded with
+// LoadNeatOptions. The node activators are written only if defined.
+func (c *Options) Write(w io.Writer) error {
+	for _, s := range optionsSchema {
+		value, err := c.Get(s.Name)
+		if err != nil {
+			return err
+		}
+		if s.Type == OptionTypeList && value == "" {
+			continue
+		}
+		if _, err = fmt.Fprintf(w, "%s %s\n", s.Name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadNeatOptions Loads NEAT options configuration from provided reader encode in plain text format (.neat). Each
+// line holds the name of option followed by its value, the empty lines and lines started with # are ignored. The
+// options missed in the configuration get default values.
+func LoadNeatOptions(r io.Reader) (*Options, error) {
+	c := DefaultOptions()
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, param := line, ""
+		if i := strings.IndexAny(line, " \t"); i > 0 {
+			name, param = line[:i], strings.TrimSpace(line[i:])
+		}
+		if _, ok := optionsSchemaByName[name]; !ok {
+			return nil, errors.Errorf("unknown configuration parameter found: %s = %s", name, param)
+		}
+		if err := c.Set(name, param); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// initialize logger
+	if err := InitLogger(c.LogLevel); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize logger")
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Clone Returns the deep copy of this options
+func (c *Options) Clone() *Options {
+	clone := *c
+	clone.NodeActivators = append([]math.NodeActivationType(nil), c.NodeActivators...)
+	clone.NodeActivatorsProb = append([]float64(nil), c.NodeActivatorsProb...)
+	clone.NodeActivatorsWithProbs = append([]string(nil), c.NodeActivatorsWithProbs...)
+	return &clone
+}
+
+// OptionsOverrides The list of NEAT options overrides in key=value format, which can be used as command line flag
+// value, e.g., -set pop_size=150 -set log_level=warn
+type OptionsOverrides []string
+
+func (o *OptionsOverrides) String() string {
+	return strings.Join(*o, " ")
+}
+
+// Set Appends the override to the list. Implements flag.Value interface.
+func (o *OptionsOverrides) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("option override must be in key=value format: [%s]", value)
+	}
+	if _, ok := optionsSchemaByName[strings.TrimSpace(parts[0])]; !ok {
+		return errors.Errorf("unknown NEAT option: [%s]", parts[0])
+	}
+	*o = append(*o, value)
+	return nil
+}
@@ -0,0 +1,144 @@
+package neat
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v2/neat/math"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOptionsSchema(t *testing.T) {
+	schema := OptionsSchema()
+	require.Len(t, schema, 36)
+	for _, s := range schema {
+		assert.NotEmpty(t, s.Description, "description expected for: %s", s.Name)
+		found, ok := LookupOption(s.Name)
+		assert.True(t, ok)
+		assert.Equal(t, s.Name, found.Name)
+	}
+	_, ok := LookupOption("unknown")
+	assert.False(t, ok)
+}
+
+func TestDefaultOptions(t *testing.T) {
+	opts := DefaultOptions()
+	require.NoError(t, opts.Validate())
+	assert.Equal(t, 200, opts.PopSize)
+	assert.Equal(t, 3.0, opts.CompatThreshold)
+	assert.Equal(t, EpochExecutorTypeSequential, opts.EpochExecutorType)
+	assert.Equal(t, GenomeCompatibilityMethodLinear, opts.GenCompatMethod)
+	assert.Equal(t, []math.NodeActivationType{math.SigmoidSteepenedActivation}, opts.NodeActivators)
+}
+
+func TestOptions_Set(t *testing.T) {
+	opts := DefaultOptions()
+	require.NoError(t, opts.Set("compat_threshold", "1.5"))
+	assert.Equal(t, 1.5, opts.CompatThreshold)
+	require.NoError(t, opts.Set("pop_size", " 150 "))
+	assert.Equal(t, 150, opts.PopSize)
+	require.NoError(t, opts.Set("genome_compat_method", "fast"))
+	assert.Equal(t, GenomeCompatibilityMethodFast, opts.GenCompatMethod)
+
+	require.NoError(t, opts.Set("node_activators", "SigmoidBipolarActivation 0.25,  SineActivation   0.75"))
+	assert.Equal(t, []string{"SigmoidBipolarActivation 0.25", "SineActivation 0.75"}, opts.NodeActivatorsWithProbs)
+	assert.Equal(t, []math.NodeActivationType{math.SigmoidBipolarActivation, math.SineActivation}, opts.NodeActivators)
+	assert.Equal(t, []float64{0.25, 0.75}, opts.NodeActivatorsProb)
+	value, err := opts.Get("node_activators")
+	require.NoError(t, err)
+	assert.Equal(t, "SigmoidBipolarActivation 0.25, SineActivation 0.75", value)
+
+	assert.Error(t, opts.Set("unknown", "1"))
+	assert.Error(t, opts.Set("pop_size", "1.5"))
+	assert.Error(t, opts.Set("compat_threshold", "high"))
+	assert.Error(t, opts.Set("node_activators", "UnknownActivation 1.0"))
+	assert.Error(t, opts.Set("node_activators", "SineActivation"))
+}
+
+func TestOptionValueString(t *testing.T) {
+	assert.Equal(t, "0.1", OptionValueString(0.1))
+	assert.Equal(t, "3", OptionValueString(3.0))
+	assert.Equal(t, "150", OptionValueString(150))
+	assert.Equal(t, "fast", OptionValueString("fast"))
+	assert.Equal(t, "SigmoidBipolarActivation 0.25, SineActivation 0.75",
+		OptionValueString([]interface{}{"SigmoidBipolarActivation 0.25", "SineActivation 0.75"}))
+}
+
+func TestOptions_Validate(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MutateAddNodeProb = -0.1
+	opts.SurvivalThresh = 1.5
+	opts.PopSize = 0
+	opts.EpochExecutorType = "unknown"
+	opts.LogLevel = "verbose"
+	opts.NodeActivatorsProb = []float64{2}
+
+	err := opts.Validate()
+	require.Error(t, err)
+	vErr, ok := err.(*ValidationError)
+	require.True(t, ok, "validation error expected")
+	assert.Len(t, vErr.Violations, 6)
+	for _, name := range []string{"mutate_add_node_prob", "survival_thresh", "pop_size", "epoch_executor", "log_level", "node_activators"} {
+		assert.Contains(t, err.Error(), name+":")
+	}
+}
+
+func TestOptions_SetFromFlags(t *testing.T) {
+	opts := DefaultOptions()
+	err := opts.SetFromFlags([]string{"pop_size=50", "log_level=warn"})
+	require.NoError(t, err)
+	assert.Equal(t, 50, opts.PopSize)
+	assert.Equal(t, "warn", opts.LogLevel)
+
+	assert.Error(t, opts.SetFromFlags([]string{"pop_size"}))
+	assert.Error(t, opts.SetFromFlags([]string{"unknown=1"}))
+
+	var overrides OptionsOverrides
+	require.NoError(t, overrides.Set("num_runs=3"))
+	assert.Error(t, overrides.Set("num_runs"))
+	assert.Error(t, overrides.Set("unknown=3"))
+	assert.Equal(t, "num_runs=3", overrides.String())
+}
+
+func TestOptions_SetFromEnvironment(t *testing.T) {
+	require.NoError(t, os.Setenv("TEST_NEAT_NUM_GENERATIONS", "42"))
+	defer func() {
+		_ = os.Unsetenv("TEST_NEAT_NUM_GENERATIONS")
+	}()
+	opts := DefaultOptions()
+	require.NoError(t, opts.SetFromEnvironment("TEST_NEAT_"))
+	assert.Equal(t, 42, opts.NumGenerations)
+}
+
+func TestLoadNeatOptions_partial(t *testing.T) {
+	config := "# comment line\n\npop_size 10\nnode_activators SineActivation 0.5, LinearActivation 0.5\n"
+	opts, err := LoadNeatOptions(strings.NewReader(config))
+	require.NoError(t, err)
+	assert.Equal(t, 10, opts.PopSize)
+	assert.Equal(t, DefaultOptions().CompatThreshold, opts.CompatThreshold)
+	assert.Equal(t, []math.NodeActivationType{math.SineActivation, math.LinearActivation}, opts.NodeActivators)
+
+	_, err = LoadNeatOptions(strings.NewReader("pop_size 10\nunknown_param 1\n"))
+	assert.Error(t, err)
+	_, err = LoadNeatOptions(strings.NewReader("pop_size -1\nsurvival_thresh 2\n"))
+	require.Error(t, err)
+	assert.Len(t, err.(*ValidationError).Violations, 2)
+}
+
+func TestOptions_Write_nodeActivators(t *testing.T) {
+	config, err := os.Open("../data/xor_test.neat.yml")
+	require.NoError(t, err)
+	opts, err := LoadYAMLOptions(config)
+	require.NoError(t, err)
+	require.Len(t, opts.NodeActivators, 4)
+
+	var buf bytes.Buffer
+	err = opts.Write(&buf)
+	require.NoError(t, err, "failed to write options")
+
+	written, err := LoadNeatOptions(&buf)
+	require.NoError(t, err, "failed to load written options")
+	assert.Equal(t, opts, written)
+}
@@ -62,9 +62,14 @@ func newTrait(length int) *Trait {
 
 // Mutate perturb the trait parameters slightly
 func (t *Trait) Mutate(traitMutationPower, traitParamMutProb float64) {
+	t.MutateWith(math.GlobalRand(), traitMutationPower, traitParamMutProb)
+}
+
+// MutateWith perturb the trait parameters slightly using provided generator of random numbers
+func (t *Trait) MutateWith(rng *rand.Rand, traitMutationPower, traitParamMutProb float64) {
 	for i := 0; i < len(t.Params); i++ {
-		if rand.Float64() > traitParamMutProb {
-			t.Params[i] += float64(math.RandSign()) * rand.Float64() * traitMutationPower
+		if rng.Float64() > traitParamMutProb {
+			t.Params[i] += float64(math.RandSignWith(rng)) * rng.Float64() * traitMutationPower
 			if t.Params[i] < 0 {
 				t.Params[i] = 0
 			}